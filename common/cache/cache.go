@@ -7,6 +7,7 @@ import (
 // Cache 定义缓存操作的通用接口
 type Cache interface {
 	SetKeyEx(key string, value string, timeout time.Duration) error
+	SetKeyNX(key string, value string, timeout time.Duration) (bool, error) // key不存在时才写入，返回是否写入成功
 	GetKey(key string) (string, error)
 	GetKeyNilIsErr(key string) (string, error)
 	GetKeyWithPrefixNilIsErr(prefix string) (string, error)
@@ -16,6 +17,18 @@ type Cache interface {
 	DelKeysWithPrefix(prefix string) error
 	DelKeysWithSuffix(suffix string) error
 	DeleteAllRedisKeys() error
+	ExpireKey(key string, timeout time.Duration) error
+	IncrKeyEx(key string, timeout time.Duration) (int64, error) // 计数加一，首次创建时设置过期时间，返回加一后的值
+	GetKeys(keys []string) ([]string, error) // 批量获取，不存在的key返回空字符串
+	AddToSet(key string, members ...string) error
+	RemoveFromSet(key string, members ...string) error
+	GetSetMembers(key string) ([]string, error)
+	PopSetMembers(key string, count int64) ([]string, error) // 随机弹出最多count个成员
+	GetHashAll(key string) (map[string]string, error)
+	SetHashField(key, field, value string) error
+	SetHashFieldNX(key, field, value string) (bool, error) // field不存在时才写入，返回是否写入成功
+	IncrHashField(key, field string, delta int64) (int64, error)
+	DelHashField(key string, fields ...string) error
 }
 
 // 全局缓存实例
@@ -32,4 +45,4 @@ func GetGlobalCache() Cache {
 		panic("cache not initialized: call Init() first")
 	}
 	return myCache
-}
\ No newline at end of file
+}
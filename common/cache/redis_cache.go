@@ -40,6 +40,10 @@ func (rc *RedisCache)SetKeyEx(key string, value string, timeout time.Duration) e
 	return nil
 }
 
+func (rc *RedisCache)SetKeyNX(key string, value string, timeout time.Duration) (bool, error) {
+	return rc.client.SetNX(rc.ctx, key, value, timeout).Result()
+}
+
 func (rc *RedisCache)GetKey(key string) (string, error) {
 	value, err := rc.client.Get(rc.ctx, key).Result()
 	if err != nil {
@@ -244,4 +248,86 @@ func (rc *RedisCache)DeleteAllRedisKeys() error {
 	return nil
 }
 
+func (rc *RedisCache)ExpireKey(key string, timeout time.Duration) error {
+	return rc.client.Expire(rc.ctx, key, timeout).Err()
+}
+
+func (rc *RedisCache)IncrKeyEx(key string, timeout time.Duration) (int64, error) {
+	cnt, err := rc.client.Incr(rc.ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if cnt == 1 {
+		if err := rc.client.Expire(rc.ctx, key, timeout).Err(); err != nil {
+			return cnt, err
+		}
+	}
+	return cnt, nil
+}
 
+func (rc *RedisCache)GetKeys(keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return []string{}, nil
+	}
+	values, err := rc.client.MGet(rc.ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp := make([]string, len(values))
+	for i, value := range values {
+		if str, ok := value.(string); ok {
+			rsp[i] = str
+		}
+	}
+	return rsp, nil
+}
+
+func (rc *RedisCache)AddToSet(key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return rc.client.SAdd(rc.ctx, key, args...).Err()
+}
+
+func (rc *RedisCache)RemoveFromSet(key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return rc.client.SRem(rc.ctx, key, args...).Err()
+}
+
+func (rc *RedisCache)GetSetMembers(key string) ([]string, error) {
+	return rc.client.SMembers(rc.ctx, key).Result()
+}
+
+func (rc *RedisCache)PopSetMembers(key string, count int64) ([]string, error) {
+	return rc.client.SPopN(rc.ctx, key, count).Result()
+}
+
+func (rc *RedisCache)GetHashAll(key string) (map[string]string, error) {
+	return rc.client.HGetAll(rc.ctx, key).Result()
+}
+
+func (rc *RedisCache)SetHashField(key, field, value string) error {
+	return rc.client.HSet(rc.ctx, key, field, value).Err()
+}
+
+func (rc *RedisCache)SetHashFieldNX(key, field, value string) (bool, error) {
+	return rc.client.HSetNX(rc.ctx, key, field, value).Result()
+}
+
+func (rc *RedisCache)IncrHashField(key, field string, delta int64) (int64, error) {
+	return rc.client.HIncrBy(rc.ctx, key, field, delta).Result()
+}
+
+func (rc *RedisCache)DelHashField(key string, fields ...string) error {
+	return rc.client.HDel(rc.ctx, key, fields...).Err()
+}
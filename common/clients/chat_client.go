@@ -0,0 +1,154 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	chatpb "github.com/puoxiu/gogochat/services/chat_service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type ChatClient struct {
+	client chatpb.ChatServiceClient
+	conn   *grpc.ClientConn
+}
+
+// 用户的设备可能连在不同的chat_service实例上，推送事件时需要发给所有实例
+// key为实例地址，由SyncChatClients按etcd中的地址列表维护
+var (
+	chatClients      = make(map[string]*ChatClient)
+	chatClientsMutex sync.RWMutex
+)
+
+func NewChatClient(addr string) (*ChatClient, error) {
+	conn, err := grpc.Dial(
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+		grpc.WithTimeout(5*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("连接聊天服务失败: %v", err)
+	}
+
+	client := chatpb.NewChatServiceClient(conn)
+	return &ChatClient{client: client, conn: conn}, nil
+}
+
+// SyncChatClients 为新出现的chat_service实例建立连接，关闭已不在列表中的实例的连接
+// 返回连接失败的实例的错误，已建立的连接不受影响，下次同步时重试
+func SyncChatClients(addrs []string) error {
+	wanted := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		wanted[addr] = true
+	}
+	chatClientsMutex.Lock()
+	for addr, client := range chatClients {
+		if wanted[addr] {
+			continue
+		}
+		delete(chatClients, addr)
+		if err := client.Close(); err != nil {
+			zlog.Warn(fmt.Sprintf("关闭聊天服务%s的连接失败: %v", addr, err))
+		}
+		zlog.Info("聊天服务实例已下线: " + addr)
+	}
+	var missing []string
+	for addr := range wanted {
+		if _, ok := chatClients[addr]; !ok {
+			missing = append(missing, addr)
+		}
+	}
+	chatClientsMutex.Unlock()
+
+	// 建立连接可能阻塞数秒，不持有锁
+	var errs []error
+	for _, addr := range missing {
+		client, err := NewChatClient(addr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", addr, err))
+			continue
+		}
+		chatClientsMutex.Lock()
+		chatClients[addr] = client
+		chatClientsMutex.Unlock()
+		zlog.Info("连接聊天服务实例成功: " + addr)
+	}
+	return errors.Join(errs...)
+}
+
+// GetChatClients 返回所有已连接的chat_service实例的客户端
+func GetChatClients() []*ChatClient {
+	chatClientsMutex.RLock()
+	defer chatClientsMutex.RUnlock()
+	clientList := make([]*ChatClient, 0, len(chatClients))
+	for _, client := range chatClients {
+		clientList = append(clientList, client)
+	}
+	return clientList
+}
+
+// BroadcastUserEvent 向所有chat_service实例推送事件，由各实例推送给用户连在本实例上的设备
+// 部分实例推送失败时只记录日志，所有实例都失败或没有可用实例时返回错误
+func BroadcastUserEvent(userId, event string) error {
+	clientList := GetChatClients()
+	if len(clientList) == 0 {
+		return errors.New("没有可用的聊天服务实例")
+	}
+	var wg sync.WaitGroup
+	var succeeded int32
+	for _, client := range clientList {
+		wg.Add(1)
+		go func(client *ChatClient) {
+			defer wg.Done()
+			if rsp := client.PushUserEvent(userId, event); rsp != nil && rsp.Code == 0 {
+				atomic.AddInt32(&succeeded, 1)
+				return
+			}
+			zlog.Warn(fmt.Sprintf("向聊天服务%s推送事件失败, user_id: %s", client.conn.Target(), userId))
+		}(client)
+	}
+	wg.Wait()
+	if succeeded == 0 {
+		return errors.New("所有聊天服务实例推送失败")
+	}
+	return nil
+}
+
+// CloseChatClients 关闭所有chat_service实例的连接
+func CloseChatClients() {
+	chatClientsMutex.Lock()
+	defer chatClientsMutex.Unlock()
+	for addr, client := range chatClients {
+		if err := client.Close(); err != nil {
+			zlog.Warn(fmt.Sprintf("关闭聊天服务%s的连接失败: %v", addr, err))
+		}
+		delete(chatClients, addr)
+	}
+}
+
+// PushUserEvent 向用户在线的连接推送事件
+func (cc *ChatClient) PushUserEvent(userId, event string) *chatpb.PushUserEventResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	resp, _ := cc.client.PushUserEvent(ctx, &chatpb.PushUserEventRequest{
+		UserId: userId,
+		Event:  event,
+	})
+	return resp
+}
+
+// Close 关闭连接
+func (cc *ChatClient) Close() error {
+	if cc.conn != nil {
+		return cc.conn.Close()
+	}
+	return nil
+}
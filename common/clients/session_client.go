@@ -87,6 +87,20 @@ func (sc *SessionClient) CreateSessionIfNotExist(sendId, receiveId string) *sess
 	return resp
 }
 
+// UpdateLastMessage 更新会话的最新消息
+func (sc *SessionClient) UpdateLastMessage(sendId, receiveId, lastMessage string, lastMessageAt time.Time) *sessionpb.UpdateLastMessageResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	resp, _ := sc.client.UpdateLastMessage(ctx, &sessionpb.UpdateLastMessageRequest{
+		SendId:        sendId,
+		ReceiveId:     receiveId,
+		LastMessage:   lastMessage,
+		LastMessageAt: lastMessageAt.UnixMilli(),
+	})
+	return resp
+}
+
 // Close 关闭连接
 func (sc *SessionClient) Close() error {
 	if sc.conn != nil {
@@ -102,7 +102,48 @@ func (uc *UserClient) GetContactStatus(userId string, contactId string) (*userpb
 	return resp
 }
 
+// RemoveGroupMembers 调用用户服务移除群成员
+func (uc *UserClient) RemoveGroupMembers(ownerId, groupId string, uuidList []string) (*userpb.GroupOperationResponse) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	resp, _ := uc.client.RemoveGroupMembers(ctx, &userpb.RemoveGroupMembersRequest{
+		OwnerId:  ownerId,
+		GroupId:  groupId,
+		UuidList: uuidList,
+	})
+
+	return resp
+}
+
+// UpdateGroupNotice 调用用户服务修改群公告
+func (uc *UserClient) UpdateGroupNotice(ownerId, groupId, notice string) (*userpb.GroupOperationResponse) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	resp, _ := uc.client.UpdateGroupNotice(ctx, &userpb.UpdateGroupNoticeRequest{
+		OwnerId: ownerId,
+		GroupId: groupId,
+		Notice:  notice,
+	})
+
+	return resp
+}
 
+// MuteGroupMember 调用用户服务禁言群成员，minutes为0表示解除禁言
+func (uc *UserClient) MuteGroupMember(ownerId, groupId, userId string, minutes int) (*userpb.GroupOperationResponse) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	resp, _ := uc.client.MuteGroupMember(ctx, &userpb.MuteGroupMemberRequest{
+		OwnerId: ownerId,
+		GroupId: groupId,
+		UserId:  userId,
+		Minutes: int32(minutes),
+	})
+
+	return resp
+}
 
 // Close 关闭GRPC连接，释放资源
 // 在程序退出时调用（如main函数的defer中）
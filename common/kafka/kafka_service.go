@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/puoxiu/gogochat/pkg/zlog"
@@ -14,6 +15,11 @@ type kafkaService struct {
 	ChatWriter *kafka.Writer
 	ChatReader *kafka.Reader
 	KafkaConn  *kafka.Conn
+	// 在线状态变更，上线/离开写入login主题，离线写入logout主题
+	LoginWriter  *kafka.Writer
+	LogoutWriter *kafka.Writer
+	LoginReader  *kafka.Reader
+	LogoutReader *kafka.Reader
 	// 保存基础配置用于关闭连接
 	addr string
 }
@@ -48,6 +54,37 @@ func (k *kafkaService) Init(addr, topic string, timeout time.Duration, groupID s
 	})
 }
 
+// InitPresence 初始化在线状态的读写器
+// 在线状态需要广播给所有聊天实例，groupID应当每个实例唯一
+func (k *kafkaService) InitPresence(addr, loginTopic, logoutTopic string, timeout time.Duration, groupID string) {
+	k.LoginWriter = &kafka.Writer{
+		Addr:         kafka.TCP(addr),
+		Topic:        loginTopic,
+		Balancer:     &kafka.Hash{},
+		WriteTimeout: timeout,
+		RequiredAcks: kafka.RequireOne,
+	}
+	k.LogoutWriter = &kafka.Writer{
+		Addr:         kafka.TCP(addr),
+		Topic:        logoutTopic,
+		Balancer:     &kafka.Hash{},
+		WriteTimeout: timeout,
+		RequiredAcks: kafka.RequireOne,
+	}
+	k.LoginReader = kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{addr},
+		Topic:       loginTopic,
+		GroupID:     groupID,
+		StartOffset: kafka.LastOffset,
+	})
+	k.LogoutReader = kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{addr},
+		Topic:       logoutTopic,
+		GroupID:     groupID,
+		StartOffset: kafka.LastOffset,
+	})
+}
+
 // Close 关闭kafka连接
 func (k *kafkaService) Close() {
 	if err := k.ChatWriter.Close(); err != nil {
@@ -56,6 +93,20 @@ func (k *kafkaService) Close() {
 	if err := k.ChatReader.Close(); err != nil {
 		zlog.Error(err.Error())
 	}
+	for _, writer := range []*kafka.Writer{k.LoginWriter, k.LogoutWriter} {
+		if writer != nil {
+			if err := writer.Close(); err != nil {
+				zlog.Error(err.Error())
+			}
+		}
+	}
+	for _, reader := range []*kafka.Reader{k.LoginReader, k.LogoutReader} {
+		if reader != nil {
+			if err := reader.Close(); err != nil {
+				zlog.Error(err.Error())
+			}
+		}
+	}
 	if k.KafkaConn != nil {
 		if err := k.KafkaConn.Close(); err != nil {
 			zlog.Error(err.Error())
@@ -91,4 +142,40 @@ func (k *kafkaService) CreateTopic(addr, topic string, partitions int) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// EnsurePartitions 保证已存在的topic至少有partitions个分区
+// CreateTopic对已存在的topic直接失败，调大配置中的分区数需要在这里显式扩容
+// 分区只能增加不能减少；扩容后部分会话的key会哈希到新分区，扩容瞬间在途的消息可能乱序
+func (k *kafkaService) EnsurePartitions(addr, topic string, partitions int) error {
+	conn, err := kafka.Dial("tcp", addr)
+	if err != nil {
+		zlog.Error("连接Kafka失败: " + err.Error())
+		return err
+	}
+	defer conn.Close()
+	current, err := conn.ReadPartitions(topic)
+	if err != nil {
+		zlog.Error("读取Topic分区失败: " + err.Error())
+		return err
+	}
+	if len(current) >= partitions {
+		return nil
+	}
+	client := &kafka.Client{Addr: kafka.TCP(addr), Timeout: 10 * time.Second}
+	rsp, err := client.CreatePartitions(ctx, &kafka.CreatePartitionsRequest{
+		Addr: kafka.TCP(addr),
+		Topics: []kafka.TopicPartitionsConfig{
+			{Name: topic, Count: int32(partitions)},
+		},
+	})
+	if err == nil {
+		err = rsp.Errors[topic]
+	}
+	if err != nil {
+		zlog.Error("扩容Topic分区失败: " + err.Error())
+		return err
+	}
+	zlog.Info(fmt.Sprintf("Topic %s 分区数由%d扩容到%d", topic, len(current), partitions))
+	return nil
+}
@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+	_ "time/tzdata" // 容器中可能没有时区数据
+
+	"github.com/go-redis/redis/v8"
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+)
+
+// 免打扰设置由session_service维护，这里提供各服务共用的判断逻辑
+// 任何给用户发提醒的路径（websocket提醒事件、以后的离线推送）都要先调用Silenced
+const (
+	keyPrefix       = "notify_"
+	DefaultTimezone = "Asia/Shanghai"
+	clockLayout     = "15:04"
+)
+
+// Mute 单个会话的免打扰，单聊target_id为用户，群聊为群聊
+type Mute struct {
+	TargetId     string     `json:"target_id"`
+	MutedUntil   *time.Time `json:"muted_until,omitempty"` // 为空表示永久
+	AllowMention bool       `json:"allow_mention"`         // 被@时仍然提醒
+}
+
+// Dnd 全局免打扰时段，按用户所在时区计算，结束时间早于开始时间表示跨天
+type Dnd struct {
+	Enabled      bool   `json:"enabled"`
+	StartTime    string `json:"start_time"` // HH:MM
+	EndTime      string `json:"end_time"`   // HH:MM
+	Timezone     string `json:"timezone"`
+	AllowMention bool   `json:"allow_mention"`
+}
+
+// Settings 用户的全部免打扰设置，key为会话对方的uuid
+type Settings struct {
+	Mutes map[string]Mute `json:"mutes"`
+	Dnd   *Dnd            `json:"dnd,omitempty"`
+}
+
+// Loader 从数据库读取用户的免打扰设置
+type Loader func(userId string) (*Settings, error)
+
+// Key 用户免打扰设置的redis键
+func Key(userId string) string {
+	return keyPrefix + userId
+}
+
+// Load 查询用户的免打扰设置，优先读缓存
+func Load(userId string, load Loader) (*Settings, error) {
+	rspString, err := cache.GetGlobalCache().GetKeyNilIsErr(Key(userId))
+	if err == nil {
+		var settings Settings
+		if err := json.Unmarshal([]byte(rspString), &settings); err == nil {
+			return &settings, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		zlog.Warn("读取免打扰设置缓存失败: " + err.Error())
+	}
+	settings, err := load(userId)
+	if err != nil {
+		return nil, err
+	}
+	if settingsByte, err := json.Marshal(settings); err == nil {
+		if err := cache.GetGlobalCache().SetKeyEx(Key(userId), string(settingsByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
+			zlog.Warn("缓存免打扰设置失败: " + err.Error())
+		}
+	}
+	return settings, nil
+}
+
+// Invalidate 设置变化后删除缓存
+func Invalidate(userId string) error {
+	return cache.GetGlobalCache().DelKeyIfExists(Key(userId))
+}
+
+// MuteOf 返回会话当前生效的免打扰，已过期的不算
+func (s *Settings) MuteOf(targetId string, now time.Time) (Mute, bool) {
+	mute, ok := s.Mutes[targetId]
+	if !ok {
+		return Mute{}, false
+	}
+	if mute.MutedUntil != nil && !now.Before(*mute.MutedUntil) {
+		return Mute{}, false
+	}
+	return mute, true
+}
+
+// Active 判断当前是否处于免打扰时段，开始和结束相同表示全天
+func (d *Dnd) Active(now time.Time) bool {
+	if d == nil || !d.Enabled {
+		return false
+	}
+	start, err := ParseClock(d.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := ParseClock(d.EndTime)
+	if err != nil {
+		return false
+	}
+	loc, err := LoadLocation(d.Timezone)
+	if err != nil {
+		return false
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	if start == end {
+		return true
+	}
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// Silenced 判断会话来新消息时是否应该静默，被@且设置了@仍提醒时不静默
+func (s *Settings) Silenced(targetId string, mentioned bool, now time.Time) bool {
+	if s == nil {
+		return false
+	}
+	if mute, ok := s.MuteOf(targetId, now); ok && !(mentioned && mute.AllowMention) {
+		return true
+	}
+	if s.Dnd.Active(now) && !(mentioned && s.Dnd.AllowMention) {
+		return true
+	}
+	return false
+}
+
+// ParseClock 解析HH:MM，返回当天的分钟数
+func ParseClock(clock string) (int, error) {
+	t, err := time.Parse(clockLayout, clock)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// LoadLocation 解析时区，为空时使用默认时区
+func LoadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	return time.LoadLocation(timezone)
+}
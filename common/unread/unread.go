@@ -0,0 +1,158 @@
+package unread
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+)
+
+// 未读数保存在redis的hash中，field为会话对方的uuid（单聊为用户，群聊为群聊），value为未读数
+// 变化过的用户记录在DirtyKey中，由chat_service定期写回数据库；redis中没有时从数据库加载
+// 每次变化后刷新过期时间，长期没有变化的用户过期后再从数据库加载，写回间隔远小于过期时间
+const (
+	keyPrefix   = "unread_"
+	DirtyKey    = "unread_dirty"
+	loadedField = "_loaded" // 标记已从数据库加载，避免没有未读的用户每次都查库
+	keyTTL      = 7 * 24 * time.Hour
+)
+
+// Loader 从数据库读取用户持久化的未读数，key为会话对方的uuid
+type Loader func(userId string) (map[string]int, error)
+
+// Key 用户未读数的redis键
+func Key(userId string) string {
+	return keyPrefix + userId
+}
+
+// Counts 查询用户所有会话的未读数，redis中没有时先从数据库加载
+func Counts(userId string, load Loader) (map[string]int, error) {
+	values, err := cache.GetGlobalCache().GetHashAll(Key(userId))
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		if err := ensureLoaded(userId, load); err != nil {
+			return nil, err
+		}
+		if values, err = cache.GetGlobalCache().GetHashAll(Key(userId)); err != nil {
+			return nil, err
+		}
+	}
+	counts := make(map[string]int, len(values))
+	for field, value := range values {
+		if field == loadedField {
+			continue
+		}
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			counts[field] = n
+		}
+	}
+	return counts, nil
+}
+
+// Incr 会话未读数加一，返回加一后的值
+func Incr(userId, targetId string, load Loader) (int, error) {
+	if err := ensureLoaded(userId, load); err != nil {
+		return 0, err
+	}
+	n, err := cache.GetGlobalCache().IncrHashField(Key(userId), targetId, 1)
+	if err != nil {
+		return 0, err
+	}
+	markDirty(userId)
+	touch(userId)
+	return int(n), nil
+}
+
+// Reset 会话未读数清零
+func Reset(userId, targetId string, load Loader) error {
+	if err := ensureLoaded(userId, load); err != nil {
+		return err
+	}
+	if err := cache.GetGlobalCache().SetHashField(Key(userId), targetId, "0"); err != nil {
+		return err
+	}
+	markDirty(userId)
+	touch(userId)
+	return nil
+}
+
+// Total 所有会话未读数之和，用于角标
+func Total(counts map[string]int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
+
+// ensureLoaded redis中没有用户的未读数时从数据库加载
+// 加载时只写入不存在的field，不会覆盖并发写入的新值
+func ensureLoaded(userId string, load Loader) error {
+	loaded, err := cache.GetGlobalCache().GetHashAll(Key(userId))
+	if err != nil {
+		return err
+	}
+	if len(loaded) > 0 {
+		return nil
+	}
+	counts, err := load(userId)
+	if err != nil {
+		return err
+	}
+	for targetId, n := range counts {
+		if _, err := cache.GetGlobalCache().SetHashFieldNX(Key(userId), targetId, strconv.Itoa(n)); err != nil {
+			return err
+		}
+	}
+	if _, err = cache.GetGlobalCache().SetHashFieldNX(Key(userId), loadedField, "1"); err != nil {
+		return err
+	}
+	touch(userId)
+	return nil
+}
+
+// touch 刷新用户未读数的过期时间，失败时只会提前过期，过期后从数据库重新加载
+func touch(userId string) {
+	if err := cache.GetGlobalCache().ExpireKey(Key(userId), keyTTL); err != nil {
+		zlog.Warn("刷新未读数过期时间失败: " + err.Error())
+	}
+}
+
+// MarkDirty 记录未读数有变化的用户，等待写回数据库
+func MarkDirty(userId string) error {
+	return cache.GetGlobalCache().AddToSet(DirtyKey, userId)
+}
+
+// markDirty 与MarkDirty相同，失败时只影响持久化的及时性，不影响未读数本身
+func markDirty(userId string) {
+	if err := MarkDirty(userId); err != nil {
+		zlog.Warn("记录未读数变化失败: " + err.Error())
+	}
+}
+
+// PopDirty 取出一批待写回数据库的用户，多个实例同时调用时不会取到同一个用户
+func PopDirty(count int64) ([]string, error) {
+	return cache.GetGlobalCache().PopSetMembers(DirtyKey, count)
+}
+
+// Snapshot 读取redis中用户的未读数用于写回数据库，包括已清零的会话
+// ok为false表示redis中没有该用户的数据
+func Snapshot(userId string) (counts map[string]int, ok bool, err error) {
+	values, err := cache.GetGlobalCache().GetHashAll(Key(userId))
+	if err != nil || len(values) == 0 {
+		return nil, false, err
+	}
+	counts = make(map[string]int, len(values))
+	for field, value := range values {
+		if field == loadedField {
+			continue
+		}
+		if n, err := strconv.Atoi(value); err == nil {
+			counts[field] = n
+		}
+	}
+	return counts, true, nil
+}
@@ -0,0 +1,36 @@
+package bottoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// tokenPrefix 机器人令牌的前缀，便于在日志和代码扫描中识别泄露的令牌
+const tokenPrefix = "bot_"
+
+// Generate 生成机器人令牌，返回明文令牌和需要保存到数据库的哈希
+// 明文令牌只在创建或重置时返回给机器人的创建者一次
+func Generate() (string, string, error) {
+	token, err := RandomHex(24)
+	if err != nil {
+		return "", "", err
+	}
+	token = tokenPrefix + token
+	return token, Hash(token), nil
+}
+
+// Hash 计算令牌的哈希，鉴权时按哈希查找机器人
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RandomHex 生成n字节的随机数并以十六进制返回，用于令牌和webhook签名密钥
+func RandomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,8 +1,24 @@
 package constants
 
 const (
-	CHANNEL_SIZE  = 100            // 通道大小
-	SYSTEM_ERROR  = "系统错误，请联系工作人员" // 系统错误
-	FILE_MAX_SIZE = 50000          // 文件最大大小
-	REDIS_TIMEOUT = 1              // redis timeout
+	CHANNEL_SIZE           = 100            // 通道大小
+	SYSTEM_ERROR           = "系统错误，请联系工作人员" // 系统错误
+	FILE_MAX_SIZE          = 50000          // 文件最大大小
+	REDIS_TIMEOUT          = 1              // redis timeout
+	DEFAULT_PAGE_SIZE      = 20             // 分页默认每页条数
+	MAX_PAGE_SIZE          = 100            // 分页每页最大条数
+	MAX_FORWARD_TARGETS    = 9              // 一次转发的最大目标数
+	MAX_FORWARD_MESSAGES   = 100            // 一次转发的最大消息数
+	VOICE_MAX_DURATION     = 60             // 语音最大时长，单位秒
+	VOICE_MAX_SIZE         = 2 << 20        // 语音文件最大大小
+	VOICE_WAVEFORM_MAX_LEN = 128            // 语音波形最大采样数
+	MAX_PINNED_MESSAGES    = 10             // 每个会话最多置顶的消息数
+	MAX_SCHEDULED_MESSAGES = 50             // 每个用户最多待发送的定时消息数
+	MAX_SCHEDULE_DAYS      = 30             // 定时消息最远的发送时间，单位天
+	DRAFT_MAX_LEN          = 5000           // 草稿最大字符数
+	MAX_FAVORITE_TAGS      = 10             // 每条收藏最多的标签数
+	FAVORITE_TAG_MAX_LEN   = 20             // 收藏标签最大字符数
+	FAVORITE_NOTE_MAX_LEN  = 500            // 收藏备注最大字符数
+	ANNOUNCE_BATCH_SIZE    = 200            // 公告每批投递的用户数，批次之间间隔一秒
+	AI_ASSISTANT_ID        = "UAIASSISTANT" // AI助手的用户uuid，所有用户默认都可以和它聊天
 )
@@ -0,0 +1,13 @@
+package conversation
+
+// Id 生成会话的唯一标识，不区分发送方和接收方
+// 群聊为群聊uuid，单聊为两个用户uuid按字典序拼接
+func Id(ownerId, targetId string) string {
+	if targetId != "" && targetId[0] == 'G' {
+		return targetId
+	}
+	if ownerId < targetId {
+		return ownerId + "_" + targetId
+	}
+	return targetId + "_" + ownerId
+}
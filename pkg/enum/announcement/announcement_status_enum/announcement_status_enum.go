@@ -0,0 +1,10 @@
+package announcement_status_enum
+
+const (
+	// 等待发送
+	Pending = iota
+	// 已被某个实例领取，正在分批发送
+	Sending
+	// 已全部发送
+	Done
+)
@@ -0,0 +1,10 @@
+package announcement_target_enum
+
+const (
+	// 全部用户
+	All = iota
+	// 指定群聊的全部成员
+	Groups
+	// 按注册时间、状态等条件筛选的用户
+	Filter
+)
@@ -0,0 +1,8 @@
+package bot_delivery_enum
+
+const (
+	// 推送到机器人配置的https webhook
+	Webhook = iota
+	// 机器人通过gRPC流主动拉取
+	Stream
+)
@@ -0,0 +1,12 @@
+package bot_event_status_enum
+
+const (
+	// 等待投递
+	Pending = iota
+	// 已被某个实例领取，正在投递
+	Sending
+	// 已投递
+	Delivered
+	// 多次重试后仍失败
+	Failed
+)
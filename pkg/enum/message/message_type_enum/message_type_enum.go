@@ -8,4 +8,18 @@ const (
 	File
 	// 通话
 	AudioOrVideo
+	// 合并转发的聊天记录
+	MergedForward
+	// 图片
+	Image
+	// 位置
+	Location
+	// 名片，分享用户或群聊
+	ContactCard
+	// 表情包中的表情
+	Sticker
+	// 系统消息，如修改阅后即焚设置
+	System
+	// 群投票
+	Poll
 )
@@ -0,0 +1,14 @@
+package scheduled_status_enum
+
+const (
+	// 等待发送
+	Pending = iota
+	// 已被某个实例领取，正在发送
+	Sending
+	// 已发送
+	Sent
+	// 已取消
+	Canceled
+	// 发送失败
+	Failed
+)
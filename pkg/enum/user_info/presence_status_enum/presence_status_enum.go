@@ -0,0 +1,7 @@
+package presence_status_enum
+
+const (
+	OFFLINE = iota
+	ONLINE
+	AWAY
+)
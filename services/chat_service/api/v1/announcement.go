@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// CreateAnnouncement 管理员发布系统公告
+func CreateAnnouncement(c *gin.Context) {
+	var req request.CreateAnnouncementRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.AnnouncementService.CreateAnnouncement(req)
+	JsonBack(c, message, ret, rsp)
+}
+
+// GetAnnouncementProgress 查询公告发送进度
+func GetAnnouncementProgress(c *gin.Context) {
+	var req request.GetAnnouncementProgressRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.AnnouncementService.GetAnnouncementProgress(req.OwnerId, req.AnnouncementId)
+	JsonBack(c, message, ret, rsp)
+}
+
+// GetMyAnnouncementList 获取自己收到的公告
+func GetMyAnnouncementList(c *gin.Context) {
+	var req request.GetMyAnnouncementListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.AnnouncementService.GetMyAnnouncementList(req.OwnerId, req.Page, req.PageSize)
+	JsonBack(c, message, ret, rsp)
+}
+
+// ReadAnnouncement 标记公告已读
+func ReadAnnouncement(c *gin.Context) {
+	var req request.ReadAnnouncementRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.AnnouncementService.ReadAnnouncement(req.OwnerId, req.AnnouncementIds)
+	JsonBack(c, message, ret, nil)
+}
@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// BotSendMessage 机器人发送消息，令牌通过Authorization: Bearer <token>传递
+func BotSendMessage(c *gin.Context) {
+	var req request.BotSendMessageRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	message, ret := services.BotService.SendMessage(token, req)
+	JsonBack(c, message, ret, nil)
+}
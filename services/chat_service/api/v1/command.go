@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// GetCommandList 获取群聊中可用的斜杠命令
+func GetCommandList(c *gin.Context) {
+	var req request.GetCommandListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.CommandService.GetCommandList(req)
+	JsonBack(c, message, ret, rsp)
+}
+
+// BotRegisterCommand 机器人在群聊中注册命令，令牌通过Authorization: Bearer <token>传递
+func BotRegisterCommand(c *gin.Context) {
+	var req request.RegisterBotCommandRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	message, ret := services.CommandService.RegisterBotCommand(token, req)
+	JsonBack(c, message, ret, nil)
+}
+
+// BotUnregisterCommand 机器人注销群聊中的命令
+func BotUnregisterCommand(c *gin.Context) {
+	var req request.UnregisterBotCommandRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	message, ret := services.CommandService.UnregisterBotCommand(token, req)
+	JsonBack(c, message, ret, nil)
+}
+
+// BotGetCommandList 获取机器人在群聊中注册的命令
+func BotGetCommandList(c *gin.Context) {
+	var req request.GetBotCommandListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	message, rsp, ret := services.CommandService.GetBotCommandList(token, req)
+	JsonBack(c, message, ret, rsp)
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// SetDisappearTimer 设置会话的阅后即焚时长
+func SetDisappearTimer(c *gin.Context) {
+	var req request.SetDisappearTimerRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.DisappearService.SetDisappearTimer(req.OwnerId, req.TargetId, req.Duration)
+	JsonBack(c, message, ret, nil)
+}
+
+// GetDisappearTimer 获取会话的阅后即焚设置
+func GetDisappearTimer(c *gin.Context) {
+	var req request.GetDisappearTimerRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.DisappearService.GetDisappearTimer(req.OwnerId, req.TargetId)
+	JsonBack(c, message, ret, rsp)
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// SaveDraft 保存会话草稿
+func SaveDraft(c *gin.Context) {
+	var req request.SaveDraftRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.DraftService.SaveDraft(req.OwnerId, req.TargetId, req.Content)
+	JsonBack(c, message, ret, nil)
+}
+
+// GetDraftList 获取草稿列表
+func GetDraftList(c *gin.Context) {
+	var req request.GetDraftListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rspList, ret := services.DraftService.GetDraftList(req.OwnerId)
+	JsonBack(c, message, ret, rspList)
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// AddFavorite 收藏消息
+func AddFavorite(c *gin.Context) {
+	var req request.AddFavoriteRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.FavoriteService.AddFavorite(req)
+	JsonBack(c, message, ret, rsp)
+}
+
+// UpdateFavorite 修改收藏的标签和备注
+func UpdateFavorite(c *gin.Context) {
+	var req request.UpdateFavoriteRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.FavoriteService.UpdateFavorite(req)
+	JsonBack(c, message, ret, nil)
+}
+
+// DeleteFavorite 删除收藏
+func DeleteFavorite(c *gin.Context) {
+	var req request.DeleteFavoriteRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.FavoriteService.DeleteFavorite(req.OwnerId, req.FavoriteIds)
+	JsonBack(c, message, ret, nil)
+}
+
+// GetFavoriteList 获取收藏列表
+func GetFavoriteList(c *gin.Context) {
+	var req request.GetFavoriteListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.FavoriteService.GetFavoriteList(req)
+	JsonBack(c, message, ret, rsp)
+}
+
+// SearchFavorite 搜索收藏
+func SearchFavorite(c *gin.Context) {
+	var req request.SearchFavoriteRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.FavoriteService.SearchFavorite(req)
+	JsonBack(c, message, ret, rsp)
+}
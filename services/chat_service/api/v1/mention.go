@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// GetMentionList 获取@我的消息列表
+func GetMentionList(c *gin.Context) {
+	var req request.GetMentionListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rspList, ret := services.MentionService.GetMentionList(req.OwnerId, req.Page, req.PageSize)
+	JsonBack(c, message, ret, rspList)
+}
@@ -48,3 +48,23 @@ func UploadFile(c *gin.Context) {
 	message, ret := services.MessageService.UploadFile(c)
 	JsonBack(c, message, ret, nil)
 }
+
+// UploadVoice 上传语音
+func UploadVoice(c *gin.Context) {
+	message, rsp, ret := services.MessageService.UploadVoice(c)
+	JsonBack(c, message, ret, rsp)
+}
+
+// SearchMessage 搜索聊天记录
+func SearchMessage(c *gin.Context) {
+	var req request.SearchMessageRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.SearchService.SearchMessage(req)
+	JsonBack(c, message, ret, rsp)
+}
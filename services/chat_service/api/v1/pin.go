@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// PinMessage 置顶消息
+func PinMessage(c *gin.Context) {
+	var req request.PinMessageRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.PinService.PinMessage(req.OwnerId, req.MessageId)
+	JsonBack(c, message, ret, nil)
+}
+
+// UnpinMessage 取消置顶消息
+func UnpinMessage(c *gin.Context) {
+	var req request.PinMessageRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.PinService.UnpinMessage(req.OwnerId, req.MessageId)
+	JsonBack(c, message, ret, nil)
+}
+
+// GetPinnedMessageList 获取会话的置顶消息
+func GetPinnedMessageList(c *gin.Context) {
+	var req request.GetPinnedMessageListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rspList, ret := services.PinService.GetPinnedMessageList(req.OwnerId, req.TargetId)
+	JsonBack(c, message, ret, rspList)
+}
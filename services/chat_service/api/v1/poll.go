@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// VotePoll 群投票投票
+func VotePoll(c *gin.Context) {
+	var req request.VotePollRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.PollService.VotePoll(req.OwnerId, req.MessageId, req.Options)
+	JsonBack(c, message, ret, nil)
+}
+
+// ClosePoll 提前结束群投票
+func ClosePoll(c *gin.Context) {
+	var req request.ClosePollRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.PollService.ClosePoll(req.OwnerId, req.MessageId)
+	JsonBack(c, message, ret, nil)
+}
+
+// GetPollResult 获取群投票结果
+func GetPollResult(c *gin.Context) {
+	var req request.GetPollResultRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.PollService.GetPollResult(req.OwnerId, req.MessageId)
+	JsonBack(c, message, ret, rsp)
+}
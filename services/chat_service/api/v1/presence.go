@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// GetPresenceList 批量获取用户在线状态
+func GetPresenceList(c *gin.Context) {
+	var req request.GetPresenceListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rspList, ret := services.PresenceService.GetPresenceList(req.OwnerId, req.UserIds)
+	JsonBack(c, message, ret, rspList)
+}
+
+// SubscribePresence 订阅联系人在线状态
+func SubscribePresence(c *gin.Context) {
+	var req request.SubscribePresenceRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rspList, ret := services.PresenceService.SubscribePresence(req.OwnerId, req.UserIds)
+	JsonBack(c, message, ret, rspList)
+}
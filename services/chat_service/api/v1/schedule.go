@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// ScheduleMessage 创建定时消息
+func ScheduleMessage(c *gin.Context) {
+	var req request.ScheduleMessageRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.ScheduleService.ScheduleMessage(req)
+	JsonBack(c, message, ret, rsp)
+}
+
+// UpdateScheduledMessage 修改定时消息
+func UpdateScheduledMessage(c *gin.Context) {
+	var req request.UpdateScheduledMessageRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.ScheduleService.UpdateScheduledMessage(req)
+	JsonBack(c, message, ret, rsp)
+}
+
+// CancelScheduledMessage 取消定时消息
+func CancelScheduledMessage(c *gin.Context) {
+	var req request.CancelScheduledMessageRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.ScheduleService.CancelScheduledMessage(req.OwnerId, req.ScheduleId)
+	JsonBack(c, message, ret, nil)
+}
+
+// GetScheduledMessageList 获取定时消息列表
+func GetScheduledMessageList(c *gin.Context) {
+	var req request.GetScheduledMessageListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rspList, ret := services.ScheduleService.GetScheduledMessageList(req.OwnerId, req.Page, req.PageSize)
+	JsonBack(c, message, ret, rspList)
+}
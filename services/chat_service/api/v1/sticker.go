@@ -0,0 +1,12 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// GetStickerPackList 获取表情包列表
+func GetStickerPackList(c *gin.Context) {
+	message, rspList, ret := services.StickerService.GetStickerPackList()
+	JsonBack(c, message, ret, rspList)
+}
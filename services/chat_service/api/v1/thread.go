@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// GetThreadList 获取群聊话题列表
+func GetThreadList(c *gin.Context) {
+	var req request.GetThreadListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rspList, ret := services.ThreadService.GetThreadList(req.GroupId, req.OwnerId, req.Page, req.PageSize)
+	JsonBack(c, message, ret, rspList)
+}
+
+// GetThreadMessageList 获取话题回复列表
+func GetThreadMessageList(c *gin.Context) {
+	var req request.GetThreadMessageListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.ThreadService.GetThreadMessageList(req.ThreadId, req.Page, req.PageSize)
+	JsonBack(c, message, ret, rsp)
+}
+
+// ReadThread 话题已读
+func ReadThread(c *gin.Context) {
+	var req request.ReadThreadRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.ThreadService.ReadThread(req.OwnerId, req.ThreadId)
+	JsonBack(c, message, ret, nil)
+}
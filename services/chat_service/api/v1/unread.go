@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+)
+
+// ReadSession 清零会话未读数
+func ReadSession(c *gin.Context) {
+	var req request.ReadSessionRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.UnreadService.ReadSession(req)
+	JsonBack(c, message, ret, nil)
+}
+
+// GetUnreadTotal 获取未读角标
+func GetUnreadTotal(c *gin.Context) {
+	var req request.GetUnreadTotalRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.UnreadService.GetUnreadTotal(req)
+	JsonBack(c, message, ret, rsp)
+}
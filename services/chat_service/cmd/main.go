@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
@@ -16,9 +17,12 @@ import (
 
 	"github.com/puoxiu/gogochat/services/chat_service/internal/config"
 	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/grpc_server"
 	"github.com/puoxiu/gogochat/services/chat_service/internal/http_server"
 	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
-	// chat "github.com/puoxiu/gogochat/services/chat_service/proto"
+	chatpb "github.com/puoxiu/gogochat/services/chat_service/proto"
+
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -70,11 +74,64 @@ func main() {
 			config.AppConfig.KafkaConfig.Partition,
 		); err != nil {
 			zlog.Warn(fmt.Sprintf("创建 Topic 失败（可能已存在）: %v", err))
+			// topic已存在时按配置补齐分区数
+			if err := kafka.KafkaService.EnsurePartitions(
+				config.AppConfig.KafkaConfig.Address,
+				config.AppConfig.KafkaConfig.ChatTopic,
+				config.AppConfig.KafkaConfig.Partition,
+			); err != nil {
+				zlog.Warn(fmt.Sprintf("扩容 Topic 分区失败: %v", err))
+			}
+		}
+		for _, topic := range []string{config.AppConfig.KafkaConfig.LoginTopic, config.AppConfig.KafkaConfig.LogoutTopic} {
+			if err := kafka.KafkaService.CreateTopic(config.AppConfig.KafkaConfig.Address, topic, 1); err != nil {
+				zlog.Warn(fmt.Sprintf("创建 Topic 失败（可能已存在）: %v", err))
+			}
 		}
+		// 在线状态需要广播到所有实例，每个实例使用独立的消费组
+		kafka.KafkaService.InitPresence(
+			config.AppConfig.KafkaConfig.Address,
+			config.AppConfig.KafkaConfig.LoginTopic,
+			config.AppConfig.KafkaConfig.LogoutTopic,
+			time.Duration(config.AppConfig.KafkaConfig.Timeout)*time.Second,
+			fmt.Sprintf("presence_%s_%d", config.AppConfig.MainConfig.Host, config.AppConfig.MainConfig.GrpcPort),
+		)
 		go chat.KafkaChatServer.Start()
+		chat.StartPresenceConsumer()
 	}
-	
-	// 启动 gRPC 服务
+	// 定时消息调度器，多实例同时运行时由数据库条件更新保证每条消息只投递一次
+	chat.StartScheduler()
+	// 阅后即焚过期消息清理
+	chat.StartDisappearSweeper()
+	// 文本消息链接预览抓取
+	chat.StartLinkPreviewWorker()
+	// 系统公告分批投递，多实例时每条公告只由一个实例投递
+	chat.StartAnnouncementWorker()
+	// 机器人事件生成和webhook投递
+	chat.StartBotWorker()
+	// AI助手流式回复
+	chat.StartAssistantWorker()
+	// 更新会话列表的最新消息和未读数
+	chat.StartLastMessageWorker()
+	// 未读数定期写回数据库
+	chat.StartUnreadFlusher()
+
+	// 启动 gRPC 服务，机器人通过它接收事件流和发送消息
+	go func() {
+		addr := fmt.Sprintf(":%d", config.AppConfig.MainConfig.GrpcPort)
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			zlog.Fatal(fmt.Sprintf("启动chat gRPC 服务失败: %v", err))
+		}
+
+		s := grpc.NewServer()
+		chatpb.RegisterChatServiceServer(s, &grpc_server.ChatGrpcServer{})
+		zlog.Info(fmt.Sprintf("chat gRPC 服务启动成功，端口：%s", addr))
+
+		if err := s.Serve(lis); err != nil {
+			zlog.Fatal(fmt.Sprintf("chat gRPC 服务运行失败: %v", err))
+		}
+	}()
 
 	// 启动 HTTP 服务
 	go func() {
@@ -15,6 +15,7 @@ type Config struct {
 	KafkaConfig     KafkaConfig     `mapstructure:"kafka_config"`
 	StaticSrcConfig StaticSrcConfig `mapstructure:"static_src_config"`
 	LogConfig       LogConfig       `mapstructure:"log_config"`
+	AiConfig        AiConfig        `mapstructure:"ai_config"`
 }
 
 // 服务基础配置
@@ -52,8 +53,10 @@ type EtcdConfig struct {
 type KafkaConfig struct {
 	Address     string `mapstructure:"address"`
 	ChatTopic   string `mapstructure:"chatTopic"`
+	LoginTopic  string `mapstructure:"loginTopic"`
+	LogoutTopic string `mapstructure:"logoutTopic"`
 	MessageMode string `mapstructure:"messageMode"`
-	Partition   int    `mapstructure:"partition"`
+	Partition   int    `mapstructure:"partition"` // 聊天主题的分区数
 	Timeout     int    `mapstructure:"timeout"`
 }
 
@@ -61,6 +64,7 @@ type KafkaConfig struct {
 type StaticSrcConfig struct {
 	StaticAvatarPath string `mapstructure:"static_avatar_path"`
 	StaticFilePath   string `mapstructure:"static_file_path"`
+	StaticVoicePath  string `mapstructure:"static_voice_path"`
 }
 
 // 日志配置
@@ -68,6 +72,17 @@ type LogConfig struct {
 	LogPath string `mapstructure:"log_path"`
 }
 
+// AI助手配置，接口需兼容OpenAI的chat completions
+type AiConfig struct {
+	BaseUrl      string `mapstructure:"base_url"` // 如https://api.openai.com/v1，本地调试时可指向桩服务
+	ApiKey       string `mapstructure:"api_key"`
+	Model        string `mapstructure:"model"`
+	SystemPrompt string `mapstructure:"system_prompt"`
+	Timeout      int    `mapstructure:"timeout"`       // 单次回复的超时，单位秒
+	DailyQuota   int    `mapstructure:"daily_quota"`   // 每个用户每天可以提问的次数，0表示不限制
+	HistoryLimit int    `mapstructure:"history_limit"` // 作为上下文的最近消息条数
+}
+
 var AppConfig *Config
 
 
@@ -28,6 +28,22 @@ func InitMySQL() {
 	}
 	err = GormDB.AutoMigrate(
 		&model.Message{},
+		&model.ThreadRead{},
+		&model.MessageMention{},
+		&model.StickerPack{},
+		&model.StickerItem{},
+		&model.MessagePin{},
+		&model.ScheduledMessage{},
+		&model.DisappearSetting{},
+		&model.Draft{},
+		&model.Poll{},
+		&model.PollVote{},
+		&model.Favorite{},
+		&model.Announcement{},
+		&model.UserAnnouncement{},
+		&model.BotEvent{},
+		&model.BotCommand{},
+		&model.UnreadCounter{},
 	) 
 
 	if err != nil {
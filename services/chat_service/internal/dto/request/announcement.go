@@ -0,0 +1,28 @@
+package request
+
+type CreateAnnouncementRequest struct {
+	OwnerId       string   `json:"owner_id"` // 发布人，必须是管理员
+	Title         string   `json:"title"`
+	Content       string   `json:"content"`
+	TargetType    int8     `json:"target_type"`    // 0.全部用户，1.指定群聊成员，2.按条件筛选
+	GroupIds      []string `json:"group_ids"`      // target_type为1时的群聊uuid
+	CreatedAfter  string   `json:"created_after"`  // target_type为2时按注册时间筛选，格式2006-01-02 15:04:05
+	CreatedBefore string   `json:"created_before"` // 同上
+	Status        *int8    `json:"status"`         // target_type为2时按用户状态筛选
+}
+
+type GetAnnouncementProgressRequest struct {
+	OwnerId        string `json:"owner_id"`
+	AnnouncementId string `json:"announcement_id"`
+}
+
+type GetMyAnnouncementListRequest struct {
+	OwnerId  string `json:"owner_id"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+type ReadAnnouncementRequest struct {
+	OwnerId         string   `json:"owner_id"`
+	AnnouncementIds []string `json:"announcement_ids"`
+}
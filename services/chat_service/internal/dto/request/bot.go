@@ -0,0 +1,18 @@
+package request
+
+import "encoding/json"
+
+// BotSendMessageRequest 机器人发送消息，令牌放在Authorization请求头中
+type BotSendMessageRequest struct {
+	ReceiveId   string          `json:"receive_id"`
+	Type        int8            `json:"type"`
+	Content     string          `json:"content"`
+	Url         string          `json:"url"`
+	FileType    string          `json:"file_type"`
+	FileName    string          `json:"file_name"`
+	FileSize    string          `json:"file_size"`
+	Extra       json.RawMessage `json:"extra"`
+	ReplyTo     string          `json:"reply_to"`
+	Mentions    []string        `json:"mentions"`
+	ClientMsgId string          `json:"client_msg_id"`
+}
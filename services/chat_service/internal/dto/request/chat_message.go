@@ -1,16 +1,27 @@
 package request
 
+import "encoding/json"
+
 type ChatMessageRequest struct {
-	SessionId  string `json:"session_id"`
-	Type       int8   `json:"type"`
-	Content    string `json:"content"`
-	Url        string `json:"url"`
-	SendId     string `json:"send_id"`
-	SendName   string `json:"send_name"`
-	SendAvatar string `json:"send_avatar"`
-	ReceiveId  string `json:"receive_id"`
-	FileSize   string `json:"file_size"`
-	FileType   string `json:"file_type"`
-	FileName   string `json:"file_name"`
-	AVdata     string `json:"av_data"`
+	SessionId   string          `json:"session_id"`
+	Type        int8            `json:"type"`
+	Content     string          `json:"content"`
+	Url         string          `json:"url"`
+	SendId      string          `json:"send_id"`
+	SendName    string          `json:"send_name"`
+	SendAvatar  string          `json:"send_avatar"`
+	ReceiveId   string          `json:"receive_id"`
+	FileSize    string          `json:"file_size"`
+	FileType    string          `json:"file_type"`
+	FileName    string          `json:"file_name"`
+	AVdata      string          `json:"av_data"`
+	ReplyTo     string          `json:"reply_to"`      // 引用回复的消息uuid
+	ThreadId    string          `json:"thread_id"`     // 话题根消息uuid，仅群聊可用
+	Mentions    []string        `json:"mentions"`      // 被@的群成员uuid
+	MentionAll  bool            `json:"mention_all"`   // @所有人，仅群主和管理员可用
+	Duration    int             `json:"duration"`      // 语音时长，单位秒
+	Waveform    []int           `json:"waveform"`      // 语音波形采样
+	Extra       json.RawMessage `json:"extra"`         // 图片、位置、名片、表情等消息的结构化内容
+	ClientMsgId string          `json:"client_msg_id"` // 客户端生成的消息id，网络重发时用于去重
+	Scheduled   bool            `json:"scheduled"`     // 定时消息到点投递，由服务端设置
 }
@@ -0,0 +1,23 @@
+package request
+
+type GetCommandListRequest struct {
+	OwnerId string `json:"owner_id"`
+	GroupId string `json:"group_id"`
+}
+
+// RegisterBotCommandRequest 机器人在群聊中注册命令，已注册的同名命令会被更新
+type RegisterBotCommandRequest struct {
+	GroupId     string `json:"group_id"`
+	Name        string `json:"name"` // 命令名，不含斜杠
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+}
+
+type UnregisterBotCommandRequest struct {
+	GroupId string `json:"group_id"`
+	Name    string `json:"name"`
+}
+
+type GetBotCommandListRequest struct {
+	GroupId string `json:"group_id"`
+}
@@ -0,0 +1,12 @@
+package request
+
+type SetDisappearTimerRequest struct {
+	OwnerId  string `json:"owner_id"`
+	TargetId string `json:"target_id"` // 单聊为对方uuid，群聊为群聊uuid
+	Duration int    `json:"duration"`  // 单位秒，0表示关闭
+}
+
+type GetDisappearTimerRequest struct {
+	OwnerId  string `json:"owner_id"`
+	TargetId string `json:"target_id"`
+}
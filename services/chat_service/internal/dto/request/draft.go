@@ -0,0 +1,11 @@
+package request
+
+type SaveDraftRequest struct {
+	OwnerId  string `json:"owner_id"`
+	TargetId string `json:"target_id"` // 单聊为对方uuid，群聊为群聊uuid
+	Content  string `json:"content"`   // 为空时删除草稿
+}
+
+type GetDraftListRequest struct {
+	OwnerId string `json:"owner_id"`
+}
@@ -0,0 +1,37 @@
+package request
+
+type AddFavoriteRequest struct {
+	OwnerId   string   `json:"owner_id"`
+	MessageId string   `json:"message_id"`
+	Tags      []string `json:"tags"`
+	Note      string   `json:"note"`
+}
+
+type UpdateFavoriteRequest struct {
+	OwnerId    string   `json:"owner_id"`
+	FavoriteId string   `json:"favorite_id"`
+	Tags       []string `json:"tags"` // 整体覆盖原有标签
+	Note       string   `json:"note"`
+}
+
+type DeleteFavoriteRequest struct {
+	OwnerId     string   `json:"owner_id"`
+	FavoriteIds []string `json:"favorite_ids"`
+}
+
+type GetFavoriteListRequest struct {
+	OwnerId  string `json:"owner_id"`
+	Tag      string `json:"tag"`  // 按标签过滤
+	Type     *int8  `json:"type"` // 按消息类型过滤
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+type SearchFavoriteRequest struct {
+	OwnerId  string `json:"owner_id"`
+	Keyword  string `json:"keyword"` // 匹配消息内容、文件名和备注
+	Tag      string `json:"tag"`
+	Type     *int8  `json:"type"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
@@ -0,0 +1,8 @@
+package request
+
+type ForwardMessageRequest struct {
+	OwnerId    string   `json:"owner_id"`
+	MessageIds []string `json:"message_ids"`
+	ReceiveIds []string `json:"receive_ids"` // 转发目标，用户或群聊uuid
+	IsMerged   bool     `json:"is_merged"`   // true为合并转发，false为逐条转发
+}
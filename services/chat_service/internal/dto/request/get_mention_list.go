@@ -0,0 +1,7 @@
+package request
+
+type GetMentionListRequest struct {
+	OwnerId  string `json:"owner_id"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
@@ -0,0 +1,8 @@
+package request
+
+type GetThreadListRequest struct {
+	GroupId  string `json:"group_id"`
+	OwnerId  string `json:"owner_id"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type GetThreadMessageListRequest struct {
+	ThreadId string `json:"thread_id"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
@@ -0,0 +1,11 @@
+package request
+
+type PinMessageRequest struct {
+	OwnerId   string `json:"owner_id"`
+	MessageId string `json:"message_id"`
+}
+
+type GetPinnedMessageListRequest struct {
+	OwnerId  string `json:"owner_id"`
+	TargetId string `json:"target_id"` // 单聊为对方uuid，群聊为群聊uuid
+}
@@ -0,0 +1,17 @@
+package request
+
+type VotePollRequest struct {
+	OwnerId   string `json:"owner_id"`
+	MessageId string `json:"message_id"` // 投票消息uuid
+	Options   []int  `json:"options"`    // 选择的选项下标，为空表示撤回投票
+}
+
+type ClosePollRequest struct {
+	OwnerId   string `json:"owner_id"`
+	MessageId string `json:"message_id"`
+}
+
+type GetPollResultRequest struct {
+	OwnerId   string `json:"owner_id"`
+	MessageId string `json:"message_id"`
+}
@@ -0,0 +1,11 @@
+package request
+
+type GetPresenceListRequest struct {
+	OwnerId string   `json:"owner_id"`
+	UserIds []string `json:"user_ids"` // 只返回好友的状态，其他用户忽略
+}
+
+type SubscribePresenceRequest struct {
+	OwnerId string   `json:"owner_id"`
+	UserIds []string `json:"user_ids"` // 订阅的联系人，会替换之前的订阅
+}
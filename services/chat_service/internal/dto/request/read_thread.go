@@ -0,0 +1,6 @@
+package request
+
+type ReadThreadRequest struct {
+	OwnerId  string `json:"owner_id"`
+	ThreadId string `json:"thread_id"`
+}
@@ -0,0 +1,25 @@
+package request
+
+type ScheduleMessageRequest struct {
+	OwnerId string             `json:"owner_id"`
+	SendAt  string             `json:"send_at"` // 格式为2006-01-02 15:04:05
+	Message ChatMessageRequest `json:"message"`
+}
+
+type UpdateScheduledMessageRequest struct {
+	OwnerId    string             `json:"owner_id"`
+	ScheduleId string             `json:"schedule_id"`
+	SendAt     string             `json:"send_at"`
+	Message    ChatMessageRequest `json:"message"`
+}
+
+type CancelScheduledMessageRequest struct {
+	OwnerId    string `json:"owner_id"`
+	ScheduleId string `json:"schedule_id"`
+}
+
+type GetScheduledMessageListRequest struct {
+	OwnerId  string `json:"owner_id"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
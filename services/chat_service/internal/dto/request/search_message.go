@@ -0,0 +1,13 @@
+package request
+
+type SearchMessageRequest struct {
+	OwnerId   string `json:"owner_id"`
+	Keyword   string `json:"keyword"`
+	TargetId  string `json:"target_id"`  // 会话对象，用户或群聊uuid，为空时搜索自己参与的全部会话
+	SendId    string `json:"send_id"`    // 按发送者过滤
+	Type      *int8  `json:"type"`       // 按消息类型过滤
+	StartTime string `json:"start_time"` // 格式2006-01-02 15:04:05
+	EndTime   string `json:"end_time"`
+	Page      int    `json:"page"`
+	PageSize  int    `json:"page_size"`
+}
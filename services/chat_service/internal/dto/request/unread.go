@@ -0,0 +1,10 @@
+package request
+
+type ReadSessionRequest struct {
+	OwnerId  string `json:"owner_id"`
+	TargetId string `json:"target_id"` // 会话对方的用户uuid或群聊uuid
+}
+
+type GetUnreadTotalRequest struct {
+	OwnerId string `json:"owner_id"`
+}
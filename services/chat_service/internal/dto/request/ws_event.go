@@ -0,0 +1,10 @@
+package request
+
+// WsEventRequest websocket上的临时事件，如正在输入，只转发不落库
+// 帧中带有event字段时按临时事件处理，否则按ChatMessageRequest处理
+type WsEventRequest struct {
+	Event     string `json:"event"`
+	ReceiveId string `json:"receive_id"`
+	MessageId string `json:"message_id"` // 投票事件的投票消息uuid
+	Options   []int  `json:"options"`    // 投票事件选择的选项下标
+}
@@ -0,0 +1,39 @@
+package respond
+
+// AnnouncementFilterRespond 公告投递范围的参数，保存在公告的target_filter中
+type AnnouncementFilterRespond struct {
+	GroupIds      []string `json:"group_ids,omitempty"`
+	CreatedAfter  string   `json:"created_after,omitempty"`
+	CreatedBefore string   `json:"created_before,omitempty"`
+	Status        *int8    `json:"status,omitempty"`
+}
+
+type AnnouncementProgressRespond struct {
+	AnnouncementId string `json:"announcement_id"`
+	Status         int8   `json:"status"` // 0.等待发送，1.发送中，2.已完成
+	TotalCnt       int    `json:"total_cnt"`
+	SentCnt        int    `json:"sent_cnt"`
+	OnlineCnt      int    `json:"online_cnt"`
+	CreatedAt      string `json:"created_at"`
+	FinishedAt     string `json:"finished_at,omitempty"`
+}
+
+type AnnouncementRespond struct {
+	AnnouncementId string `json:"announcement_id"`
+	Title          string `json:"title"`
+	Content        string `json:"content"`
+	Read           bool   `json:"read"`
+	CreatedAt      string `json:"created_at"`
+}
+
+type GetMyAnnouncementListRespond struct {
+	Total         int64                 `json:"total"`
+	UnreadCnt     int64                 `json:"unread_cnt"`
+	Announcements []AnnouncementRespond `json:"announcements"`
+}
+
+// AnnouncementEventRespond 公告的实时推送
+type AnnouncementEventRespond struct {
+	Event        string              `json:"event"`
+	Announcement AnnouncementRespond `json:"announcement"`
+}
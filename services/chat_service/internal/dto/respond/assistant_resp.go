@@ -0,0 +1,19 @@
+package respond
+
+// AssistantDeltaRespond AI助手流式回复的增量帧，客户端按message_id拼接
+// 回复结束后以同一个message_id下发完整消息，客户端用它替换拼接的内容
+type AssistantDeltaRespond struct {
+	Event     string `json:"event"`
+	MessageId string `json:"message_id"`
+	ReplyTo   string `json:"reply_to"` // 用户提问的消息uuid
+	Seq       int    `json:"seq"`      // 增量帧序号，从1开始
+	Delta     string `json:"delta"`
+}
+
+// AssistantErrorRespond AI助手回复失败，如超出次数、接口超时
+type AssistantErrorRespond struct {
+	Event     string `json:"event"`
+	MessageId string `json:"message_id"`
+	ReplyTo   string `json:"reply_to"`
+	Reason    string `json:"reason"`
+}
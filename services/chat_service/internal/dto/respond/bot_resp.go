@@ -0,0 +1,9 @@
+package respond
+
+// BotMessageEventRespond 推送给机器人的消息事件，webhook请求体和gRPC流的payload相同
+type BotMessageEventRespond struct {
+	Event          string                `json:"event"`
+	BotId          string                `json:"bot_id"`
+	ConversationId string                `json:"conversation_id"` // 单聊为对方uuid，群聊为群聊uuid，回复时作为receive_id
+	Message        GetMessageListRespond `json:"message"`
+}
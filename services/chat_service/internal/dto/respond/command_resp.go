@@ -0,0 +1,32 @@
+package respond
+
+// CommandResultRespond 斜杠命令的执行结果，只推送给执行命令的用户
+type CommandResultRespond struct {
+	Event       string      `json:"event"`
+	Command     string      `json:"command"`
+	Ok          bool        `json:"ok"`
+	Message     string      `json:"message"`
+	Data        interface{} `json:"data,omitempty"`
+	ClientMsgId string      `json:"client_msg_id,omitempty"` // 命令消息的客户端消息id，用于和本地消息对应
+}
+
+// CommandRespond 命令说明，用于/help和客户端的命令补全
+type CommandRespond struct {
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+	AdminOnly   bool   `json:"admin_only"`
+	BotId       string `json:"bot_id,omitempty"` // 机器人注册的命令
+}
+
+// BotCommandEventRespond 群成员执行机器人命令时推送给机器人的事件
+type BotCommandEventRespond struct {
+	Event          string   `json:"event"`
+	BotId          string   `json:"bot_id"`
+	ConversationId string   `json:"conversation_id"` // 群聊uuid，回复时作为receive_id
+	Command        string   `json:"command"`
+	Args           []string `json:"args"`
+	RawArgs        string   `json:"raw_args"` // 命令名之后的原始文本
+	SendId         string   `json:"send_id"`
+	SendName       string   `json:"send_name"`
+}
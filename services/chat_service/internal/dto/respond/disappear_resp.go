@@ -0,0 +1,20 @@
+package respond
+
+// DisappearTimerRespond 会话的阅后即焚设置
+type DisappearTimerRespond struct {
+	Duration  int    `json:"duration"` // 单位秒，0表示关闭
+	UpdatedBy string `json:"updated_by,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// DisappearTimerExtraRespond 修改阅后即焚设置时系统消息的extra
+type DisappearTimerExtraRespond struct {
+	Event    string `json:"event"`
+	Duration int    `json:"duration"`
+}
+
+// MessagesExpiredEventRespond 通知客户端删除本地已过期的消息
+type MessagesExpiredEventRespond struct {
+	Event      string   `json:"event"`
+	MessageIds []string `json:"message_ids"`
+}
@@ -0,0 +1,13 @@
+package respond
+
+type DraftRespond struct {
+	TargetId  string `json:"target_id"`
+	Content   string `json:"content"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// DraftEventRespond 草稿变化时推送给用户自己的在线连接，content为空表示草稿已清除
+type DraftEventRespond struct {
+	Event string `json:"event"`
+	DraftRespond
+}
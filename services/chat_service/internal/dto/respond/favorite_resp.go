@@ -0,0 +1,63 @@
+package respond
+
+import (
+	"encoding/json"
+
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+type FavoriteRespond struct {
+	FavoriteId       string          `json:"favorite_id"`
+	MessageId        string          `json:"message_id"`
+	ConversationId   string          `json:"conversation_id"` // 来源会话，单聊为对方uuid，群聊为群聊uuid
+	SendId           string          `json:"send_id"`
+	SendName         string          `json:"send_name"`
+	SendAvatar       string          `json:"send_avatar"`
+	Type             int8            `json:"type"`
+	Content          string          `json:"content"`
+	Url              string          `json:"url"`
+	FileType         string          `json:"file_type"`
+	FileName         string          `json:"file_name"`
+	FileSize         string          `json:"file_size"`
+	Extra            json.RawMessage `json:"extra,omitempty"`
+	Tags             []string        `json:"tags"`
+	Note             string          `json:"note"`
+	MessageCreatedAt string          `json:"message_created_at"`
+	CreatedAt        string          `json:"created_at"`
+	UpdatedAt        string          `json:"updated_at"`
+}
+
+type GetFavoriteListRespond struct {
+	Total     int64             `json:"total"`
+	Favorites []FavoriteRespond `json:"favorites"`
+}
+
+// NewFavoriteRespond 由收藏记录构造响应
+func NewFavoriteRespond(favorite *model.Favorite) FavoriteRespond {
+	tags := []string{}
+	if favorite.Tags != "" {
+		if err := json.Unmarshal([]byte(favorite.Tags), &tags); err != nil {
+			tags = []string{}
+		}
+	}
+	return FavoriteRespond{
+		FavoriteId:       favorite.Uuid,
+		MessageId:        favorite.MessageId,
+		ConversationId:   favorite.ConversationId,
+		SendId:           favorite.SendId,
+		SendName:         favorite.SendName,
+		SendAvatar:       favorite.SendAvatar,
+		Type:             favorite.Type,
+		Content:          favorite.Content,
+		Url:              favorite.Url,
+		FileType:         favorite.FileType,
+		FileName:         favorite.FileName,
+		FileSize:         favorite.FileSize,
+		Extra:            parseExtra(favorite.Extra),
+		Tags:             tags,
+		Note:             favorite.Note,
+		MessageCreatedAt: favorite.MessageCreatedAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:        favorite.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:        favorite.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
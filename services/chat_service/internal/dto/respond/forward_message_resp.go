@@ -0,0 +1,8 @@
+package respond
+
+// ForwardMessageRespond 每个转发目标的转发结果，部分目标失败不影响其他目标
+type ForwardMessageRespond struct {
+	ReceiveId string `json:"receive_id"`
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+}
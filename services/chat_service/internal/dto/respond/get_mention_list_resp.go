@@ -0,0 +1,7 @@
+package respond
+
+type GetMentionListRespond struct {
+	GroupId string                     `json:"group_id"`
+	IsAll   bool                       `json:"is_all"` // 是否来自@所有人
+	Message GetGroupMessageListRespond `json:"message"`
+}
@@ -0,0 +1,16 @@
+package respond
+
+type GetStickerPackListRespond struct {
+	Uuid  string               `json:"uuid"`
+	Name  string               `json:"name"`
+	Cover string               `json:"cover"`
+	Items []StickerItemRespond `json:"items"`
+}
+
+type StickerItemRespond struct {
+	Uuid   string `json:"uuid"`
+	Name   string `json:"name"`
+	Url    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
@@ -0,0 +1,8 @@
+package respond
+
+type GetThreadListRespond struct {
+	RootMessage GetGroupMessageListRespond `json:"root_message"`
+	ReplyCnt    int                        `json:"reply_cnt"`
+	LastReplyAt string                     `json:"last_reply_at"`
+	UnreadCnt   int64                      `json:"unread_cnt"`
+}
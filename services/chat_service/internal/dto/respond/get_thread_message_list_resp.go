@@ -0,0 +1,6 @@
+package respond
+
+type GetThreadMessageListRespond struct {
+	Total    int64                        `json:"total"`
+	Messages []GetGroupMessageListRespond `json:"messages"`
+}
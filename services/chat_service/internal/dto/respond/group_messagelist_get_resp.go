@@ -1,15 +1,71 @@
 package respond
 
+import (
+	"encoding/json"
+
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
 type GetGroupMessageListRespond struct {
-	SendId     string `json:"send_id"`
-	SendName   string `json:"send_name"`
-	SendAvatar string `json:"send_avatar"`
-	ReceiveId  string `json:"receive_id"`
-	Type       int8   `json:"type"`
-	Content    string `json:"content"`
-	Url        string `json:"url"`
-	FileType   string `json:"file_type"`
-	FileName   string `json:"file_name"`
-	FileSize   string `json:"file_size"`
-	CreatedAt  string `json:"created_at"` // 先用CreatedAt排序，后面考虑改成SentAt
+	Uuid        string               `json:"uuid"`
+	SendId      string               `json:"send_id"`
+	SendName    string               `json:"send_name"`
+	SendAvatar  string               `json:"send_avatar"`
+	ReceiveId   string               `json:"receive_id"`
+	Type        int8                 `json:"type"`
+	Content     string               `json:"content"`
+	Url         string               `json:"url"`
+	FileType    string               `json:"file_type"`
+	FileName    string               `json:"file_name"`
+	FileSize    string               `json:"file_size"`
+	CreatedAt   string               `json:"created_at"`              // 先用CreatedAt排序，后面考虑改成SentAt
+	ReplyTo     *QuoteMessageRespond `json:"reply_to,omitempty"`      // 引用的消息摘要
+	ThreadId    string               `json:"thread_id,omitempty"`     // 所属话题的根消息uuid，为空表示主时间线消息
+	ReplyCnt    int                  `json:"reply_cnt"`               // 作为话题根消息时的回复数
+	Mentions    []string             `json:"mentions,omitempty"`      // 被@的成员uuid
+	MentionAll  bool                 `json:"mention_all,omitempty"`   // 是否@所有人
+	IsMentioned bool                 `json:"is_mentioned,omitempty"`  // 下发给被@成员时为true，便于客户端高亮
+	ForwardFrom string               `json:"forward_from,omitempty"`  // 逐条转发时的原消息uuid
+	Extra       json.RawMessage      `json:"extra,omitempty"`         // 扩展数据，如合并转发的聊天记录
+	ExpireAt    string               `json:"expire_at,omitempty"`     // 阅后即焚的过期时间，客户端到期后自行删除
+	ClientMsgId string               `json:"client_msg_id,omitempty"` // 发送者提交时带的客户端消息id，用于和本地消息对应
+}
+
+// NewGetGroupMessageListRespond 由消息记录构造群聊消息响应
+func NewGetGroupMessageListRespond(message *model.Message) GetGroupMessageListRespond {
+	return GetGroupMessageListRespond{
+		Uuid:        message.Uuid,
+		SendId:      message.SendId,
+		SendName:    message.SendName,
+		SendAvatar:  message.SendAvatar,
+		ReceiveId:   message.ReceiveId,
+		Type:        message.Type,
+		Content:     message.Content,
+		Url:         message.Url,
+		FileType:    message.FileType,
+		FileName:    message.FileName,
+		FileSize:    message.FileSize,
+		CreatedAt:   message.CreatedAt.Format("2006-01-02 15:04:05"),
+		ReplyTo:     parseQuote(message.ReplyQuote),
+		ThreadId:    message.ThreadId,
+		ReplyCnt:    message.ReplyCnt,
+		Mentions:    parseMentions(message.Mentions),
+		MentionAll:  message.MentionAll,
+		ForwardFrom: message.ForwardFrom,
+		Extra:       parseExtra(message.Extra),
+		ExpireAt:    formatExpireAt(message.ExpireAt),
+		ClientMsgId: message.ClientMsgId,
+	}
+}
+
+// parseMentions 解析消息中保存的@成员列表
+func parseMentions(mentions string) []string {
+	if mentions == "" {
+		return nil
+	}
+	var rsp []string
+	if err := json.Unmarshal([]byte(mentions), &rsp); err != nil {
+		return nil
+	}
+	return rsp
 }
@@ -0,0 +1,24 @@
+package respond
+
+// LinkPreviewRespond 链接预览，来自网页的OpenGraph或Twitter card标签
+type LinkPreviewRespond struct {
+	Url         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// TextExtraRespond 文本消息的extra
+type TextExtraRespond struct {
+	LinkPreview *LinkPreviewRespond `json:"link_preview,omitempty"`
+}
+
+// LinkPreviewEventRespond 链接预览生成后推送给会话参与者，客户端据此更新已显示的消息
+type LinkPreviewEventRespond struct {
+	Event       string             `json:"event"`
+	MessageId   string             `json:"message_id"`
+	SendId      string             `json:"send_id"`
+	ReceiveId   string             `json:"receive_id"`
+	LinkPreview LinkPreviewRespond `json:"link_preview"`
+}
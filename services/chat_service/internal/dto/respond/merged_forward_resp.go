@@ -0,0 +1,22 @@
+package respond
+
+import "encoding/json"
+
+// MergedForwardRespond 合并转发消息的extra内容，保存被转发消息的快照，接收方展开后直接展示
+type MergedForwardRespond struct {
+	Title    string                    `json:"title"`
+	Messages []ForwardedMessageRespond `json:"messages"`
+}
+
+type ForwardedMessageRespond struct {
+	SendName   string          `json:"send_name"`
+	SendAvatar string          `json:"send_avatar"`
+	Type       int8            `json:"type"`
+	Content    string          `json:"content"`
+	Url        string          `json:"url"`
+	FileType   string          `json:"file_type"`
+	FileName   string          `json:"file_name"`
+	FileSize   string          `json:"file_size"`
+	CreatedAt  string          `json:"created_at"`
+	Extra      json.RawMessage `json:"extra,omitempty"` // 被转发的消息本身是合并转发时，可以继续展开
+}
@@ -1,15 +1,79 @@
 package respond
 
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
 type GetMessageListRespond struct {
-	SendId     string `json:"send_id"`
-	SendName   string `json:"send_name"`
-	SendAvatar string `json:"send_avatar"`
-	ReceiveId  string `json:"receive_id"`
-	Type       int8   `json:"type"`
-	Content    string `json:"content"`
-	Url        string `json:"url"`
-	FileType   string `json:"file_type"`
-	FileName   string `json:"file_name"`
-	FileSize   string `json:"file_size"`
-	CreatedAt  string `json:"created_at"` // 先用CreatedAt排序，后面考虑改成SentAt
+	Uuid        string               `json:"uuid"`
+	SendId      string               `json:"send_id"`
+	SendName    string               `json:"send_name"`
+	SendAvatar  string               `json:"send_avatar"`
+	ReceiveId   string               `json:"receive_id"`
+	Type        int8                 `json:"type"`
+	Content     string               `json:"content"`
+	Url         string               `json:"url"`
+	FileType    string               `json:"file_type"`
+	FileName    string               `json:"file_name"`
+	FileSize    string               `json:"file_size"`
+	CreatedAt   string               `json:"created_at"`              // 先用CreatedAt排序，后面考虑改成SentAt
+	ReplyTo     *QuoteMessageRespond `json:"reply_to,omitempty"`      // 引用的消息摘要
+	ForwardFrom string               `json:"forward_from,omitempty"`  // 逐条转发时的原消息uuid
+	Extra       json.RawMessage      `json:"extra,omitempty"`         // 扩展数据，如合并转发的聊天记录
+	ExpireAt    string               `json:"expire_at,omitempty"`     // 阅后即焚的过期时间，客户端到期后自行删除
+	ClientMsgId string               `json:"client_msg_id,omitempty"` // 发送者提交时带的客户端消息id，用于和本地消息对应
+}
+
+// NewGetMessageListRespond 由消息记录构造单聊消息响应
+func NewGetMessageListRespond(message *model.Message) GetMessageListRespond {
+	return GetMessageListRespond{
+		Uuid:        message.Uuid,
+		SendId:      message.SendId,
+		SendName:    message.SendName,
+		SendAvatar:  message.SendAvatar,
+		ReceiveId:   message.ReceiveId,
+		Type:        message.Type,
+		Content:     message.Content,
+		Url:         message.Url,
+		FileType:    message.FileType,
+		FileName:    message.FileName,
+		FileSize:    message.FileSize,
+		CreatedAt:   message.CreatedAt.Format("2006-01-02 15:04:05"),
+		ReplyTo:     parseQuote(message.ReplyQuote),
+		ForwardFrom: message.ForwardFrom,
+		Extra:       parseExtra(message.Extra),
+		ExpireAt:    formatExpireAt(message.ExpireAt),
+		ClientMsgId: message.ClientMsgId,
+	}
+}
+
+// parseQuote 解析消息中保存的引用快照，没有引用时返回nil
+func parseQuote(replyQuote string) *QuoteMessageRespond {
+	if replyQuote == "" {
+		return nil
+	}
+	var quote QuoteMessageRespond
+	if err := json.Unmarshal([]byte(replyQuote), &quote); err != nil {
+		return nil
+	}
+	return &quote
+}
+
+// parseExtra 将消息的扩展数据原样作为json对象下发，没有扩展数据或格式不合法时返回nil
+func parseExtra(extra string) json.RawMessage {
+	if extra == "" || !json.Valid([]byte(extra)) {
+		return nil
+	}
+	return json.RawMessage(extra)
+}
+
+// formatExpireAt 格式化消息的过期时间，不过期时返回空
+func formatExpireAt(expireAt sql.NullTime) string {
+	if !expireAt.Valid {
+		return ""
+	}
+	return expireAt.Time.Format("2006-01-02 15:04:05")
 }
@@ -0,0 +1,17 @@
+package respond
+
+type GetPinnedMessageListRespond struct {
+	PinnedBy string                `json:"pinned_by"`
+	PinnedAt string                `json:"pinned_at"`
+	Message  GetMessageListRespond `json:"message"`
+}
+
+// PinEventRespond 置顶和取消置顶的实时通知
+type PinEventRespond struct {
+	Event     string `json:"event"`
+	SendId    string `json:"send_id"`    // 操作人
+	ReceiveId string `json:"receive_id"` // 单聊为接收通知的一方，群聊为群聊uuid
+	MessageId string `json:"message_id"`
+	Snippet   string `json:"snippet"`
+	CreatedAt string `json:"created_at"`
+}
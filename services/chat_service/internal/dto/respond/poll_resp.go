@@ -0,0 +1,47 @@
+package respond
+
+// PollExtraRespond 投票消息的extra内容
+type PollExtraRespond struct {
+	Question  string   `json:"question"`
+	Options   []string `json:"options"`
+	Multiple  bool     `json:"multiple"`
+	Anonymous bool     `json:"anonymous"`
+	Deadline  string   `json:"deadline,omitempty"` // 格式为2006-01-02 15:04:05，为空表示不自动截止
+}
+
+type PollOptionRespond struct {
+	Index  int      `json:"index"`
+	Text   string   `json:"text"`
+	Count  int      `json:"count"`
+	Voters []string `json:"voters,omitempty"` // 匿名投票不下发投票人
+}
+
+// PollResultRespond 投票的当前统计
+type PollResultRespond struct {
+	MessageId   string              `json:"message_id"`
+	GroupId     string              `json:"group_id"`
+	Question    string              `json:"question"`
+	Options     []PollOptionRespond `json:"options"`
+	Multiple    bool                `json:"multiple"`
+	Anonymous   bool                `json:"anonymous"`
+	Deadline    string              `json:"deadline,omitempty"`
+	Closed      bool                `json:"closed"` // 手动结束或已过截止时间
+	TotalVoters int                 `json:"total_voters"`
+	MyVotes     []int               `json:"my_votes,omitempty"` // 查询者自己的选择，实时推送中不带
+}
+
+// PollEventRespond 投票统计变化的实时通知
+type PollEventRespond struct {
+	Event     string            `json:"event"`
+	SendId    string            `json:"send_id"`    // 触发变化的用户
+	ReceiveId string            `json:"receive_id"` // 群聊uuid
+	Poll      PollResultRespond `json:"poll"`
+	CreatedAt string            `json:"created_at"`
+}
+
+// PollVoteFailedRespond 通过websocket投票失败时回给投票人的通知
+type PollVoteFailedRespond struct {
+	Event     string `json:"event"`
+	MessageId string `json:"message_id"`
+	Message   string `json:"message"`
+}
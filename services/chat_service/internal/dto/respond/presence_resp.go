@@ -0,0 +1,15 @@
+package respond
+
+// PresenceRespond 用户在线状态
+type PresenceRespond struct {
+	UserId        string `json:"user_id"`
+	Status        int8   `json:"status"` // 0.离线，1.在线，2.离开
+	LastOnlineAt  string `json:"last_online_at"`
+	LastOfflineAt string `json:"last_offline_at"`
+}
+
+// PresenceEventRespond 在线状态变更时推送给订阅者的websocket帧
+type PresenceEventRespond struct {
+	Event string `json:"event"`
+	PresenceRespond
+}
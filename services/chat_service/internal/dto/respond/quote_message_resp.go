@@ -0,0 +1,12 @@
+package respond
+
+// QuoteMessageRespond 被引用消息的摘要，随引用回复一起下发
+type QuoteMessageRespond struct {
+	Uuid     string `json:"uuid"`
+	SendId   string `json:"send_id"`
+	SendName string `json:"send_name"`
+	Type     int8   `json:"type"`
+	Content  string `json:"content"` // 截断后的文本内容
+	Url      string `json:"url"`
+	FileName string `json:"file_name"`
+}
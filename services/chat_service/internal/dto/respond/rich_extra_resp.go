@@ -0,0 +1,33 @@
+package respond
+
+// ImageExtraRespond 图片消息的extra内容，原图地址放在消息的url中
+type ImageExtraRespond struct {
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+}
+
+// LocationExtraRespond 位置消息的extra内容
+type LocationExtraRespond struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Title     string  `json:"title"`
+	Address   string  `json:"address"`
+}
+
+// ContactCardExtraRespond 名片消息的extra内容，名称和头像为发送时的快照
+type ContactCardExtraRespond struct {
+	Uuid   string `json:"uuid"` // 用户或群聊uuid
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+}
+
+// StickerExtraRespond 表情消息的extra内容
+type StickerExtraRespond struct {
+	PackId    string `json:"pack_id"`
+	StickerId string `json:"sticker_id"`
+	Name      string `json:"name"`
+	Url       string `json:"url"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+}
@@ -0,0 +1,17 @@
+package respond
+
+import "encoding/json"
+
+type ScheduledMessageRespond struct {
+	ScheduleId string          `json:"schedule_id"`
+	SessionId  string          `json:"session_id"`
+	ReceiveId  string          `json:"receive_id"`
+	Type       int8            `json:"type"`
+	Content    string          `json:"content"`
+	Url        string          `json:"url"`
+	FileName   string          `json:"file_name"`
+	Extra      json.RawMessage `json:"extra,omitempty"`
+	SendAt     string          `json:"send_at"`
+	Status     int8            `json:"status"`
+	CreatedAt  string          `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package respond
+
+type SearchMessageRespond struct {
+	Total    int64                      `json:"total"`
+	Messages []SearchMessageItemRespond `json:"messages"`
+}
+
+type SearchMessageItemRespond struct {
+	ConversationId string                `json:"conversation_id"` // 单聊为对方uuid，群聊为群聊uuid
+	Message        GetMessageListRespond `json:"message"`
+}
@@ -0,0 +1,21 @@
+package respond
+
+// UnreadEventRespond 会话未读数变化时推送给用户
+type UnreadEventRespond struct {
+	Event    string `json:"event"`
+	TargetId string `json:"target_id"` // 会话对方的用户uuid或群聊uuid
+	Unread   int    `json:"unread"`
+	Total    int    `json:"total"`  // 所有会话的未读数之和
+	Silent   bool   `json:"silent"` // 会话免打扰或处于免打扰时段，客户端只更新角标，不弹提醒
+}
+
+type SessionUnreadRespond struct {
+	TargetId string `json:"target_id"`
+	Unread   int    `json:"unread"`
+}
+
+// UnreadTotalRespond 未读角标
+type UnreadTotalRespond struct {
+	Total    int                    `json:"total"`
+	Sessions []SessionUnreadRespond `json:"sessions"` // 有未读的会话
+}
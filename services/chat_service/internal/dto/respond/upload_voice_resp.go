@@ -0,0 +1,7 @@
+package respond
+
+type UploadVoiceRespond struct {
+	Url      string `json:"url"`
+	FileType string `json:"file_type"`
+	FileSize string `json:"file_size"`
+}
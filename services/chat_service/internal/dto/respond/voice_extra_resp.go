@@ -0,0 +1,7 @@
+package respond
+
+// VoiceExtraRespond 语音消息的extra内容
+type VoiceExtraRespond struct {
+	Duration int   `json:"duration"` // 时长，单位秒
+	Waveform []int `json:"waveform"` // 波形采样，取值0-255，用于客户端绘制
+}
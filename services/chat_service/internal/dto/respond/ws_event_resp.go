@@ -0,0 +1,8 @@
+package respond
+
+type WsEventRespond struct {
+	Event     string `json:"event"`
+	SendId    string `json:"send_id"`
+	ReceiveId string `json:"receive_id"`
+	CreatedAt string `json:"created_at"`
+}
@@ -1,2 +1,101 @@
 package grpc_server
 
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/enum/bot/bot_delivery_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+	chatpb "github.com/puoxiu/gogochat/services/chat_service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	botStreamPollInterval = time.Second // 流上轮询待投递事件的间隔
+	botStreamBatchSize    = 50          // 每次最多领取的事件数
+)
+
+type ChatGrpcServer struct {
+	chatpb.UnimplementedChatServiceServer
+}
+
+// BotStream 机器人建立事件流，连接期间持续推送发给机器人的事件
+// 事件发送成功后才标记为已投递，断开时未发送的事件放回，下次连接时继续投递
+func (s *ChatGrpcServer) BotStream(req *chatpb.BotStreamRequest, stream chatpb.ChatService_BotStreamServer) error {
+	bot, msg, ret := services.BotService.Authenticate(req.Token)
+	if ret == -1 {
+		return status.Error(codes.Internal, msg)
+	}
+	if ret != 0 {
+		return status.Error(codes.Unauthenticated, msg)
+	}
+	if bot.DeliveryMode != bot_delivery_enum.Stream {
+		return status.Error(codes.FailedPrecondition, "机器人的投递方式不是gRPC流")
+	}
+	ticker := time.NewTicker(botStreamPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+		eventList, err := chat.ClaimBotStreamEvents(bot.Uuid, botStreamBatchSize)
+		if err != nil {
+			zlog.Error(err.Error())
+		}
+		for i, event := range eventList {
+			if err := stream.Send(&chatpb.BotEvent{
+				EventId: event.Id,
+				Event:   event.Event,
+				Payload: event.Payload,
+			}); err != nil {
+				for _, unsent := range eventList[i:] {
+					chat.AckBotStreamEvent(unsent.Id, false)
+				}
+				return err
+			}
+			chat.AckBotStreamEvent(event.Id, true)
+		}
+	}
+}
+
+// SendBotMessage 机器人通过gRPC发送消息，与http接口走同一套校验
+func (s *ChatGrpcServer) SendBotMessage(ctx context.Context, req *chatpb.SendBotMessageRequest) (*chatpb.SendBotMessageResponse, error) {
+	sendReq := request.BotSendMessageRequest{
+		ReceiveId:   req.ReceiveId,
+		Type:        int8(req.Type),
+		Content:     req.Content,
+		Url:         req.Url,
+		FileType:    req.FileType,
+		FileName:    req.FileName,
+		FileSize:    req.FileSize,
+		ReplyTo:     req.ReplyTo,
+		Mentions:    req.Mentions,
+		ClientMsgId: req.ClientMsgId,
+	}
+	if req.Extra != "" {
+		sendReq.Extra = []byte(req.Extra)
+	}
+	msg, code := services.BotService.SendMessage(req.Token, sendReq)
+	return &chatpb.SendBotMessageResponse{
+		Code:    int32(code),
+		Message: msg,
+	}, nil
+}
+
+// PushUserEvent 其他服务向用户连在本实例上的连接推送事件，如会话设置在多端同步，调用方会发给每个实例
+func (s *ChatGrpcServer) PushUserEvent(ctx context.Context, req *chatpb.PushUserEventRequest) (*chatpb.PushUserEventResponse, error) {
+	if req.UserId == "" || !json.Valid([]byte(req.Event)) {
+		return &chatpb.PushUserEventResponse{Code: -2, Message: "事件不合法"}, nil
+	}
+	if !chat.PushUserEvent(req.UserId, []byte(req.Event)) {
+		return &chatpb.PushUserEventResponse{Code: 0, Message: "用户不在线"}, nil
+	}
+	return &chatpb.PushUserEventResponse{Code: 0, Message: "推送成功"}, nil
+}
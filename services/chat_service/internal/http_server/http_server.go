@@ -19,11 +19,52 @@ func InitHttpServer() {
 	// GE.Use(ssl.TlsHandler(config.GetConfig().MainConfig.Host, config.GetConfig().MainConfig.Port))
 	GE.Static("/static/avatars", config.AppConfig.StaticSrcConfig.StaticAvatarPath)
 	GE.Static("/static/files", config.AppConfig.StaticSrcConfig.StaticFilePath)
+	GE.Static("/static/voices", config.AppConfig.StaticSrcConfig.StaticVoicePath)
 
 	GE.POST("/message/getMessageList", v1.GetMessageList)
 	GE.POST("/message/getGroupMessageList", v1.GetGroupMessageList)
 	GE.POST("/message/uploadAvatar", v1.UploadAvatar)
 	GE.POST("/message/uploadFile", v1.UploadFile)
+	GE.POST("/message/uploadVoice", v1.UploadVoice)
+	GE.POST("/message/getThreadList", v1.GetThreadList)
+	GE.POST("/message/getThreadMessageList", v1.GetThreadMessageList)
+	GE.POST("/message/readThread", v1.ReadThread)
+	GE.POST("/message/getMentionList", v1.GetMentionList)
+	GE.POST("/message/forwardMessage", v1.ForwardMessage)
+	GE.POST("/message/searchMessage", v1.SearchMessage)
+	GE.POST("/message/pinMessage", v1.PinMessage)
+	GE.POST("/message/unpinMessage", v1.UnpinMessage)
+	GE.POST("/message/getPinnedMessageList", v1.GetPinnedMessageList)
+	GE.POST("/message/scheduleMessage", v1.ScheduleMessage)
+	GE.POST("/message/updateScheduledMessage", v1.UpdateScheduledMessage)
+	GE.POST("/message/cancelScheduledMessage", v1.CancelScheduledMessage)
+	GE.POST("/message/getScheduledMessageList", v1.GetScheduledMessageList)
+	GE.POST("/message/setDisappearTimer", v1.SetDisappearTimer)
+	GE.POST("/message/getDisappearTimer", v1.GetDisappearTimer)
+	GE.POST("/sticker/getStickerPackList", v1.GetStickerPackList)
+	GE.POST("/presence/getPresenceList", v1.GetPresenceList)
+	GE.POST("/presence/subscribe", v1.SubscribePresence)
+	GE.POST("/draft/saveDraft", v1.SaveDraft)
+	GE.POST("/draft/getDraftList", v1.GetDraftList)
+	GE.POST("/poll/votePoll", v1.VotePoll)
+	GE.POST("/poll/closePoll", v1.ClosePoll)
+	GE.POST("/poll/getPollResult", v1.GetPollResult)
+	GE.POST("/favorite/addFavorite", v1.AddFavorite)
+	GE.POST("/favorite/updateFavorite", v1.UpdateFavorite)
+	GE.POST("/favorite/deleteFavorite", v1.DeleteFavorite)
+	GE.POST("/favorite/getFavoriteList", v1.GetFavoriteList)
+	GE.POST("/favorite/searchFavorite", v1.SearchFavorite)
+	GE.POST("/announcement/createAnnouncement", v1.CreateAnnouncement)
+	GE.POST("/announcement/getAnnouncementProgress", v1.GetAnnouncementProgress)
+	GE.POST("/announcement/getMyAnnouncementList", v1.GetMyAnnouncementList)
+	GE.POST("/announcement/readAnnouncement", v1.ReadAnnouncement)
+	GE.POST("/bot/sendMessage", v1.BotSendMessage)
+	GE.POST("/bot/registerCommand", v1.BotRegisterCommand)
+	GE.POST("/bot/unregisterCommand", v1.BotUnregisterCommand)
+	GE.POST("/bot/getCommandList", v1.BotGetCommandList)
+	GE.POST("/command/getCommandList", v1.GetCommandList)
+	GE.POST("/unread/readSession", v1.ReadSession)
+	GE.POST("/unread/getUnreadTotal", v1.GetUnreadTotal)
 	GE.POST("/chatroom/getCurContactListInChatRoom", v1.GetCurContactListInChatRoom)
 	GE.GET("/wss", v1.WsLogin)
 }
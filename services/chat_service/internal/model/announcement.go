@@ -0,0 +1,42 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Announcement 管理员发布的系统公告，按批次投递到用户的公告收件箱
+type Announcement struct {
+	Id           int64        `gorm:"column:id;primaryKey;comment:自增id"`
+	Uuid         string       `gorm:"column:uuid;uniqueIndex;type:char(20);not null;comment:公告uuid"`
+	AdminId      string       `gorm:"column:admin_id;type:char(20);not null;comment:发布人uuid"`
+	Title        string       `gorm:"column:title;type:varchar(50);not null;comment:标题"`
+	Content      string       `gorm:"column:content;type:TEXT;not null;comment:内容"`
+	TargetType   int8         `gorm:"column:target_type;not null;comment:投递范围，0.全部用户，1.指定群聊成员，2.按条件筛选"`
+	TargetFilter string       `gorm:"column:target_filter;type:TEXT;comment:投递范围的参数json"`
+	Status       int8         `gorm:"column:status;index:idx_status_claimed_at;not null;comment:状态，0.等待发送，1.发送中，2.已完成"`
+	ClaimedAt    sql.NullTime `gorm:"column:claimed_at;index:idx_status_claimed_at;comment:领取或最近一批投递的时间"`
+	LastUserId   int64        `gorm:"column:last_user_id;not null;default:0;comment:已投递到的用户自增id，中断后从这里继续"`
+	TotalCnt     int          `gorm:"column:total_cnt;not null;default:0;comment:目标用户数"`
+	SentCnt      int          `gorm:"column:sent_cnt;not null;default:0;comment:已投递用户数"`
+	OnlineCnt    int          `gorm:"column:online_cnt;not null;default:0;comment:投递时在线并实时推送的用户数"`
+	CreatedAt    time.Time    `gorm:"column:created_at;not null;comment:创建时间"`
+	FinishedAt   sql.NullTime `gorm:"column:finished_at;comment:完成时间"`
+}
+
+func (Announcement) TableName() string {
+	return "announcement"
+}
+
+// UserAnnouncement 用户的公告收件箱，离线用户上线后从这里拉取
+type UserAnnouncement struct {
+	Id             int64        `gorm:"column:id;primaryKey;comment:自增id"`
+	UserId         string       `gorm:"column:user_id;uniqueIndex:idx_user_announcement;type:char(20);not null;comment:用户uuid"`
+	AnnouncementId string       `gorm:"column:announcement_id;uniqueIndex:idx_user_announcement;type:char(20);not null;comment:公告uuid"`
+	ReadAt         sql.NullTime `gorm:"column:read_at;comment:已读时间，为空表示未读"`
+	CreatedAt      time.Time    `gorm:"column:created_at;not null;comment:投递时间"`
+}
+
+func (UserAnnouncement) TableName() string {
+	return "user_announcement"
+}
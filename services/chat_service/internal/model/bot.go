@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Bot 机器人配置，由user_service维护，聊天服务只读取
+type Bot struct {
+	Id            int64          `gorm:"column:id;primaryKey;comment:自增id"`
+	Uuid          string         `gorm:"column:uuid;uniqueIndex;type:char(20);not null;comment:机器人用户uuid"`
+	OwnerId       string         `gorm:"column:owner_id;index;type:char(20);not null;comment:创建者uuid"`
+	TokenHash     string         `gorm:"column:token_hash;uniqueIndex;type:char(64);not null;comment:令牌的sha256，明文令牌不保存"`
+	DeliveryMode  int8           `gorm:"column:delivery_mode;not null;default:0;comment:消息投递方式，0.webhook，1.gRPC流"`
+	WebhookUrl    string         `gorm:"column:webhook_url;type:varchar(255);not null;default:'';comment:webhook地址，必须是https"`
+	WebhookSecret string         `gorm:"column:webhook_secret;type:char(64);not null;comment:webhook请求的签名密钥"`
+	CreatedAt     time.Time      `gorm:"column:created_at;not null;comment:创建时间"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at;not null;comment:更新时间"`
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at;index;comment:删除时间"`
+}
+
+func (Bot) TableName() string {
+	return "bot"
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// BotCommand 机器人在群聊中注册的斜杠命令，同一群聊中命令名唯一
+type BotCommand struct {
+	Id          int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	GroupId     string    `gorm:"column:group_id;uniqueIndex:idx_group_name;type:char(20);not null;comment:群聊uuid"`
+	Name        string    `gorm:"column:name;uniqueIndex:idx_group_name;type:varchar(32);not null;comment:命令名，不含斜杠"`
+	BotId       string    `gorm:"column:bot_id;index;type:char(20);not null;comment:机器人uuid"`
+	Usage       string    `gorm:"column:usage;type:varchar(100);not null;default:'';comment:用法说明"`
+	Description string    `gorm:"column:description;type:varchar(200);not null;default:'';comment:命令说明"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null;comment:创建时间"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;not null;comment:更新时间"`
+}
+
+func (BotCommand) TableName() string {
+	return "bot_command"
+}
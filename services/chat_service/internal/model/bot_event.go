@@ -0,0 +1,24 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BotEvent 待投递给机器人的事件，webhook和gRPC流共用，实例宕机后由其他实例继续投递
+type BotEvent struct {
+	Id            int64        `gorm:"column:id;primaryKey;comment:自增id，作为事件id下发给机器人"`
+	BotId         string       `gorm:"column:bot_id;index:idx_bot_status;type:char(20);not null;comment:机器人uuid"`
+	DeliveryMode  int8         `gorm:"column:delivery_mode;index:idx_status_next_attempt;not null;comment:生成事件时机器人的投递方式"`
+	Event         string       `gorm:"column:event;type:varchar(32);not null;comment:事件类型"`
+	Payload       string       `gorm:"column:payload;type:TEXT;not null;comment:事件内容json"`
+	Status        int8         `gorm:"column:status;index:idx_bot_status;index:idx_status_next_attempt;not null;comment:状态，0.等待投递，1.投递中，2.已投递，3.失败"`
+	Attempts      int          `gorm:"column:attempts;not null;default:0;comment:已尝试次数"`
+	NextAttemptAt time.Time    `gorm:"column:next_attempt_at;index:idx_status_next_attempt;not null;comment:下次可投递的时间"`
+	ClaimedAt     sql.NullTime `gorm:"column:claimed_at;comment:领取时间"`
+	CreatedAt     time.Time    `gorm:"column:created_at;not null;comment:创建时间"`
+}
+
+func (BotEvent) TableName() string {
+	return "bot_event"
+}
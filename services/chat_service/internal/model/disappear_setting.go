@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// DisappearSetting 会话的阅后即焚设置，单聊双方共用一条，conversation_id见pkg/conversation
+type DisappearSetting struct {
+	Id             int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	ConversationId string    `gorm:"column:conversation_id;uniqueIndex;type:varchar(41);not null;comment:会话标识"`
+	Duration       int       `gorm:"column:duration;not null;default:0;comment:消息保留时长，单位秒，0表示关闭"`
+	UpdatedBy      string    `gorm:"column:updated_by;type:char(20);not null;comment:最后修改人uuid"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;not null;comment:修改时间"`
+}
+
+func (DisappearSetting) TableName() string {
+	return "disappear_setting"
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Draft 用户在会话中未发送的草稿，每个用户的每个会话最多一条，多端共享
+type Draft struct {
+	Id        int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	UserId    string    `gorm:"column:user_id;uniqueIndex:idx_user_target;type:char(20);not null;comment:用户uuid"`
+	TargetId  string    `gorm:"column:target_id;uniqueIndex:idx_user_target;type:char(20);not null;comment:会话对方的用户uuid或群聊uuid"`
+	Content   string    `gorm:"column:content;type:TEXT;comment:草稿内容"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null;comment:更新时间"`
+}
+
+func (Draft) TableName() string {
+	return "draft"
+}
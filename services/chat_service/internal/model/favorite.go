@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Favorite 个人收藏，保存收藏时的消息快照，原消息或会话删除后仍然保留
+type Favorite struct {
+	Id               int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	Uuid             string    `gorm:"column:uuid;uniqueIndex;type:char(20);not null;comment:收藏uuid"`
+	OwnerId          string    `gorm:"column:owner_id;uniqueIndex:idx_owner_message;type:char(20);not null;comment:收藏者uuid"`
+	MessageId        string    `gorm:"column:message_id;uniqueIndex:idx_owner_message;type:char(20);not null;comment:原消息uuid"`
+	ConversationId   string    `gorm:"column:conversation_id;type:char(20);not null;comment:来源会话，单聊为对方uuid，群聊为群聊uuid"`
+	SendId           string    `gorm:"column:send_id;type:char(20);not null;comment:发送者uuid"`
+	SendName         string    `gorm:"column:send_name;type:varchar(20);not null;comment:发送者昵称"`
+	SendAvatar       string    `gorm:"column:send_avatar;type:varchar(255);not null;comment:发送者头像"`
+	Type             int8      `gorm:"column:type;not null;comment:消息类型"`
+	Content          string    `gorm:"column:content;type:TEXT;comment:消息内容"`
+	Url              string    `gorm:"column:url;type:char(255);comment:消息url"`
+	FileType         string    `gorm:"column:file_type;type:char(10);comment:文件类型"`
+	FileName         string    `gorm:"column:file_name;type:varchar(50);comment:文件名"`
+	FileSize         string    `gorm:"column:file_size;type:char(20);comment:文件大小"`
+	Extra            string    `gorm:"column:extra;type:TEXT;comment:消息扩展数据快照"`
+	Tags             string    `gorm:"column:tags;type:json;comment:标签列表"`
+	Note             string    `gorm:"column:note;type:varchar(500);not null;default:'';comment:备注"`
+	MessageCreatedAt time.Time `gorm:"column:message_created_at;not null;comment:原消息发送时间"`
+	CreatedAt        time.Time `gorm:"column:created_at;index;not null;comment:收藏时间"`
+	UpdatedAt        time.Time `gorm:"column:updated_at;not null;comment:更新时间"`
+}
+
+func (Favorite) TableName() string {
+	return "favorite"
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// GroupMute 群成员禁言记录，由user_service维护，聊天服务只读取
+type GroupMute struct {
+	Id         int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	GroupId    string    `gorm:"column:group_id;uniqueIndex:idx_group_user;type:char(20);not null;comment:群聊uuid"`
+	UserId     string    `gorm:"column:user_id;uniqueIndex:idx_group_user;type:char(20);not null;comment:被禁言的成员uuid"`
+	OperatorId string    `gorm:"column:operator_id;type:char(20);not null;comment:操作人uuid"`
+	MutedUntil time.Time `gorm:"column:muted_until;not null;comment:禁言截止时间"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null;comment:创建时间"`
+}
+
+func (GroupMute) TableName() string {
+	return "group_mute"
+}
@@ -9,8 +9,8 @@ type Message struct {
 	Id         int64     `gorm:"column:id;primaryKey;comment:自增id"`
 	Uuid       string    `gorm:"column:uuid;uniqueIndex;type:char(20);not null;comment:消息uuid"`
 	SessionId  string    `gorm:"column:session_id;index;type:char(20);not null;comment:会话uuid"`
-	Type       int8      `gorm:"column:type;not null;comment:消息类型，0.文本，1.语音，2.文件，3.通话"` // 通话不用存消息内容或者url
-	Content    string    `gorm:"column:content;type:TEXT;comment:消息内容"`
+	Type       int8      `gorm:"column:type;not null;comment:消息类型，0.文本，1.语音，2.文件，3.通话，4.合并转发，5.图片，6.位置，7.名片，8.表情，9.系统消息，10.投票"` // 通话不用存消息内容或者url
+	Content    string    `gorm:"column:content;type:TEXT;index:idx_content_fulltext,class:FULLTEXT,option:WITH PARSER ngram;comment:消息内容"`
 	Url        string    `gorm:"column:url;type:char(255);comment:消息url"`
 	SendId     string    `gorm:"column:send_id;index;type:char(20);not null;comment:发送者uuid"`
 	SendName   string    `gorm:"column:send_name;type:varchar(20);not null;comment:发送者昵称"`
@@ -23,6 +23,17 @@ type Message struct {
 	CreatedAt  time.Time `gorm:"column:created_at;not null;comment:创建时间"`
 	SendAt     sql.NullTime `gorm:"column:send_at;comment:发送时间"`
 	AVdata     string    `gorm:"column:av_data;comment:通话传递数据"`
+	ReplyTo     string       `gorm:"column:reply_to;type:char(20);not null;default:'';comment:引用的消息uuid"`
+	ReplyQuote  string       `gorm:"column:reply_quote;type:TEXT;comment:被引用消息的摘要快照"`
+	ThreadId    string       `gorm:"column:thread_id;index;type:char(20);not null;default:'';comment:所属话题根消息uuid"`
+	ReplyCnt    int          `gorm:"column:reply_cnt;not null;default:0;comment:话题回复数"`
+	LastReplyAt sql.NullTime `gorm:"column:last_reply_at;comment:话题最近回复时间"`
+	Mentions    string       `gorm:"column:mentions;type:TEXT;comment:被@的成员uuid列表"`
+	MentionAll  bool         `gorm:"column:mention_all;not null;default:false;comment:是否@所有人"`
+	ForwardFrom string       `gorm:"column:forward_from;type:char(20);not null;default:'';comment:逐条转发时的原消息uuid"`
+	Extra       string       `gorm:"column:extra;type:TEXT;comment:扩展数据json，如合并转发的聊天记录快照"`
+	ExpireAt    sql.NullTime `gorm:"column:expire_at;index;comment:阅后即焚的过期时间，为空表示不过期"`
+	ClientMsgId string       `gorm:"column:client_msg_id;type:varchar(64);not null;default:'';comment:客户端生成的消息id，用于重发去重"`
 }
 
 func (Message) TableName() string {
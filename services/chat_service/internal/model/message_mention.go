@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// MessageMention 群聊中的@记录，@所有人时按成员展开，便于查询“@我的消息”
+type MessageMention struct {
+	Id        int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	MessageId string    `gorm:"column:message_id;index;type:char(20);not null;comment:消息uuid"`
+	GroupId   string    `gorm:"column:group_id;type:char(20);not null;comment:群聊uuid"`
+	UserId    string    `gorm:"column:user_id;index:idx_user_created;type:char(20);not null;comment:被@的用户uuid"`
+	SendId    string    `gorm:"column:send_id;type:char(20);not null;comment:发送者uuid"`
+	IsAll     bool      `gorm:"column:is_all;not null;default:false;comment:是否来自@所有人"`
+	CreatedAt time.Time `gorm:"column:created_at;index:idx_user_created;not null;comment:创建时间"`
+}
+
+func (MessageMention) TableName() string {
+	return "message_mention"
+}
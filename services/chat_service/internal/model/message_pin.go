@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// MessagePin 会话中的置顶消息，单聊和群聊共用，conversation_id见pkg/conversation
+type MessagePin struct {
+	Id             int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	ConversationId string    `gorm:"column:conversation_id;uniqueIndex:idx_conversation_message;type:varchar(41);not null;comment:会话标识"`
+	MessageId      string    `gorm:"column:message_id;uniqueIndex:idx_conversation_message;index;type:char(20);not null;comment:消息uuid"`
+	PinnedBy       string    `gorm:"column:pinned_by;type:char(20);not null;comment:置顶人uuid"`
+	Snippet        string    `gorm:"column:snippet;type:varchar(100);comment:消息摘要"`
+	CreatedAt      time.Time `gorm:"column:created_at;not null;comment:置顶时间"`
+}
+
+func (MessagePin) TableName() string {
+	return "message_pin"
+}
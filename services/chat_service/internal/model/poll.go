@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Poll 群投票，与投票消息一一对应，题目和选项同时保存在消息的extra中
+type Poll struct {
+	Id        int64        `gorm:"column:id;primaryKey;comment:自增id"`
+	MessageId string       `gorm:"column:message_id;uniqueIndex;type:char(20);not null;comment:投票消息uuid"`
+	GroupId   string       `gorm:"column:group_id;index;type:char(20);not null;comment:群聊uuid"`
+	CreatorId string       `gorm:"column:creator_id;type:char(20);not null;comment:发起人uuid"`
+	Question  string       `gorm:"column:question;type:varchar(255);not null;comment:投票题目"`
+	Options   string       `gorm:"column:options;type:TEXT;not null;comment:选项列表json"`
+	Multiple  bool         `gorm:"column:multiple;not null;default:false;comment:是否多选"`
+	Anonymous bool         `gorm:"column:anonymous;not null;default:false;comment:是否匿名投票"`
+	Deadline  sql.NullTime `gorm:"column:deadline;comment:截止时间，为空表示不自动截止"`
+	Closed    bool         `gorm:"column:closed;not null;default:false;comment:是否已手动结束"`
+	ClosedBy  string       `gorm:"column:closed_by;type:char(20);not null;default:'';comment:结束投票的用户uuid"`
+	ClosedAt  sql.NullTime `gorm:"column:closed_at;comment:结束时间"`
+	CreatedAt time.Time    `gorm:"column:created_at;not null;comment:创建时间"`
+}
+
+func (Poll) TableName() string {
+	return "poll"
+}
+
+// PollVote 投票记录，多选时每个选项一条
+type PollVote struct {
+	Id          int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	MessageId   string    `gorm:"column:message_id;uniqueIndex:idx_poll_user_option;type:char(20);not null;comment:投票消息uuid"`
+	UserId      string    `gorm:"column:user_id;uniqueIndex:idx_poll_user_option;type:char(20);not null;comment:投票人uuid"`
+	OptionIndex int       `gorm:"column:option_index;uniqueIndex:idx_poll_user_option;not null;comment:选项下标，从0开始"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null;comment:投票时间"`
+}
+
+func (PollVote) TableName() string {
+	return "poll_vote"
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ScheduledMessage 定时消息，到点后由调度器把payload投递到正常的消息通道
+type ScheduledMessage struct {
+	Id        int64        `gorm:"column:id;primaryKey;comment:自增id"`
+	Uuid      string       `gorm:"column:uuid;uniqueIndex;type:char(20);not null;comment:定时消息uuid"`
+	OwnerId   string       `gorm:"column:owner_id;index;type:char(20);not null;comment:发送者uuid"`
+	SessionId string       `gorm:"column:session_id;type:char(20);not null;comment:会话uuid"`
+	ReceiveId string       `gorm:"column:receive_id;type:char(20);not null;comment:接收者uuid"`
+	Type      int8         `gorm:"column:type;not null;comment:消息类型"`
+	Payload   string       `gorm:"column:payload;type:TEXT;not null;comment:投递的消息请求json"`
+	SendAt    time.Time    `gorm:"column:send_at;index:idx_status_send_at;type:datetime;not null;comment:发送时间"`
+	Status    int8         `gorm:"column:status;index:idx_status_send_at;not null;comment:状态，0.待发送，1.发送中，2.已发送，3.已取消，4.发送失败"`
+	ClaimedAt sql.NullTime `gorm:"column:claimed_at;type:datetime;comment:被调度器领取的时间"`
+	CreatedAt time.Time    `gorm:"column:created_at;type:datetime;not null;comment:创建时间"`
+	UpdatedAt time.Time    `gorm:"column:updated_at;type:datetime;not null;comment:更新时间"`
+}
+
+func (ScheduledMessage) TableName() string {
+	return "scheduled_message"
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SessionMute 会话免打扰，表由session_service维护，这里只读
+type SessionMute struct {
+	Id           int64        `gorm:"column:id;primaryKey"`
+	UserId       string       `gorm:"column:user_id"`
+	TargetId     string       `gorm:"column:target_id"`
+	MutedUntil   sql.NullTime `gorm:"column:muted_until"`
+	AllowMention bool         `gorm:"column:allow_mention"`
+	CreatedAt    time.Time    `gorm:"column:created_at"`
+}
+
+func (SessionMute) TableName() string {
+	return "session_mute"
+}
+
+// DndSetting 全局免打扰时段，表由session_service维护，这里只读
+type DndSetting struct {
+	Id           int64     `gorm:"column:id;primaryKey"`
+	UserId       string    `gorm:"column:user_id"`
+	Enabled      bool      `gorm:"column:enabled"`
+	StartTime    string    `gorm:"column:start_time"`
+	EndTime      string    `gorm:"column:end_time"`
+	Timezone     string    `gorm:"column:timezone"`
+	AllowMention bool      `gorm:"column:allow_mention"`
+	UpdatedAt    time.Time `gorm:"column:updated_at"`
+}
+
+func (DndSetting) TableName() string {
+	return "dnd_setting"
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// StickerPack 表情包
+type StickerPack struct {
+	Id        int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	Uuid      string    `gorm:"column:uuid;uniqueIndex;type:char(20);not null;comment:表情包uuid"`
+	Name      string    `gorm:"column:name;type:varchar(20);not null;comment:表情包名称"`
+	Cover     string    `gorm:"column:cover;type:varchar(255);not null;comment:封面"`
+	Status    int8      `gorm:"column:status;not null;default:0;comment:状态，0.正常，1.下架"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;comment:创建时间"`
+}
+
+func (StickerPack) TableName() string {
+	return "sticker_pack"
+}
+
+// StickerItem 表情包中的单个表情
+type StickerItem struct {
+	Id        int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	Uuid      string    `gorm:"column:uuid;uniqueIndex;type:char(20);not null;comment:表情uuid"`
+	PackId    string    `gorm:"column:pack_id;index;type:char(20);not null;comment:所属表情包uuid"`
+	Name      string    `gorm:"column:name;type:varchar(20);not null;comment:表情名称"`
+	Url       string    `gorm:"column:url;type:varchar(255);not null;comment:表情地址"`
+	Width     int       `gorm:"column:width;not null;default:0;comment:宽度"`
+	Height    int       `gorm:"column:height;not null;default:0;comment:高度"`
+	Sort      int       `gorm:"column:sort;not null;default:0;comment:排序"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;comment:创建时间"`
+}
+
+func (StickerItem) TableName() string {
+	return "sticker_item"
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// ThreadRead 记录用户在话题中的已读位置，用于计算话题未读数
+type ThreadRead struct {
+	Id         int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	UserId     string    `gorm:"column:user_id;uniqueIndex:idx_user_thread;type:char(20);not null;comment:用户uuid"`
+	ThreadId   string    `gorm:"column:thread_id;uniqueIndex:idx_user_thread;type:char(20);not null;comment:话题根消息uuid"`
+	LastReadAt time.Time `gorm:"column:last_read_at;type:datetime;not null;comment:最近已读时间"`
+}
+
+func (ThreadRead) TableName() string {
+	return "thread_read"
+}
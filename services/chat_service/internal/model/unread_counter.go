@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// UnreadCounter 用户在会话中的未读数，实时数据在redis中，这里是定期写回的持久化副本
+type UnreadCounter struct {
+	Id        int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	UserId    string    `gorm:"column:user_id;uniqueIndex:idx_user_target;type:char(20);not null;comment:用户uuid"`
+	TargetId  string    `gorm:"column:target_id;uniqueIndex:idx_user_target;type:char(20);not null;comment:会话对方的用户uuid或群聊uuid"`
+	Count     int       `gorm:"column:count;not null;default:0;comment:未读数"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null;comment:更新时间"`
+}
+
+func (UnreadCounter) TableName() string {
+	return "unread_counter"
+}
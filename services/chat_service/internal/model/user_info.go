@@ -0,0 +1,27 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserInfo 用户表，由user_service维护，聊天服务只读取和更新在线时间
+type UserInfo struct {
+	Id            int64          `gorm:"column:id;primaryKey;comment:自增id"`
+	Uuid          string         `gorm:"column:uuid;uniqueIndex;type:char(20);comment:用户唯一id"`
+	Nickname      string         `gorm:"column:nickname;type:varchar(20);not null;comment:昵称"`
+	Avatar        string         `gorm:"column:avatar;type:char(255);not null;comment:头像"`
+	CreatedAt     time.Time      `gorm:"column:created_at;index;type:datetime;not null;comment:创建时间"`
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at;type:datetime;comment:删除时间"`
+	LastOnlineAt  sql.NullTime   `gorm:"column:last_online_at;type:datetime;comment:上次登录时间"`
+	LastOfflineAt sql.NullTime   `gorm:"column:last_offline_at;type:datetime;comment:最近离线时间"`
+	IsAdmin       int8           `gorm:"column:is_admin;not null;comment:是否是管理员，0.不是，1.是"`
+	IsBot         int8           `gorm:"column:is_bot;not null;default:0;comment:是否是机器人，0.不是，1.是"`
+	Status        int8           `gorm:"column:status;index;not null;comment:状态，0.正常，1.禁用"`
+}
+
+func (UserInfo) TableName() string {
+	return "user_info"
+}
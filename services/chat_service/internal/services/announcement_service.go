@@ -0,0 +1,227 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/announcement/announcement_status_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/announcement/announcement_target_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/user_info/user_status_enum"
+	"github.com/puoxiu/gogochat/pkg/random"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+	"gorm.io/gorm"
+)
+
+type announcementService struct {
+}
+
+var AnnouncementService = new(announcementService)
+
+const (
+	announcementTitleMaxLen   = 50   // 公告标题最大字符数
+	announcementContentMaxLen = 2000 // 公告内容最大字符数
+)
+
+// CreateAnnouncement 发布系统公告，由后台按批次投递，返回初始进度
+func (a *announcementService) CreateAnnouncement(req request.CreateAnnouncementRequest) (string, *respond.AnnouncementProgressRespond, int) {
+	if msg, ret := a.checkAdmin(req.OwnerId); ret != 0 {
+		return msg, nil, ret
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" || utf8.RuneCountInString(title) > announcementTitleMaxLen {
+		return fmt.Sprintf("标题不能为空且不能超过%d个字", announcementTitleMaxLen), nil, -2
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" || utf8.RuneCountInString(content) > announcementContentMaxLen {
+		return fmt.Sprintf("内容不能为空且不能超过%d个字", announcementContentMaxLen), nil, -2
+	}
+	var filter respond.AnnouncementFilterRespond
+	switch req.TargetType {
+	case announcement_target_enum.All:
+	case announcement_target_enum.Groups:
+		filter.GroupIds = uniqueStrings(req.GroupIds)
+		if len(filter.GroupIds) == 0 {
+			return "请选择要发送的群聊", nil, -2
+		}
+	case announcement_target_enum.Filter:
+		if req.CreatedAfter == "" && req.CreatedBefore == "" && req.Status == nil {
+			return "请至少设置一个筛选条件", nil, -2
+		}
+		for _, t := range []string{req.CreatedAfter, req.CreatedBefore} {
+			if t == "" {
+				continue
+			}
+			if _, err := time.ParseInLocation("2006-01-02 15:04:05", t, time.Local); err != nil {
+				return "注册时间格式错误", nil, -2
+			}
+		}
+		filter.CreatedAfter = req.CreatedAfter
+		filter.CreatedBefore = req.CreatedBefore
+		filter.Status = req.Status
+	default:
+		return "不支持的投递范围", nil, -2
+	}
+	query, err := chat.AnnouncementRecipients(req.TargetType, &filter)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	var total int64
+	if res := query.Count(&total); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if total == 0 {
+		return "没有符合条件的用户", nil, -2
+	}
+	filterByte, err := json.Marshal(filter)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	announcement := model.Announcement{
+		Uuid:         fmt.Sprintf("A%s", random.GetNowAndLenRandomString(11)),
+		AdminId:      req.OwnerId,
+		Title:        title,
+		Content:      content,
+		TargetType:   req.TargetType,
+		TargetFilter: string(filterByte),
+		Status:       announcement_status_enum.Pending,
+		TotalCnt:     int(total),
+		CreatedAt:    time.Now(),
+	}
+	if res := dao.GormDB.Create(&announcement); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "公告已提交发送", a.progressRespond(&announcement), 0
+}
+
+// GetAnnouncementProgress 查询公告的投递进度
+func (a *announcementService) GetAnnouncementProgress(ownerId, announcementId string) (string, *respond.AnnouncementProgressRespond, int) {
+	if msg, ret := a.checkAdmin(ownerId); ret != 0 {
+		return msg, nil, ret
+	}
+	var announcement model.Announcement
+	if res := dao.GormDB.Where("uuid = ?", announcementId).First(&announcement); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "公告不存在", nil, -2
+		}
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "获取发送进度成功", a.progressRespond(&announcement), 0
+}
+
+// GetMyAnnouncementList 获取自己收到的公告，按投递时间倒序
+func (a *announcementService) GetMyAnnouncementList(ownerId string, page, pageSize int) (string, *respond.GetMyAnnouncementListRespond, int) {
+	page, pageSize = normalizePage(page, pageSize)
+	query := dao.GormDB.Model(&model.UserAnnouncement{}).Where("user_id = ?", ownerId)
+	var total int64
+	if res := query.Session(&gorm.Session{}).Count(&total); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	var unreadCnt int64
+	if res := query.Session(&gorm.Session{}).Where("read_at IS NULL").Count(&unreadCnt); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	var inboxList []model.UserAnnouncement
+	if res := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&inboxList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rsp := &respond.GetMyAnnouncementListRespond{
+		Total:         total,
+		UnreadCnt:     unreadCnt,
+		Announcements: make([]respond.AnnouncementRespond, 0, len(inboxList)),
+	}
+	if len(inboxList) == 0 {
+		return "获取公告成功", rsp, 0
+	}
+	announcementIds := make([]string, 0, len(inboxList))
+	for _, inbox := range inboxList {
+		announcementIds = append(announcementIds, inbox.AnnouncementId)
+	}
+	var announcementList []model.Announcement
+	if res := dao.GormDB.Where("uuid IN ?", announcementIds).Find(&announcementList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	announcementMap := make(map[string]*model.Announcement, len(announcementList))
+	for i := range announcementList {
+		announcementMap[announcementList[i].Uuid] = &announcementList[i]
+	}
+	for _, inbox := range inboxList {
+		announcement, ok := announcementMap[inbox.AnnouncementId]
+		if !ok {
+			continue
+		}
+		rsp.Announcements = append(rsp.Announcements, respond.AnnouncementRespond{
+			AnnouncementId: announcement.Uuid,
+			Title:          announcement.Title,
+			Content:        announcement.Content,
+			Read:           inbox.ReadAt.Valid,
+			CreatedAt:      announcement.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return "获取公告成功", rsp, 0
+}
+
+// ReadAnnouncement 标记公告为已读
+func (a *announcementService) ReadAnnouncement(ownerId string, announcementIds []string) (string, int) {
+	announcementIds = uniqueStrings(announcementIds)
+	if len(announcementIds) == 0 {
+		return "请选择公告", -2
+	}
+	if res := dao.GormDB.Model(&model.UserAnnouncement{}).
+		Where("user_id = ? AND announcement_id IN ? AND read_at IS NULL", ownerId, announcementIds).
+		Update("read_at", time.Now()); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	return "已读成功", 0
+}
+
+// checkAdmin 校验操作人是正常状态的管理员
+func (a *announcementService) checkAdmin(ownerId string) (string, int) {
+	var user model.UserInfo
+	if res := dao.GormDB.Where("uuid = ?", ownerId).First(&user); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "用户不存在", -2
+		}
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if user.IsAdmin != 1 || user.Status != user_status_enum.NORMAL {
+		return "只有管理员可以发布公告", -2
+	}
+	return "", 0
+}
+
+// progressRespond 由公告记录构造进度响应
+func (a *announcementService) progressRespond(announcement *model.Announcement) *respond.AnnouncementProgressRespond {
+	rsp := &respond.AnnouncementProgressRespond{
+		AnnouncementId: announcement.Uuid,
+		Status:         announcement.Status,
+		TotalCnt:       announcement.TotalCnt,
+		SentCnt:        announcement.SentCnt,
+		OnlineCnt:      announcement.OnlineCnt,
+		CreatedAt:      announcement.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if announcement.FinishedAt.Valid {
+		rsp.FinishedAt = announcement.FinishedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	return rsp
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/puoxiu/gogochat/pkg/bottoken"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/user_info/user_status_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+	"gorm.io/gorm"
+)
+
+type botService struct {
+}
+
+var BotService = new(botService)
+
+// Authenticate 按令牌的哈希查找机器人，机器人用户被禁用或删除时鉴权失败
+func (b *botService) Authenticate(token string) (*model.Bot, string, int) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, "缺少机器人令牌", -2
+	}
+	var bot model.Bot
+	if res := dao.GormDB.Where("token_hash = ?", bottoken.Hash(token)).First(&bot); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, "机器人令牌无效", -2
+		}
+		zlog.Error(res.Error.Error())
+		return nil, constants.SYSTEM_ERROR, -1
+	}
+	var botUser model.UserInfo
+	if res := dao.GormDB.Where("uuid = ?", bot.Uuid).First(&botUser); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, "机器人令牌无效", -2
+		}
+		zlog.Error(res.Error.Error())
+		return nil, constants.SYSTEM_ERROR, -1
+	}
+	if botUser.IsBot != 1 || botUser.Status != user_status_enum.NORMAL {
+		return nil, "机器人已被禁用", -2
+	}
+	return &bot, "", 0
+}
+
+// SendMessage 机器人发送消息，校验通过后投递到正常的消息通道，和用户发送的消息走同样的处理流程
+// 单聊只能发给已添加机器人的好友，群聊需要机器人在群中
+func (b *botService) SendMessage(token string, req request.BotSendMessageRequest) (string, int) {
+	bot, msg, ret := b.Authenticate(token)
+	if ret != 0 {
+		return msg, ret
+	}
+	sessionId, msg := checkSendTarget(bot.Uuid, req.ReceiveId)
+	if msg != "" {
+		if msg == constants.SYSTEM_ERROR {
+			return msg, -1
+		}
+		return msg, -2
+	}
+	var botUser model.UserInfo
+	if res := dao.GormDB.Where("uuid = ?", bot.Uuid).First(&botUser); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	message := request.ChatMessageRequest{
+		SessionId:   sessionId,
+		Type:        req.Type,
+		Content:     req.Content,
+		Url:         req.Url,
+		SendId:      bot.Uuid,
+		SendName:    botUser.Nickname,
+		SendAvatar:  botUser.Avatar,
+		ReceiveId:   req.ReceiveId,
+		FileSize:    req.FileSize,
+		FileType:    req.FileType,
+		FileName:    req.FileName,
+		ReplyTo:     req.ReplyTo,
+		Mentions:    req.Mentions,
+		Extra:       req.Extra,
+		ClientMsgId: req.ClientMsgId,
+	}
+	if err := chat.ValidateMessageRequest(&message); err != nil {
+		return err.Error(), -2
+	}
+	if err := chat.SubmitBotMessage(&message); err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	return "发送成功", 0
+}
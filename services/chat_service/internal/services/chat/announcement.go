@@ -0,0 +1,214 @@
+package chat
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/announcement/announcement_status_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/announcement/announcement_target_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const EventAnnouncement = "announcement" // 系统公告
+
+const (
+	announcementScanInterval  = time.Second     // 扫描待发送公告的间隔
+	announcementBatchInterval = time.Second     // 两批投递之间的间隔，控制投递速率
+	announcementClaimTimeout  = 2 * time.Minute // 超过该时间没有投递进度，视为领取的实例已宕机，由其他实例接着投递
+)
+
+// AnnouncementRecipients 按投递范围构造目标用户的查询，创建公告时统计人数和投递时分批读取共用
+func AnnouncementRecipients(targetType int8, filter *respond.AnnouncementFilterRespond) (*gorm.DB, error) {
+	query := dao.GormDB.Model(&model.UserInfo{})
+	switch targetType {
+	case announcement_target_enum.All:
+		return query, nil
+	case announcement_target_enum.Groups:
+		var groupList []model.GroupInfo
+		if res := dao.GormDB.Where("uuid IN ?", filter.GroupIds).Find(&groupList); res.Error != nil {
+			return nil, res.Error
+		}
+		var memberIds []string
+		seen := make(map[string]bool)
+		for _, group := range groupList {
+			var members []string
+			if err := json.Unmarshal(group.Members, &members); err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				if !seen[member] {
+					seen[member] = true
+					memberIds = append(memberIds, member)
+				}
+			}
+		}
+		if len(memberIds) == 0 {
+			return query.Where("1 = 0"), nil
+		}
+		return query.Where("uuid IN ?", memberIds), nil
+	case announcement_target_enum.Filter:
+		if filter.CreatedAfter != "" {
+			createdAfter, err := time.ParseInLocation("2006-01-02 15:04:05", filter.CreatedAfter, time.Local)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where("created_at >= ?", createdAfter)
+		}
+		if filter.CreatedBefore != "" {
+			createdBefore, err := time.ParseInLocation("2006-01-02 15:04:05", filter.CreatedBefore, time.Local)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where("created_at <= ?", createdBefore)
+		}
+		if filter.Status != nil {
+			query = query.Where("status = ?", *filter.Status)
+		}
+		return query, nil
+	}
+	return nil, errors.New("不支持的投递范围")
+}
+
+// StartAnnouncementWorker 启动公告投递，每个实例都会运行
+// 同一时间一条公告只会被一个实例领取，按批次写入收件箱并推送给在线用户，进度保存在公告记录中
+func StartAnnouncementWorker() {
+	go func() {
+		ticker := time.NewTicker(announcementScanInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			runAnnouncements()
+		}
+	}()
+}
+
+// runAnnouncements 领取一条待发送或投递中断的公告并投递完
+func runAnnouncements() {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("announcement worker panic: %v", r))
+		}
+	}()
+	staleBefore := time.Now().Add(-announcementClaimTimeout)
+	claimable := dao.GormDB.Where("status = ? OR (status = ? AND claimed_at < ?)",
+		announcement_status_enum.Pending, announcement_status_enum.Sending, staleBefore)
+	var announcement model.Announcement
+	if res := claimable.Order("id ASC").First(&announcement); res.Error != nil {
+		if !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			zlog.Error(res.Error.Error())
+		}
+		return
+	}
+	// 条件更新领取，多个实例同时领取时只有一个能成功
+	res := dao.GormDB.Model(&model.Announcement{}).
+		Where("id = ? AND (status = ? OR (status = ? AND claimed_at < ?))", announcement.Id,
+			announcement_status_enum.Pending, announcement_status_enum.Sending, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     announcement_status_enum.Sending,
+			"claimed_at": time.Now(),
+		})
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	if res.RowsAffected == 0 {
+		return
+	}
+	sendAnnouncement(&announcement)
+}
+
+// sendAnnouncement 从上次的进度开始分批投递公告
+func sendAnnouncement(announcement *model.Announcement) {
+	var filter respond.AnnouncementFilterRespond
+	if announcement.TargetFilter != "" {
+		if err := json.Unmarshal([]byte(announcement.TargetFilter), &filter); err != nil {
+			zlog.Error(err.Error())
+			return
+		}
+	}
+	eventRsp := respond.AnnouncementEventRespond{
+		Event: EventAnnouncement,
+		Announcement: respond.AnnouncementRespond{
+			AnnouncementId: announcement.Uuid,
+			Title:          announcement.Title,
+			Content:        announcement.Content,
+			CreatedAt:      announcement.CreatedAt.Format("2006-01-02 15:04:05"),
+		},
+	}
+	jsonMessage, err := json.Marshal(eventRsp)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	eventBack := &MessageBack{Message: jsonMessage}
+	lastUserId := announcement.LastUserId
+	for {
+		query, err := AnnouncementRecipients(announcement.TargetType, &filter)
+		if err != nil {
+			zlog.Error(err.Error())
+			return
+		}
+		var userList []model.UserInfo
+		if res := query.Select("id", "uuid").Where("id > ?", lastUserId).
+			Order("id ASC").Limit(constants.ANNOUNCE_BATCH_SIZE).Find(&userList); res.Error != nil {
+			zlog.Error(res.Error.Error())
+			return
+		}
+		if len(userList) > 0 {
+			now := time.Now()
+			inboxList := make([]model.UserAnnouncement, 0, len(userList))
+			for _, user := range userList {
+				inboxList = append(inboxList, model.UserAnnouncement{
+					UserId:         user.Uuid,
+					AnnouncementId: announcement.Uuid,
+					CreatedAt:      now,
+				})
+			}
+			// 中断后重新投递时可能重复写入同一批用户，由唯一索引去重
+			if res := dao.GormDB.Clauses(clause.OnConflict{DoNothing: true}).Create(&inboxList); res.Error != nil {
+				zlog.Error(res.Error.Error())
+				return
+			}
+			onlineCnt := 0
+			hub := currentHub()
+			for _, user := range userList {
+				if client, ok := hub.GetClient(user.Uuid); ok {
+					sendEventToClient(client, eventBack)
+					onlineCnt++
+				}
+			}
+			lastUserId = userList[len(userList)-1].Id
+			if res := dao.GormDB.Model(&model.Announcement{}).Where("id = ?", announcement.Id).
+				Updates(map[string]interface{}{
+					"last_user_id": lastUserId,
+					"sent_cnt":     gorm.Expr("sent_cnt + ?", len(userList)),
+					"online_cnt":   gorm.Expr("online_cnt + ?", onlineCnt),
+					"claimed_at":   now,
+				}); res.Error != nil {
+				zlog.Error(res.Error.Error())
+				return
+			}
+		}
+		if len(userList) < constants.ANNOUNCE_BATCH_SIZE {
+			break
+		}
+		time.Sleep(announcementBatchInterval)
+	}
+	// 发送期间新注册或入群的用户也会收到，完成时以实际投递人数为准
+	if res := dao.GormDB.Model(&model.Announcement{}).Where("id = ?", announcement.Id).
+		Updates(map[string]interface{}{
+			"status":      announcement_status_enum.Done,
+			"total_cnt":   gorm.Expr("sent_cnt"),
+			"finished_at": sql.NullTime{Time: time.Now(), Valid: true},
+		}); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+}
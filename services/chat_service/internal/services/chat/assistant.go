@@ -0,0 +1,341 @@
+package chat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_status_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/random"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/config"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+const (
+	EventAssistantDelta = "assistant_delta" // AI助手流式回复的增量内容
+	EventAssistantError = "assistant_error" // AI助手回复失败
+)
+
+const (
+	assistantWorkers        = 4                // 同时生成回复的协程数，即同时调用接口的请求数
+	assistantQueueSize      = 1000             // 待回复的提问队列长度
+	assistantDefaultTimeout = 60 * time.Second // 未配置时单次回复的超时
+	assistantDefaultHistory = 20               // 未配置时作为上下文的最近消息条数
+	assistantMaxContentLen  = 5000             // 回复内容最大字符数，超过后截断
+	assistantMaxLineSize    = 1 << 20          // 流式响应中单行的最大长度
+)
+
+var assistantQueue = make(chan model.Message, assistantQueueSize)
+
+// assistantClient 调用AI接口的http客户端，超时由每次请求的context控制
+var assistantClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     30 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+}
+
+// chatCompletionMessage OpenAI兼容接口的对话消息
+type chatCompletionMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model    string                  `json:"model"`
+	Messages []chatCompletionMessage `json:"messages"`
+	Stream   bool                    `json:"stream"`
+}
+
+// chatCompletionChunk 流式响应中每个data行的内容，只解析用到的字段
+type chatCompletionChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// StartAssistantWorker 启动AI助手的回复协程
+func StartAssistantWorker() {
+	for i := 0; i < assistantWorkers; i++ {
+		go func() {
+			for message := range assistantQueue {
+				replyAssistant(&message)
+			}
+		}()
+	}
+}
+
+// enqueueAssistantReply 发给AI助手的文本消息落库后加入回复队列，不阻塞消息发送
+func enqueueAssistantReply(message *model.Message) {
+	if message.Uuid == "" || message.ReceiveId != constants.AI_ASSISTANT_ID || message.Type != message_type_enum.Text {
+		return
+	}
+	select {
+	case assistantQueue <- *message:
+	default:
+		zlog.Warn("AI助手回复队列已满，丢弃: " + message.Uuid)
+		sendAssistantError(message.SendId, "", message.Uuid, "AI助手繁忙，请稍后再试")
+	}
+}
+
+// replyAssistant 以最近的聊天记录为上下文调用AI接口，增量内容实时推送给提问者，结束后保存为AI助手发出的消息
+func replyAssistant(question *model.Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("assistant panic: %v", r))
+		}
+	}()
+	aiConfig := config.AppConfig.AiConfig
+	userId := question.SendId
+	replyId := fmt.Sprintf("M%s", random.GetNowAndLenRandomString(11))
+	if aiConfig.BaseUrl == "" || aiConfig.Model == "" {
+		sendAssistantError(userId, replyId, question.Uuid, "AI助手暂未开放")
+		return
+	}
+	if reason, ok := consumeAssistantQuota(userId, aiConfig.DailyQuota); !ok {
+		sendAssistantError(userId, replyId, question.Uuid, reason)
+		return
+	}
+	messages, err := assistantContext(userId, aiConfig.SystemPrompt, aiConfig.HistoryLimit)
+	if err != nil {
+		zlog.Error(err.Error())
+		sendAssistantError(userId, replyId, question.Uuid, constants.SYSTEM_ERROR)
+		return
+	}
+	timeout := assistantDefaultTimeout
+	if aiConfig.Timeout > 0 {
+		timeout = time.Duration(aiConfig.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	seq := 0
+	var content strings.Builder
+	err = streamChatCompletion(ctx, messages, func(delta string) {
+		if content.Len()+len(delta) > assistantMaxContentLen {
+			return
+		}
+		content.WriteString(delta)
+		seq++
+		sendAssistantEvent(userId, respond.AssistantDeltaRespond{
+			Event:     EventAssistantDelta,
+			MessageId: replyId,
+			ReplyTo:   question.Uuid,
+			Seq:       seq,
+			Delta:     delta,
+		})
+	})
+	if err != nil {
+		zlog.Warn(fmt.Sprintf("AI助手回复%s失败: %s", question.Uuid, err.Error()))
+		reason := "AI助手暂时无法回复，请稍后再试"
+		if errors.Is(err, context.DeadlineExceeded) {
+			reason = "AI助手回复超时"
+		}
+		sendAssistantError(userId, replyId, question.Uuid, reason)
+		// 已经生成的部分内容仍然保存，避免用户看到的回复凭空消失
+		if content.Len() == 0 {
+			return
+		}
+	}
+	if strings.TrimSpace(content.String()) == "" {
+		if err == nil {
+			sendAssistantError(userId, replyId, question.Uuid, "AI助手没有给出回复")
+		}
+		return
+	}
+	saveAssistantReply(replyId, userId, content.String())
+}
+
+// consumeAssistantQuota 计入用户当天的一次提问，超过每天的次数时返回false和提示，dailyQuota为0表示不限制
+func consumeAssistantQuota(userId string, dailyQuota int) (string, bool) {
+	if dailyQuota <= 0 {
+		return "", true
+	}
+	key := "ai_quota_" + userId + "_" + time.Now().Format("20060102")
+	cnt, err := cache.GetGlobalCache().IncrKeyEx(key, 24*time.Hour)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, false
+	}
+	if cnt > int64(dailyQuota) {
+		return fmt.Sprintf("今天已向AI助手提问%d次，请明天再来", dailyQuota), false
+	}
+	return "", true
+}
+
+// assistantContext 由最近的聊天记录构造对话上下文，已过期的阅后即焚消息不参与
+func assistantContext(userId, systemPrompt string, historyLimit int) ([]chatCompletionMessage, error) {
+	if historyLimit <= 0 {
+		historyLimit = assistantDefaultHistory
+	}
+	var historyList []model.Message
+	if res := dao.GormDB.
+		Where("((send_id = ? AND receive_id = ?) OR (send_id = ? AND receive_id = ?)) AND type = ?",
+			userId, constants.AI_ASSISTANT_ID, constants.AI_ASSISTANT_ID, userId, message_type_enum.Text).
+		Where("expire_at IS NULL OR expire_at > ?", time.Now()).
+		Order("id DESC").Limit(historyLimit).Find(&historyList); res.Error != nil {
+		return nil, res.Error
+	}
+	messages := make([]chatCompletionMessage, 0, len(historyList)+1)
+	if systemPrompt != "" {
+		messages = append(messages, chatCompletionMessage{Role: "system", Content: systemPrompt})
+	}
+	for i := len(historyList) - 1; i >= 0; i-- {
+		if historyList[i].Content == "" {
+			continue
+		}
+		role := "user"
+		if historyList[i].SendId == constants.AI_ASSISTANT_ID {
+			role = "assistant"
+		}
+		messages = append(messages, chatCompletionMessage{Role: role, Content: historyList[i].Content})
+	}
+	return messages, nil
+}
+
+// streamChatCompletion 以流式方式调用chat completions接口，每收到一段增量内容就回调一次
+func streamChatCompletion(ctx context.Context, messages []chatCompletionMessage, onDelta func(string)) error {
+	aiConfig := config.AppConfig.AiConfig
+	body, err := json.Marshal(chatCompletionRequest{
+		Model:    aiConfig.Model,
+		Messages: messages,
+		Stream:   true,
+	})
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimRight(aiConfig.BaseUrl, "/") + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if aiConfig.ApiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+aiConfig.ApiKey)
+	}
+	resp, err := assistantClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("接口返回状态码%d: %s", resp.StatusCode, string(errBody))
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), assistantMaxLineSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return nil
+		}
+		var chunk chatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return err
+		}
+		if chunk.Error != nil {
+			return errors.New(chunk.Error.Message)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				onDelta(choice.Delta.Content)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		// 超时导致的读取失败按超时处理
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// saveAssistantReply 把完整的回复保存为AI助手发给用户的消息，并按正常消息下发
+func saveAssistantReply(replyId, userId, content string) {
+	var assistant model.UserInfo
+	if res := dao.GormDB.Where("uuid = ?", constants.AI_ASSISTANT_ID).First(&assistant); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	sessionId := ""
+	if sessionClient, err := clients.GetGlobalSessionClient(); err != nil {
+		zlog.Error("获取会话客户端失败: " + err.Error())
+	} else if resp := sessionClient.CreateSessionIfNotExist(constants.AI_ASSISTANT_ID, userId); resp == nil || resp.Code != 0 {
+		zlog.Error("创建AI助手会话失败: " + userId)
+	} else {
+		sessionId = resp.SessionId
+	}
+	message := model.Message{
+		Uuid:       replyId,
+		SessionId:  sessionId,
+		Type:       message_type_enum.Text,
+		Content:    content,
+		SendId:     constants.AI_ASSISTANT_ID,
+		SendName:   assistant.Nickname,
+		SendAvatar: assistant.Avatar,
+		ReceiveId:  userId,
+		FileSize:   "0B",
+		Status:     message_status_enum.Unsent,
+		CreatedAt:  time.Now(),
+	}
+	ApplyDisappearTimer(&message)
+	if res := dao.GormDB.Create(&message); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	DeliverMessage(&message)
+}
+
+// sendAssistantError 通知提问者AI助手回复失败
+func sendAssistantError(userId, replyId, questionId, reason string) {
+	sendAssistantEvent(userId, respond.AssistantErrorRespond{
+		Event:     EventAssistantError,
+		MessageId: replyId,
+		ReplyTo:   questionId,
+		Reason:    reason,
+	})
+}
+
+// sendAssistantEvent 把AI助手的事件推送给在线的提问者
+// 增量帧在发送通道满时会被丢弃，回复结束后下发的完整消息会覆盖客户端拼接的内容
+func sendAssistantEvent(userId string, event interface{}) {
+	client, ok := currentHub().GetClient(userId)
+	if !ok {
+		return
+	}
+	jsonMessage, err := json.Marshal(event)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	sendEventToClient(client, &MessageBack{Message: jsonMessage})
+}
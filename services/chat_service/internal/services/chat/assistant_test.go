@@ -0,0 +1,202 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/config"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+// counterCache 只实现计数的缓存，其他方法不会被用到
+type counterCache struct {
+	cache.Cache
+	mutex  sync.Mutex
+	counts map[string]int64
+}
+
+func (c *counterCache) IncrKeyEx(key string, timeout time.Duration) (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.counts[key]++
+	return c.counts[key], nil
+}
+
+func useTestAiConfig(t *testing.T, aiConfig config.AiConfig) {
+	t.Helper()
+	appConfig := config.AppConfig
+	config.AppConfig = &config.Config{AiConfig: aiConfig}
+	config.AppConfig.KafkaConfig.MessageMode = "channel"
+	t.Cleanup(func() {
+		config.AppConfig = appConfig
+	})
+}
+
+func TestStreamChatCompletion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" || r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		if req.Model != "test-model" || !req.Stream || len(req.Messages) != 2 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"你好\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		fmt.Fprint(w, "data:{\"choices\":[{\"delta\":{\"content\":\"，世界\"},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"结束后的内容\"}}]}\n\n")
+	}))
+	defer srv.Close()
+	useTestAiConfig(t, config.AiConfig{BaseUrl: srv.URL + "/v1/", ApiKey: "test-key", Model: "test-model"})
+
+	var deltas []string
+	err := streamChatCompletion(context.Background(), []chatCompletionMessage{
+		{Role: "system", Content: "你是助手"},
+		{Role: "user", Content: "你好"},
+	}, func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(deltas, "|") != "你好|，世界" {
+		t.Fatalf("deltas = %q", deltas)
+	}
+}
+
+func TestStreamChatCompletionErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status/chat/completions":
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		case "/chunk/chat/completions":
+			fmt.Fprint(w, "data: {\"error\":{\"message\":\"模型不可用\"}}\n\n")
+		case "/invalid/chat/completions":
+			fmt.Fprint(w, "data: {not json}\n\n")
+		}
+	}))
+	defer srv.Close()
+
+	for path, want := range map[string]string{
+		"/status":  "429",
+		"/chunk":   "模型不可用",
+		"/invalid": "invalid character",
+	} {
+		useTestAiConfig(t, config.AiConfig{BaseUrl: srv.URL + path, Model: "test-model"})
+		err := streamChatCompletion(context.Background(), nil, func(string) {})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", path, want, err)
+		}
+	}
+}
+
+func TestStreamChatCompletionTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"部分\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	useTestAiConfig(t, config.AiConfig{BaseUrl: srv.URL, Model: "test-model"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var deltas []string
+	err := streamChatCompletion(ctx, nil, func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	// 超时前收到的增量内容已经回调，由调用方保存
+	if len(deltas) != 1 || deltas[0] != "部分" {
+		t.Fatalf("deltas = %q", deltas)
+	}
+}
+
+func TestConsumeAssistantQuota(t *testing.T) {
+	cache.Init(&counterCache{counts: make(map[string]int64)})
+
+	for i := 1; i <= 2; i++ {
+		if reason, ok := consumeAssistantQuota("U1", 2); !ok {
+			t.Fatalf("question %d rejected: %s", i, reason)
+		}
+	}
+	reason, ok := consumeAssistantQuota("U1", 2)
+	if ok || !strings.Contains(reason, "2次") {
+		t.Fatalf("third question should be rejected, got %v %q", ok, reason)
+	}
+	// 其他用户的次数单独计算
+	if _, ok := consumeAssistantQuota("U2", 2); !ok {
+		t.Fatal("quota should be per user")
+	}
+	// 0表示不限制，不计数
+	if _, ok := consumeAssistantQuota("U1", 0); !ok {
+		t.Fatal("zero quota should not limit")
+	}
+}
+
+// 超过次数的提问不请求AI接口，直接通知提问者
+func TestReplyAssistantQuotaExceeded(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+	useTestAiConfig(t, config.AiConfig{BaseUrl: srv.URL, Model: "test-model", DailyQuota: 1})
+	cache.Init(&counterCache{counts: map[string]int64{
+		"ai_quota_U1_" + time.Now().Format("20060102"): 1,
+	}})
+	asker := newTestClient("U1")
+	ChatServer.mutex.Lock()
+	ChatServer.Clients[asker.Uuid] = asker
+	ChatServer.mutex.Unlock()
+	t.Cleanup(func() {
+		ChatServer.RemoveClient(asker.Uuid)
+	})
+
+	replyAssistant(&model.Message{Uuid: "M1", SendId: asker.Uuid, ReceiveId: constants.AI_ASSISTANT_ID, Type: message_type_enum.Text})
+
+	select {
+	case messageBack := <-asker.SendBack:
+		var event respond.AssistantErrorRespond
+		if err := json.Unmarshal(messageBack.Message, &event); err != nil {
+			t.Fatal(err)
+		}
+		if event.Event != EventAssistantError || event.ReplyTo != "M1" || !strings.Contains(event.Reason, "1次") {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+	default:
+		t.Fatal("asker did not get the quota error")
+	}
+	if requests != 0 {
+		t.Fatalf("AI api called %d times", requests)
+	}
+}
@@ -0,0 +1,384 @@
+package chat
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/enum/bot/bot_delivery_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/bot/bot_event_status_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+const BotEventMessage = "message" // 发给机器人或在群聊中@机器人的消息
+
+const (
+	botNotifyWorkers      = 2               // 生成机器人事件的协程数
+	botNotifyQueueSize    = 1000            // 待生成事件的消息队列长度
+	botNotifyWait         = time.Second     // 队列满时等待空位的最长时间
+	botScanInterval       = time.Second     // 扫描待投递事件的间隔
+	botScanBatchSize      = 100             // 每次扫描最多投递的webhook事件数
+	botWebhookWorkers     = 8               // 同时进行的webhook请求数
+	botWebhookTimeout     = 5 * time.Second // 单次webhook请求的超时
+	botWebhookMaxAttempts = 5               // webhook最多尝试次数
+	botWebhookRetryBase   = 5 * time.Second // 重试间隔的基数，每次失败后翻倍
+	botClaimTimeout       = time.Minute     // 领取后超过该时间仍未完成，视为领取的实例已宕机，重新投递
+	botEventExpire        = 24 * time.Hour  // 超过该时间仍未投递的事件不再投递，如gRPC流长时间没有连接
+	botWebhookUserAgent   = "gogochat-bot-webhook/1.0"
+)
+
+var botNotifyQueue = make(chan model.Message, botNotifyQueueSize)
+
+// botWebhookClient 调用webhook的http客户端，连接经过safeDialContext，不跟随重定向
+var botWebhookClient = &http.Client{
+	Timeout: botWebhookTimeout,
+	Transport: &http.Transport{
+		Proxy:                 nil, // 不走代理，代理会绕过对目标地址的校验
+		DialContext:           safeDialContext,
+		TLSHandshakeTimeout:   botWebhookTimeout,
+		ResponseHeaderTimeout: botWebhookTimeout,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       30 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// StartBotWorker 启动机器人事件的生成协程和webhook投递，每个实例都会运行
+// 事件先写入bot_event表，再由各实例通过条件更新领取投递，实例宕机后由其他实例继续投递
+func StartBotWorker() {
+	for i := 0; i < botNotifyWorkers; i++ {
+		go func() {
+			for message := range botNotifyQueue {
+				createBotEvents(&message)
+			}
+		}()
+	}
+	go func() {
+		ticker := time.NewTicker(botScanInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			runBotWebhooks()
+		}
+	}()
+}
+
+// notifyBots 消息落库后，发给机器人或在群聊中@了机器人时生成机器人事件，不阻塞消息发送
+func notifyBots(message *model.Message) {
+	if message.Uuid == "" || message.Type == message_type_enum.AudioOrVideo || message.Type == message_type_enum.System {
+		return
+	}
+	// 群聊只有@到的机器人才会收到，@所有人不通知机器人
+	if message.ReceiveId[0] == 'G' && message.Mentions == "" {
+		return
+	}
+	select {
+	case botNotifyQueue <- *message:
+		return
+	default:
+	}
+	// 队列满时短暂等待，不另起协程，避免积压时协程数量失控
+	select {
+	case botNotifyQueue <- *message:
+	case <-time.After(botNotifyWait):
+		zlog.Warn("机器人事件队列已满，丢弃: " + message.Uuid)
+	}
+}
+
+// createBotEvents 为消息的每个目标机器人生成一条待投递事件
+func createBotEvents(message *model.Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("bot notify panic: %v", r))
+		}
+	}()
+	var candidates []string
+	if message.ReceiveId[0] == 'G' {
+		if err := json.Unmarshal([]byte(message.Mentions), &candidates); err != nil {
+			zlog.Error(err.Error())
+			return
+		}
+	} else {
+		candidates = []string{message.ReceiveId}
+	}
+	var botList []model.Bot
+	if res := dao.GormDB.Where("uuid IN ?", append(candidates, message.SendId)).Find(&botList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	for _, bot := range botList {
+		// 机器人发出的消息不再通知其他机器人，避免机器人之间互相回复形成循环
+		if bot.Uuid == message.SendId {
+			return
+		}
+	}
+	if len(botList) == 0 {
+		return
+	}
+	now := time.Now()
+	eventList := make([]model.BotEvent, 0, len(botList))
+	for _, bot := range botList {
+		conversationId := message.ReceiveId
+		if conversationId[0] == 'U' {
+			conversationId = message.SendId
+		}
+		payload, err := json.Marshal(respond.BotMessageEventRespond{
+			Event:          BotEventMessage,
+			BotId:          bot.Uuid,
+			ConversationId: conversationId,
+			Message:        respond.NewGetMessageListRespond(message),
+		})
+		if err != nil {
+			zlog.Error(err.Error())
+			return
+		}
+		eventList = append(eventList, model.BotEvent{
+			BotId:         bot.Uuid,
+			DeliveryMode:  bot.DeliveryMode,
+			Event:         BotEventMessage,
+			Payload:       string(payload),
+			Status:        bot_event_status_enum.Pending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	if res := dao.GormDB.Create(&eventList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+}
+
+// runBotWebhooks 回收超时的领取，领取到期的webhook事件并投递
+func runBotWebhooks() {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("bot webhook panic: %v", r))
+		}
+	}()
+	now := time.Now()
+	if res := dao.GormDB.Model(&model.BotEvent{}).
+		Where("status = ? AND claimed_at < ?", bot_event_status_enum.Sending, now.Add(-botClaimTimeout)).
+		Update("status", bot_event_status_enum.Pending); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+	if res := dao.GormDB.Model(&model.BotEvent{}).
+		Where("status = ? AND created_at < ?", bot_event_status_enum.Pending, now.Add(-botEventExpire)).
+		Update("status", bot_event_status_enum.Failed); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+	var dueList []model.BotEvent
+	if res := dao.GormDB.Where("status = ? AND delivery_mode = ? AND next_attempt_at <= ?",
+		bot_event_status_enum.Pending, bot_delivery_enum.Webhook, now).
+		Order("id ASC").Limit(botScanBatchSize).Find(&dueList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, botWebhookWorkers)
+	for i := range dueList {
+		event := &dueList[i]
+		res := dao.GormDB.Model(&model.BotEvent{}).
+			Where("id = ? AND status = ?", event.Id, bot_event_status_enum.Pending).
+			Updates(map[string]interface{}{
+				"status":     bot_event_status_enum.Sending,
+				"attempts":   gorm.Expr("attempts + 1"),
+				"claimed_at": now,
+			})
+		if res.Error != nil {
+			zlog.Error(res.Error.Error())
+			continue
+		}
+		if res.RowsAffected == 0 {
+			// 已被其他实例或gRPC流领取
+			continue
+		}
+		event.Attempts++
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			deliverBotWebhook(event)
+		}()
+	}
+	wg.Wait()
+}
+
+// deliverBotWebhook 投递一条webhook事件，失败时按指数退避重试
+func deliverBotWebhook(event *model.BotEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("bot webhook panic: %v", r))
+		}
+	}()
+	var bot model.Bot
+	if res := dao.GormDB.Where("uuid = ?", event.BotId).First(&bot); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			finishBotEvent(event.Id, bot_event_status_enum.Failed)
+			return
+		}
+		zlog.Error(res.Error.Error())
+		retryBotEvent(event)
+		return
+	}
+	// 投递方式改成了gRPC流，转给流投递
+	if bot.DeliveryMode != bot_delivery_enum.Webhook {
+		if res := dao.GormDB.Model(&model.BotEvent{}).Where("id = ?", event.Id).Updates(map[string]interface{}{
+			"delivery_mode": bot.DeliveryMode,
+			"status":        bot_event_status_enum.Pending,
+		}); res.Error != nil {
+			zlog.Error(res.Error.Error())
+		}
+		return
+	}
+	if err := postBotWebhook(&bot, event); err != nil {
+		zlog.Warn(fmt.Sprintf("投递机器人事件%d失败: %s", event.Id, err.Error()))
+		retryBotEvent(event)
+		return
+	}
+	finishBotEvent(event.Id, bot_event_status_enum.Delivered)
+}
+
+// postBotWebhook 向机器人的webhook发送事件
+// 签名为HMAC-SHA256(webhook_secret, 时间戳 + "." + 请求体)，机器人据此校验请求来源并拒绝重放
+func postBotWebhook(bot *model.Bot, event *model.BotEvent) error {
+	u, err := url.Parse(bot.WebhookUrl)
+	if err != nil {
+		return err
+	}
+	if err := checkWebhookUrl(u); err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(bot.WebhookSecret))
+	mac.Write([]byte(timestamp + "." + event.Payload))
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader([]byte(event.Payload)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", botWebhookUserAgent)
+	req.Header.Set("X-Bot-Event-Id", strconv.FormatInt(event.Id, 10))
+	req.Header.Set("X-Bot-Timestamp", timestamp)
+	req.Header.Set("X-Bot-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	resp, err := botWebhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook返回状态码%d", resp.StatusCode)
+	}
+	return nil
+}
+
+// checkWebhookUrl webhook只允许https，地址是ip时必须是公网地址，域名在连接时由safeDialContext校验
+func checkWebhookUrl(u *url.URL) error {
+	if u.Scheme != "https" {
+		return errors.New("webhook地址必须是https")
+	}
+	if u.User != nil {
+		return errors.New("webhook地址中不能带有用户信息")
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !allowDialIP(ip) {
+		return errors.New("不允许访问内网地址")
+	}
+	return nil
+}
+
+// retryBotEvent 投递失败后安排下次重试，超过最大次数后标记为失败
+func retryBotEvent(event *model.BotEvent) {
+	if event.Attempts >= botWebhookMaxAttempts {
+		finishBotEvent(event.Id, bot_event_status_enum.Failed)
+		return
+	}
+	if res := dao.GormDB.Model(&model.BotEvent{}).
+		Where("id = ? AND status = ?", event.Id, bot_event_status_enum.Sending).
+		Updates(map[string]interface{}{
+			"status":          bot_event_status_enum.Pending,
+			"next_attempt_at": time.Now().Add(botWebhookRetryDelay(event.Attempts)),
+		}); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+}
+
+// botWebhookRetryDelay 第attempts次投递失败后到下次重试的间隔，每次失败后翻倍
+func botWebhookRetryDelay(attempts int) time.Duration {
+	return botWebhookRetryBase << (attempts - 1)
+}
+
+// finishBotEvent 更新领取中事件的最终状态
+func finishBotEvent(id int64, status int8) {
+	if res := dao.GormDB.Model(&model.BotEvent{}).
+		Where("id = ? AND status = ?", id, bot_event_status_enum.Sending).
+		Update("status", status); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+}
+
+// ClaimBotStreamEvents 为gRPC流领取机器人待投递的事件，按生成顺序返回
+// 投递方式切换前生成的webhook事件也由流一起投递
+func ClaimBotStreamEvents(botId string, limit int) ([]model.BotEvent, error) {
+	var pendingList []model.BotEvent
+	if res := dao.GormDB.Where("bot_id = ? AND status = ?", botId, bot_event_status_enum.Pending).
+		Order("id ASC").Limit(limit).Find(&pendingList); res.Error != nil {
+		return nil, res.Error
+	}
+	now := time.Now()
+	claimedList := make([]model.BotEvent, 0, len(pendingList))
+	for _, event := range pendingList {
+		res := dao.GormDB.Model(&model.BotEvent{}).
+			Where("id = ? AND status = ?", event.Id, bot_event_status_enum.Pending).
+			Updates(map[string]interface{}{
+				"status":     bot_event_status_enum.Sending,
+				"attempts":   gorm.Expr("attempts + 1"),
+				"claimed_at": now,
+			})
+		if res.Error != nil {
+			return claimedList, res.Error
+		}
+		if res.RowsAffected == 1 {
+			claimedList = append(claimedList, event)
+		}
+	}
+	return claimedList, nil
+}
+
+// AckBotStreamEvent gRPC流发送事件后更新状态，发送失败时放回待投递，等机器人重新连接
+func AckBotStreamEvent(id int64, sent bool) {
+	if sent {
+		finishBotEvent(id, bot_event_status_enum.Delivered)
+		return
+	}
+	finishBotEvent(id, bot_event_status_enum.Pending)
+}
+
+// SubmitBotMessage 机器人发送的消息投递到正常的消息通道，与websocket上收到的消息走同样的处理流程
+// 机器人没有websocket连接，消费时不会向发送者回显，只推送给在线的接收者
+func SubmitBotMessage(req *request.ChatMessageRequest) error {
+	jsonMessage, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return injectMessage(chatMessageKey(req.SendId, req.ReceiveId), jsonMessage)
+}
@@ -6,7 +6,7 @@ import (
 	"fmt"
 
 	"net/http"
-	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +33,44 @@ type Client struct {
 	SendTo   chan []byte       // 给server端
 	SendBack chan *MessageBack // 给前端
 	HeartBeatDone     chan struct{}
+
+	closeMutex sync.Mutex // 保护通道的关闭，退出登录后其他协程的下发直接丢弃，不会向已关闭的通道发送
+	closed     bool
+}
+
+// trySendBack 非阻塞地向客户端下发，连接已退出或通道满时返回false
+func (c *Client) trySendBack(messageBack *MessageBack) bool {
+	c.closeMutex.Lock()
+	defer c.closeMutex.Unlock()
+	if c.closed {
+		return false
+	}
+	select {
+	case c.SendBack <- messageBack:
+		return true
+	default:
+		return false
+	}
+}
+
+// closeChannels 关闭客户端的通道，读协程和心跳协程可能同时触发退出，重复调用时只关闭一次
+func (c *Client) closeChannels() {
+	c.closeMutex.Lock()
+	defer c.closeMutex.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.SendTo)
+	close(c.SendBack)
+	close(c.HeartBeatDone)
+}
+
+// deliverMessageBack 下发消息帧，失败时只记录日志，消息已落库，客户端重新拉取即可
+func deliverMessageBack(client *Client, messageBack *MessageBack) {
+	if !client.trySendBack(messageBack) {
+		zlog.Warn("客户端通道已满或已断开，消息下发失败: " + client.Uuid)
+	}
 }
 
 var upgrader = websocket.Upgrader{
@@ -62,9 +100,31 @@ func (c *Client) Read() {
 			zlog.Error(err.Error())
 			return
 		} else {
+			// 临时事件直接转发，不进入消息通道
+			var event request.WsEventRequest
+			if err := json.Unmarshal(jsonMessage, &event); err == nil && event.Event != "" {
+				if event.Event == EventPollVote {
+					handlePollVoteEvent(c, &event)
+					continue
+				}
+				if event.Event == EventSessionRead {
+					handleSessionReadEvent(c, &event)
+					continue
+				}
+				handleEphemeralEvent(c, &event)
+				continue
+			}
 			var message = request.ChatMessageRequest{}
 			if err := json.Unmarshal(jsonMessage, &message); err != nil {
 				zlog.Error(err.Error())
+				continue
+			}
+			// 发送者就是这条连接，不信任客户端填写的send_id，否则可以冒充群主执行斜杠命令
+			message.SendId = c.Uuid
+			message.Scheduled = false
+			if jsonMessage, err = json.Marshal(message); err != nil {
+				zlog.Error(err.Error())
+				continue
 			}
 			if config.AppConfig.KafkaConfig.MessageMode == "channel" {
 				// 如果server的转发channel没满，先把sendto中的给transmit
@@ -86,7 +146,7 @@ func (c *Client) Read() {
 				}
 			} else {
 				if err := mykafka.KafkaService.ChatWriter.WriteMessages(ctx, kafka.Message{
-					Key:   []byte(strconv.Itoa(config.AppConfig.KafkaConfig.Partition)),
+					Key:   chatMessageKey(message.SendId, message.ReceiveId),
 					Value: jsonMessage,
 				}); err != nil {
 					zlog.Error(err.Error())
@@ -107,6 +167,10 @@ func (c *Client) Write() {
 			zlog.Error(err.Error())
 			return
 		}
+		// 临时事件没有对应的消息记录
+		if messageBack.Uuid == "" {
+			continue
+		}
 		// 说明顺利发送，修改状态为已发送
 		if res := dao.GormDB.Model(&model.Message{}).Where("uuid = ?", messageBack.Uuid).Update("status", message_status_enum.Sent); res.Error != nil {
 			zlog.Error(res.Error.Error())
@@ -156,6 +220,7 @@ func (c *Client) Heartbeat() {
 				ClientLogout(c.Uuid)
 				return
 			}
+			refreshPresence(c.Uuid)
 		case <-c.HeartBeatDone:
 			return
 		}
@@ -186,13 +251,12 @@ func ClientLogout(clientId string) (string, int) {
 	} else {
 		KafkaChatServer.SendClientToLogout(client)
 	}
-	if err := client.Conn.Close(); err != nil {
+	err := client.Conn.Close()
+	client.closeChannels()
+	if err != nil {
 		zlog.Error(err.Error())
 		return constants.SYSTEM_ERROR, -1
 	}
-	close(client.SendTo)
-	close(client.SendBack)
-	close(client.HeartBeatDone)
 
 	// log.Printf("ClientLogout退出啦 ：%s logout", clientId)
 	return "退出成功", 0
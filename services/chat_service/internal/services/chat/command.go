@@ -0,0 +1,554 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/bot/bot_event_status_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	EventCommandResult = "command_result" // 斜杠命令的执行结果，只推送给执行人
+	BotEventCommand    = "command"        // 群成员执行了机器人注册的命令
+)
+
+const (
+	commandPrefix      = "/"
+	commandMaxArgs     = 32           // 单条命令最多的参数个数
+	muteDefaultMinutes = 10           // 禁言命令未指定时长时的默认时长
+	muteMaxMinutes     = 30 * 24 * 60 // 禁言最长时长，与user_service一致
+)
+
+// commandNamePattern 命令名只能由小写字母、数字和下划线组成
+var commandNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// commandContext 一次命令执行的上下文
+type commandContext struct {
+	req     *request.ChatMessageRequest
+	group   *model.GroupInfo
+	members []string
+	name    string
+	args    []string
+	rawArgs string // 命令名之后的原始文本，如群公告内容
+	isAdmin bool
+}
+
+// commandResult 命令的结构化执行结果
+type commandResult struct {
+	ok      bool
+	message string
+	data    interface{}
+}
+
+// builtinCommand 内置的斜杠命令，新增命令只需要在builtinCommands中注册
+type builtinCommand struct {
+	usage       string
+	description string
+	adminOnly   bool
+	run         func(ctx *commandContext) commandResult
+}
+
+var builtinCommands map[string]*builtinCommand
+
+func init() {
+	// 在init中初始化，/help需要引用命令表本身
+	builtinCommands = map[string]*builtinCommand{
+		"help": {
+			usage:       "/help [命令名]",
+			description: "查看可用的命令",
+			run:         runHelpCommand,
+		},
+		"mute": {
+			usage:       "/mute @成员 [时长]",
+			description: "禁言成员，时长如30m、2h、1d，默认10分钟",
+			adminOnly:   true,
+			run:         runMuteCommand,
+		},
+		"unmute": {
+			usage:       "/unmute @成员",
+			description: "解除成员禁言",
+			adminOnly:   true,
+			run:         runUnmuteCommand,
+		},
+		"kick": {
+			usage:       "/kick @成员 [@成员...]",
+			description: "将成员移出群聊",
+			adminOnly:   true,
+			run:         runKickCommand,
+		},
+		"notice": {
+			usage:       "/notice 公告内容",
+			description: "修改群公告，内容为空时清空公告",
+			adminOnly:   true,
+			run:         runNoticeCommand,
+		},
+		"poll": {
+			usage:       `/poll [-multi] [-anonymous] "题目" 选项1 选项2 ...`,
+			description: "发起群投票，含空格的题目或选项用引号括起来",
+			run:         runPollCommand,
+		},
+	}
+}
+
+// interceptMessage 消息落库前的拦截：群聊中被禁言的成员不能发言，以/开头的文本消息按命令执行
+// 返回true表示消息已被处理，不再继续发送
+func interceptMessage(req *request.ChatMessageRequest) bool {
+	if req.ReceiveId == "" || req.ReceiveId[0] != 'G' || req.Type == message_type_enum.AudioOrVideo {
+		return false
+	}
+	mutedUntil, err := GroupMutedUntil(req.ReceiveId, req.SendId)
+	if err != nil {
+		zlog.Error(err.Error())
+	} else if !mutedUntil.IsZero() {
+		zlog.Info(fmt.Sprintf("用户%s在群聊%s中被禁言至%s", req.SendId, req.ReceiveId, mutedUntil.Format("2006-01-02 15:04:05")))
+		if sendClient, ok := currentHub().GetClient(req.SendId); ok {
+			sendMessageToClient(sendClient, &model.Message{
+				SessionId: req.SessionId,
+				Type:      req.Type,
+				SendId:    req.SendId,
+				ReceiveId: req.ReceiveId,
+				CreatedAt: time.Now(),
+			}, MsgStatusMuted)
+		}
+		return true
+	}
+	if req.Type != message_type_enum.Text || !strings.HasPrefix(req.Content, commandPrefix) {
+		return false
+	}
+	return runCommand(req)
+}
+
+// GroupMutedUntil 查询成员在群聊中的禁言截止时间，未被禁言或已到期时返回零值
+func GroupMutedUntil(groupId, userId string) (time.Time, error) {
+	var mute model.GroupMute
+	res := dao.GormDB.Where("group_id = ? AND user_id = ? AND muted_until > ?", groupId, userId, time.Now()).First(&mute)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, res.Error
+	}
+	return mute.MutedUntil, nil
+}
+
+// runCommand 解析并执行命令，未注册的命令不拦截，按普通文本消息发送
+func runCommand(req *request.ChatMessageRequest) bool {
+	line := strings.TrimPrefix(req.Content, commandPrefix)
+	name, rawArgs := line, ""
+	if i := strings.IndexFunc(line, unicode.IsSpace); i >= 0 {
+		name, rawArgs = line[:i], strings.TrimSpace(line[i:])
+	}
+	name = strings.ToLower(name)
+	if !commandNamePattern.MatchString(name) {
+		return false
+	}
+	builtin, isBuiltin := builtinCommands[name]
+	var botCommand model.BotCommand
+	if !isBuiltin {
+		res := dao.GormDB.Where("group_id = ? AND name = ?", req.ReceiveId, name).First(&botCommand)
+		if res.Error != nil {
+			if !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				zlog.Error(res.Error.Error())
+			}
+			return false
+		}
+	}
+	var group model.GroupInfo
+	if res := dao.GormDB.Where("uuid = ?", req.ReceiveId).First(&group); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		replyCommandResult(req, name, commandResult{message: constants.SYSTEM_ERROR})
+		return true
+	}
+	var members []string
+	if err := json.Unmarshal(group.Members, &members); err != nil {
+		zlog.Error(err.Error())
+		replyCommandResult(req, name, commandResult{message: constants.SYSTEM_ERROR})
+		return true
+	}
+	if !containsString(members, req.SendId) {
+		replyCommandResult(req, name, commandResult{message: "你不是该群成员"})
+		return true
+	}
+	args, err := splitCommandArgs(rawArgs)
+	if err != nil {
+		replyCommandResult(req, name, commandResult{message: err.Error()})
+		return true
+	}
+	ctx := &commandContext{
+		req:     req,
+		group:   &group,
+		members: members,
+		name:    name,
+		args:    args,
+		rawArgs: rawArgs,
+		isAdmin: IsGroupManager(&group, req.SendId),
+	}
+	var result commandResult
+	switch {
+	case !isBuiltin:
+		result = runBotCommand(ctx, &botCommand)
+	case builtin.adminOnly && !ctx.isAdmin:
+		result = commandResult{message: "该命令仅群主和管理员可用"}
+	default:
+		result = builtin.run(ctx)
+	}
+	replyCommandResult(req, name, result)
+	return true
+}
+
+// splitCommandArgs 按空白切分参数，双引号括起来的部分作为一个参数，支持\"转义
+func splitCommandArgs(rawArgs string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inQuote, hasToken, escaped := false, false, false
+	for _, r := range rawArgs {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && inQuote:
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+			hasToken = true
+		case unicode.IsSpace(r) && !inQuote:
+			if hasToken {
+				args = append(args, current.String())
+				current.Reset()
+				hasToken = false
+			}
+		default:
+			current.WriteRune(r)
+			hasToken = true
+		}
+	}
+	if inQuote {
+		return nil, errors.New("命令参数中的引号未闭合")
+	}
+	if hasToken {
+		args = append(args, current.String())
+	}
+	if len(args) > commandMaxArgs {
+		return nil, fmt.Errorf("命令参数不能超过%d个", commandMaxArgs)
+	}
+	return args, nil
+}
+
+// commandTargets 命令作用的成员：优先使用消息中@的成员，其次是参数中群成员的uuid
+// 返回的rest为去掉成员后剩余的参数
+func commandTargets(ctx *commandContext) (targets []string, rest []string) {
+	for _, arg := range ctx.args {
+		if strings.HasPrefix(arg, "@") {
+			continue
+		}
+		if len(ctx.req.Mentions) == 0 && containsString(ctx.members, arg) {
+			targets = append(targets, arg)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	if len(ctx.req.Mentions) > 0 {
+		targets = ctx.req.Mentions
+	}
+	return uniqueCommandTargets(targets), rest
+}
+
+// uniqueCommandTargets 去重并保持原有顺序
+func uniqueCommandTargets(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	rsp := make([]string, 0, len(list))
+	for _, item := range list {
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		rsp = append(rsp, item)
+	}
+	return rsp
+}
+
+// parseMuteMinutes 解析禁言时长，纯数字按分钟计算，也支持m、h、d后缀
+func parseMuteMinutes(arg string) (int, error) {
+	arg = strings.ToLower(strings.TrimSpace(arg))
+	unit := 1
+	switch {
+	case strings.HasSuffix(arg, "d"):
+		unit, arg = 24*60, strings.TrimSuffix(arg, "d")
+	case strings.HasSuffix(arg, "h"):
+		unit, arg = 60, strings.TrimSuffix(arg, "h")
+	case strings.HasSuffix(arg, "m"):
+		arg = strings.TrimSuffix(arg, "m")
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil || n <= 0 || n > muteMaxMinutes/unit {
+		return 0, fmt.Errorf("禁言时长不合法，最长%d天", muteMaxMinutes/(24*60))
+	}
+	return n * unit, nil
+}
+
+// groupOperationResult 把user_service的群管理操作结果转换为命令结果
+func groupOperationResult(code int32, message string) commandResult {
+	if code == -1 {
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	return commandResult{ok: code == 0, message: message}
+}
+
+func runHelpCommand(ctx *commandContext) commandResult {
+	commandList, err := GroupCommandList(ctx.group.Uuid)
+	if err != nil {
+		zlog.Error(err.Error())
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	if len(ctx.args) > 0 {
+		name := strings.ToLower(strings.TrimPrefix(ctx.args[0], commandPrefix))
+		for _, command := range commandList {
+			if command.Name == name {
+				return commandResult{ok: true, message: command.Usage, data: []respond.CommandRespond{command}}
+			}
+		}
+		return commandResult{message: "命令不存在: " + name}
+	}
+	return commandResult{ok: true, message: "可用的命令", data: commandList}
+}
+
+func runMuteCommand(ctx *commandContext) commandResult {
+	targets, rest := commandTargets(ctx)
+	if len(targets) != 1 || len(rest) > 1 {
+		return commandResult{message: "用法: " + builtinCommands["mute"].usage}
+	}
+	minutes := muteDefaultMinutes
+	if len(rest) == 1 {
+		var err error
+		if minutes, err = parseMuteMinutes(rest[0]); err != nil {
+			return commandResult{message: err.Error()}
+		}
+	}
+	userClient, err := clients.GetGlobalUserClient()
+	if err != nil {
+		zlog.Error("获取用户客户端失败: " + err.Error())
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	resp := userClient.MuteGroupMember(ctx.req.SendId, ctx.group.Uuid, targets[0], minutes)
+	if resp == nil {
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	result := groupOperationResult(resp.Code, resp.Message)
+	if result.ok {
+		result.data = map[string]interface{}{"user_id": targets[0], "minutes": minutes}
+	}
+	return result
+}
+
+func runUnmuteCommand(ctx *commandContext) commandResult {
+	targets, rest := commandTargets(ctx)
+	if len(targets) != 1 || len(rest) > 0 {
+		return commandResult{message: "用法: " + builtinCommands["unmute"].usage}
+	}
+	userClient, err := clients.GetGlobalUserClient()
+	if err != nil {
+		zlog.Error("获取用户客户端失败: " + err.Error())
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	resp := userClient.MuteGroupMember(ctx.req.SendId, ctx.group.Uuid, targets[0], 0)
+	if resp == nil {
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	return groupOperationResult(resp.Code, resp.Message)
+}
+
+func runKickCommand(ctx *commandContext) commandResult {
+	targets, rest := commandTargets(ctx)
+	if len(targets) == 0 || len(rest) > 0 {
+		return commandResult{message: "用法: " + builtinCommands["kick"].usage}
+	}
+	userClient, err := clients.GetGlobalUserClient()
+	if err != nil {
+		zlog.Error("获取用户客户端失败: " + err.Error())
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	resp := userClient.RemoveGroupMembers(ctx.req.SendId, ctx.group.Uuid, targets)
+	if resp == nil {
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	result := groupOperationResult(resp.Code, resp.Message)
+	if result.ok {
+		result.data = map[string]interface{}{"uuid_list": targets}
+	}
+	return result
+}
+
+func runNoticeCommand(ctx *commandContext) commandResult {
+	userClient, err := clients.GetGlobalUserClient()
+	if err != nil {
+		zlog.Error("获取用户客户端失败: " + err.Error())
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	resp := userClient.UpdateGroupNotice(ctx.req.SendId, ctx.group.Uuid, ctx.rawArgs)
+	if resp == nil {
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	return groupOperationResult(resp.Code, resp.Message)
+}
+
+// runPollCommand 把命令转换为投票消息，走与客户端直接发起投票相同的校验和落库流程
+func runPollCommand(ctx *commandContext) commandResult {
+	var extra respond.PollExtraRespond
+	var rest []string
+	for _, arg := range ctx.args {
+		switch arg {
+		case "-multi":
+			extra.Multiple = true
+		case "-anonymous":
+			extra.Anonymous = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if len(rest) < 1+pollMinOptions {
+		return commandResult{message: "用法: " + builtinCommands["poll"].usage}
+	}
+	extra.Question = rest[0]
+	extra.Options = rest[1:]
+	extraByte, err := json.Marshal(extra)
+	if err != nil {
+		zlog.Error(err.Error())
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	pollReq := *ctx.req
+	pollReq.Type = message_type_enum.Poll
+	pollReq.Content = ""
+	pollReq.Mentions = nil
+	pollReq.MentionAll = false
+	pollReq.Extra = extraByte
+	// 先按投票消息校验，便于把失败原因返回给执行人
+	if err := preparePoll(&model.Message{SendId: pollReq.SendId, ReceiveId: pollReq.ReceiveId}, &pollReq); err != nil {
+		return commandResult{message: err.Error()}
+	}
+	dispatchMessage(&pollReq, preparePoll, nil)
+	return commandResult{ok: true, message: "投票已发起"}
+}
+
+// runBotCommand 把命令作为事件投递给注册命令的机器人，由机器人自行回复
+func runBotCommand(ctx *commandContext, command *model.BotCommand) commandResult {
+	if !containsString(ctx.members, command.BotId) {
+		return commandResult{message: "机器人已不在群聊中"}
+	}
+	var bot model.Bot
+	if res := dao.GormDB.Where("uuid = ?", command.BotId).First(&bot); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return commandResult{message: "机器人已被删除"}
+		}
+		zlog.Error(res.Error.Error())
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	args := ctx.args
+	if args == nil {
+		args = []string{}
+	}
+	payload, err := json.Marshal(respond.BotCommandEventRespond{
+		Event:          BotEventCommand,
+		BotId:          bot.Uuid,
+		ConversationId: ctx.group.Uuid,
+		Command:        ctx.name,
+		Args:           args,
+		RawArgs:        ctx.rawArgs,
+		SendId:         ctx.req.SendId,
+		SendName:       ctx.req.SendName,
+	})
+	if err != nil {
+		zlog.Error(err.Error())
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	now := time.Now()
+	if res := dao.GormDB.Create(&model.BotEvent{
+		BotId:         bot.Uuid,
+		DeliveryMode:  bot.DeliveryMode,
+		Event:         BotEventCommand,
+		Payload:       string(payload),
+		Status:        bot_event_status_enum.Pending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return commandResult{message: constants.SYSTEM_ERROR}
+	}
+	return commandResult{ok: true, message: "命令已发送给机器人", data: map[string]interface{}{"bot_id": bot.Uuid}}
+}
+
+// replyCommandResult 把命令执行结果推送给执行人
+func replyCommandResult(req *request.ChatMessageRequest, name string, result commandResult) {
+	sendClient, ok := currentHub().GetClient(req.SendId)
+	if !ok {
+		return
+	}
+	jsonMessage, err := json.Marshal(respond.CommandResultRespond{
+		Event:       EventCommandResult,
+		Command:     name,
+		Ok:          result.ok,
+		Message:     result.message,
+		Data:        result.data,
+		ClientMsgId: req.ClientMsgId,
+	})
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	sendEventToClient(sendClient, &MessageBack{Message: jsonMessage})
+}
+
+// IsBuiltinCommand 判断命令名是否被内置命令占用，机器人不能注册同名命令
+func IsBuiltinCommand(name string) bool {
+	_, ok := builtinCommands[name]
+	return ok
+}
+
+// IsValidCommandName 判断命令名是否合法
+func IsValidCommandName(name string) bool {
+	return commandNamePattern.MatchString(name)
+}
+
+// GroupCommandList 群聊中可用的命令，内置命令在前，机器人命令按名称排序
+func GroupCommandList(groupId string) ([]respond.CommandRespond, error) {
+	commandList := make([]respond.CommandRespond, 0, len(builtinCommands))
+	for name, command := range builtinCommands {
+		commandList = append(commandList, respond.CommandRespond{
+			Name:        name,
+			Usage:       command.usage,
+			Description: command.description,
+			AdminOnly:   command.adminOnly,
+		})
+	}
+	sort.Slice(commandList, func(i, j int) bool {
+		return commandList[i].Name < commandList[j].Name
+	})
+	var botCommandList []model.BotCommand
+	if res := dao.GormDB.Where("group_id = ?", groupId).Order("name ASC").Find(&botCommandList); res.Error != nil {
+		return nil, res.Error
+	}
+	for _, command := range botCommandList {
+		commandList = append(commandList, respond.CommandRespond{
+			Name:        command.Name,
+			Usage:       command.Usage,
+			Description: command.Description,
+			BotId:       command.BotId,
+		})
+	}
+	return commandList, nil
+}
@@ -0,0 +1,89 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	clientMsgDedupWindow = 10 * time.Minute // 同一客户端消息id的去重窗口
+	clientMsgIdMaxLen    = 64               // 客户端消息id的最大长度，与message表的字段长度一致
+)
+
+// client_msg_<send_id>_<client_msg_id>: 客户端消息id对应的服务端消息uuid
+func clientMsgKey(sendId, clientMsgId string) string {
+	return "client_msg_" + sendId + "_" + clientMsgId
+}
+
+// claimClientMsgId 消息落库前登记客户端消息id，同一发送者在去重窗口内重复提交时返回false
+// 重复提交不再落库，原消息已经保存时重新回执给发送者，带上原来的uuid和时间
+// redis共享于所有实例，channel和kafka模式都能去重
+func claimClientMsgId(message *model.Message, clientMsgId string) bool {
+	if clientMsgId == "" {
+		return true
+	}
+	if len(clientMsgId) > clientMsgIdMaxLen {
+		zlog.Warn("客户端消息id过长，不做去重: " + message.SendId)
+		return true
+	}
+	message.ClientMsgId = clientMsgId
+	ok, err := cache.GetGlobalCache().SetKeyNX(clientMsgKey(message.SendId, clientMsgId), message.Uuid, clientMsgDedupWindow)
+	if err != nil {
+		// redis不可用时不影响正常发送
+		zlog.Error(err.Error())
+		return true
+	}
+	if ok {
+		return true
+	}
+	ackDuplicateMessage(message.SendId, clientMsgId)
+	return false
+}
+
+// releaseClientMsgId 消息落库失败时释放登记，允许客户端重试
+func releaseClientMsgId(message *model.Message) {
+	if message.ClientMsgId == "" {
+		return
+	}
+	if err := cache.GetGlobalCache().DelKeyIfExists(clientMsgKey(message.SendId, message.ClientMsgId)); err != nil {
+		zlog.Error(err.Error())
+	}
+}
+
+// ackDuplicateMessage 把原消息回执给重复提交的发送者
+// 原消息还在其他实例处理中、尚未落库时不回执，等原消息正常推送
+func ackDuplicateMessage(sendId, clientMsgId string) {
+	uuid, err := cache.GetGlobalCache().GetKey(clientMsgKey(sendId, clientMsgId))
+	if err != nil || uuid == "" {
+		return
+	}
+	var original model.Message
+	if res := dao.GormDB.Where("uuid = ?", uuid).First(&original); res.Error != nil {
+		if !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			zlog.Error(res.Error.Error())
+		}
+		return
+	}
+	sendClient, ok := currentHub().GetClient(sendId)
+	if !ok {
+		return
+	}
+	if original.ReceiveId[0] != 'G' {
+		sendMessageToClient(sendClient, &original, MsgStatusSuccess)
+		return
+	}
+	jsonMessage, err := json.Marshal(respond.NewGetGroupMessageListRespond(&original))
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	sendEventToClient(sendClient, &MessageBack{Message: jsonMessage, Uuid: original.Uuid})
+}
@@ -0,0 +1,206 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/config"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+// clientHub 在线连接的查询入口，channel模式和kafka模式的server都实现了它
+type clientHub interface {
+	GetClient(id string) (*Client, bool)
+}
+
+// currentHub 按配置的消息模式返回当前使用的server
+func currentHub() clientHub {
+	if config.AppConfig.KafkaConfig.MessageMode == "channel" {
+		return ChatServer
+	}
+	return KafkaChatServer
+}
+
+// DeliverMessage 将已落库的消息推送给在线的接收方和发送方，并追加到消息列表缓存
+// 用于不经过websocket读通道产生的消息（如转发），以及语音等新增的消息类型
+func DeliverMessage(message *model.Message) {
+	notifyBots(message)
+	enqueueLastMessage(message)
+	hub := currentHub()
+	if message.ReceiveId[0] == 'U' {
+		if messageBack := newMessageBack(message); messageBack != nil {
+			deliverUserMessage(hub, message.ReceiveId, message.SendId, messageBack)
+		}
+		appendMessageListCache(message)
+		return
+	}
+	messageRsp := respond.NewGetGroupMessageListRespond(message)
+	jsonMessage, err := json.Marshal(messageRsp)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	messageBack := &MessageBack{
+		Message: jsonMessage,
+		Uuid:    message.Uuid,
+	}
+	var group model.GroupInfo
+	if res := dao.GormDB.Where("uuid = ?", message.ReceiveId).First(&group); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	var members []string
+	if err := json.Unmarshal(group.Members, &members); err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	deliverGroupMessage(hub, message.ReceiveId, message.SendId, members, messageBack, nil, nil)
+	// 话题回复不追加到群聊主时间线的缓存中
+	if message.ThreadId != "" {
+		onThreadReply(message)
+		return
+	}
+	appendGroupMessageListCache(message)
+}
+
+// newMessageBack 单聊消息序列化为下发给客户端的消息，序列化失败时返回nil
+func newMessageBack(message *model.Message) *MessageBack {
+	jsonMessage, err := json.Marshal(respond.NewGetMessageListRespond(message))
+	if err != nil {
+		zlog.Error(err.Error())
+		return nil
+	}
+	return &MessageBack{
+		Message: jsonMessage,
+		Uuid:    message.Uuid,
+	}
+}
+
+// deliverUserMessage 单聊消息推送给在线的接收者，并回显给发送者
+// 前后端的req和rsp结构不同，前端存储message的messageList只能存rsp，所以由后端进行回显
+// 定时消息、机器人消息的发送者并不在线，只推送给在线的一方；接收者免打扰时收到带silent标记的消息
+func deliverUserMessage(hub clientHub, receiveId, sendId string, messageBack *MessageBack) {
+	if receiveClient, ok := hub.GetClient(receiveId); ok {
+		deliverMessageBack(receiveClient, recipientBack(receiveId, sendId, false, messageBack))
+	}
+	if sendClient, ok := hub.GetClient(sendId); ok {
+		deliverMessageBack(sendClient, messageBack)
+	}
+}
+
+// deliverGroupMessage 群聊消息推送给在线的群成员，发送者也在成员中，一并回显
+// mentioned不为nil时被@的成员收到mentionedBack；对群聊免打扰的成员收到带silent标记的消息
+func deliverGroupMessage(hub clientHub, groupId, sendId string, members []string, messageBack, mentionedBack *MessageBack, mentioned func(member string) bool) {
+	for _, member := range members {
+		client, ok := hub.GetClient(member)
+		if !ok {
+			continue
+		}
+		if member == sendId {
+			deliverMessageBack(client, messageBack)
+			continue
+		}
+		isMentionedMember := mentioned != nil && mentioned(member)
+		back := messageBack
+		if isMentionedMember {
+			back = mentionedBack
+		}
+		deliverMessageBack(client, recipientBack(member, groupId, isMentionedMember, back))
+	}
+}
+
+// appendMessageListCache 缓存存在时把单聊消息追加到消息列表缓存中
+func appendMessageListCache(message *model.Message) {
+	key := "message_list_" + message.SendId + "_" + message.ReceiveId
+	rspString, err := cache.GetGlobalCache().GetKeyNilIsErr(key)
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			zlog.Error(err.Error())
+		}
+		return
+	}
+	var rsp []respond.GetMessageListRespond
+	if err := json.Unmarshal([]byte(rspString), &rsp); err != nil {
+		zlog.Error(err.Error())
+	}
+	rsp = append(rsp, respond.NewGetMessageListRespond(message))
+	rspByte, err := json.Marshal(rsp)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	if err := cache.GetGlobalCache().SetKeyEx(key, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
+		zlog.Error(err.Error())
+	}
+}
+
+// appendGroupMessageListCache 缓存存在时把群聊消息追加到群聊消息列表缓存中
+func appendGroupMessageListCache(message *model.Message) {
+	key := "group_messagelist_" + message.ReceiveId
+	rspString, err := cache.GetGlobalCache().GetKeyNilIsErr(key)
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			zlog.Error(err.Error())
+		}
+		return
+	}
+	var rsp []respond.GetGroupMessageListRespond
+	if err := json.Unmarshal([]byte(rspString), &rsp); err != nil {
+		zlog.Error(err.Error())
+	}
+	rsp = append(rsp, respond.NewGetGroupMessageListRespond(message))
+	rspByte, err := json.Marshal(rsp)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	if err := cache.GetGlobalCache().SetKeyEx(key, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
+		zlog.Error(err.Error())
+	}
+}
+
+// purgeMessageListCache 删除消息所在会话的消息列表缓存，下次查询时从数据库重建
+func purgeMessageListCache(messageList []model.Message) {
+	keys := make(map[string]bool)
+	for _, message := range messageList {
+		if message.ReceiveId[0] == 'G' {
+			keys["group_messagelist_"+message.ReceiveId] = true
+		} else {
+			keys["message_list_"+message.SendId+"_"+message.ReceiveId] = true
+			keys["message_list_"+message.ReceiveId+"_"+message.SendId] = true
+		}
+	}
+	for key := range keys {
+		if err := cache.GetGlobalCache().DelKeyIfExists(key); err != nil {
+			zlog.Error(err.Error())
+		}
+	}
+}
+
+// messageReceivers 消息所在会话的参与者：单聊为收发双方，群聊为全部群成员
+func messageReceivers(message *model.Message) ([]string, error) {
+	if message.ReceiveId[0] != 'G' {
+		return []string{message.SendId, message.ReceiveId}, nil
+	}
+	return groupMembers(message.ReceiveId)
+}
+
+// groupMembers 查询群聊当前的成员列表
+func groupMembers(groupId string) ([]string, error) {
+	var group model.GroupInfo
+	if res := dao.GormDB.Where("uuid = ?", groupId).First(&group); res.Error != nil {
+		return nil, res.Error
+	}
+	var members []string
+	if err := json.Unmarshal(group.Members, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,94 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/common/notify"
+)
+
+// notifyCache 只提供免打扰设置的缓存，没有设置的用户按不免打扰处理，不会回源数据库
+type notifyCache struct {
+	cache.Cache
+	settings map[string]*notify.Settings
+}
+
+func (c *notifyCache) GetKeyNilIsErr(key string) (string, error) {
+	for userId, settings := range c.settings {
+		if notify.Key(userId) == key {
+			settingsByte, err := json.Marshal(settings)
+			return string(settingsByte), err
+		}
+	}
+	return "{}", nil
+}
+
+func (c *notifyCache) SetKeyEx(key string, value string, timeout time.Duration) error {
+	return redis.Nil
+}
+
+func useNotifySettings(t *testing.T, settings map[string]*notify.Settings) {
+	t.Helper()
+	cache.Init(&notifyCache{settings: settings})
+}
+
+func receiveBack(t *testing.T, c *Client) map[string]interface{} {
+	t.Helper()
+	select {
+	case messageBack := <-c.SendBack:
+		var fields map[string]interface{}
+		if err := json.Unmarshal(messageBack.Message, &fields); err != nil {
+			t.Fatal(err)
+		}
+		return fields
+	default:
+		t.Fatalf("%s did not get the message", c.Uuid)
+		return nil
+	}
+}
+
+func TestDeliverUserMessageSilent(t *testing.T) {
+	useNotifySettings(t, map[string]*notify.Settings{
+		"U2": {Mutes: map[string]notify.Mute{"U1": {TargetId: "U1"}}},
+	})
+	k := newTestKafkaServer()
+	sender, receiver := newTestClient("U1"), newTestClient("U2")
+	k.Clients[sender.Uuid], k.Clients[receiver.Uuid] = sender, receiver
+
+	deliverUserMessage(k, receiver.Uuid, sender.Uuid, &MessageBack{Message: []byte(`{"uuid":"M1","content":"hi"}`), Uuid: "M1"})
+
+	got := receiveBack(t, receiver)
+	if got["silent"] != true || got["content"] != "hi" {
+		t.Fatalf("receiver got %v, want silent message", got)
+	}
+	if got := receiveBack(t, sender); got["silent"] != nil {
+		t.Fatalf("sender echo should not be silent: %v", got)
+	}
+}
+
+func TestDeliverGroupMessageSilent(t *testing.T) {
+	useNotifySettings(t, map[string]*notify.Settings{
+		"U2": {Mutes: map[string]notify.Mute{"G1": {TargetId: "G1"}}},
+		"U3": {Mutes: map[string]notify.Mute{"G1": {TargetId: "G1", AllowMention: true}}},
+	})
+	k := newTestKafkaServer()
+	for _, uuid := range []string{"U1", "U2", "U3", "U4"} {
+		k.Clients[uuid] = newTestClient(uuid)
+	}
+
+	messageBack := &MessageBack{Message: []byte(`{"uuid":"M1"}`), Uuid: "M1"}
+	mentionedBack := &MessageBack{Message: []byte(`{"uuid":"M1","is_mentioned":true}`), Uuid: "M1"}
+	deliverGroupMessage(k, "G1", "U1", []string{"U1", "U2", "U3", "U4"}, messageBack, mentionedBack, func(member string) bool {
+		return member == "U3"
+	})
+
+	for uuid, wantSilent := range map[string]bool{"U1": false, "U2": true, "U3": false, "U4": false} {
+		got := receiveBack(t, k.Clients[uuid])
+		if (got["silent"] == true) != wantSilent {
+			t.Errorf("%s got %v, want silent=%v", uuid, got, wantSilent)
+		}
+	}
+}
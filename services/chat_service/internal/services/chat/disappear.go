@@ -0,0 +1,219 @@
+package chat
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/conversation"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/config"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	EventMessagesExpired = "messages_expired" // 消息已过期，客户端删除本地记录
+	EventDisappearTimer  = "disappear_timer"  // 系统消息：阅后即焚设置变更
+)
+
+const (
+	disappearSweepInterval = 30 * time.Second // 清理过期消息的间隔
+	disappearSweepBatch    = 500              // 每批删除的消息数
+)
+
+// DisappearDurations 可选的阅后即焚时长，单位秒，value为系统消息中的展示文字
+var DisappearDurations = map[int]string{
+	0:       "关闭",
+	3600:    "1小时",
+	86400:   "1天",
+	604800:  "7天",
+	2592000: "30天",
+}
+
+// GetDisappearDuration 查询会话当前的阅后即焚时长，未设置时为0
+func GetDisappearDuration(sendId, receiveId string) (int, error) {
+	var setting model.DisappearSetting
+	if res := dao.GormDB.Where("conversation_id = ?", conversation.Id(sendId, receiveId)).First(&setting); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, res.Error
+	}
+	return setting.Duration, nil
+}
+
+// ApplyDisappearTimer 消息落库前按会话设置写入过期时间，系统消息不过期
+func ApplyDisappearTimer(message *model.Message) {
+	if message.Type == message_type_enum.System || message.ReceiveId == "" {
+		return
+	}
+	duration, err := GetDisappearDuration(message.SendId, message.ReceiveId)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	SetExpireAt(message, duration)
+}
+
+// SetExpireAt 按保留时长设置消息的过期时间，duration为0时不过期
+func SetExpireAt(message *model.Message, duration int) {
+	if duration <= 0 {
+		return
+	}
+	message.ExpireAt = sql.NullTime{Time: message.CreatedAt.Add(time.Duration(duration) * time.Second), Valid: true}
+}
+
+// StartDisappearSweeper 定时清理过期消息，多实例同时运行时删除是幂等的
+func StartDisappearSweeper() {
+	go func() {
+		ticker := time.NewTicker(disappearSweepInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sweepExpiredMessages()
+		}
+	}()
+}
+
+// sweepExpiredMessages 分批删除过期消息，并清理缓存、附件、置顶和@记录，通知在线的客户端
+func sweepExpiredMessages() {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("disappear sweeper panic: %v", r))
+		}
+	}()
+	for {
+		var expiredList []model.Message
+		if res := dao.GormDB.Where("expire_at IS NOT NULL AND expire_at <= ?", time.Now()).
+			Limit(disappearSweepBatch).Find(&expiredList); res.Error != nil {
+			zlog.Error(res.Error.Error())
+			return
+		}
+		if len(expiredList) == 0 {
+			return
+		}
+		messageIds := make([]string, 0, len(expiredList))
+		for _, message := range expiredList {
+			messageIds = append(messageIds, message.Uuid)
+		}
+		if res := dao.GormDB.Where("uuid IN ?", messageIds).Delete(&model.Message{}); res.Error != nil {
+			zlog.Error(res.Error.Error())
+			return
+		}
+		if res := dao.GormDB.Where("message_id IN ?", messageIds).Delete(&model.MessagePin{}); res.Error != nil {
+			zlog.Error(res.Error.Error())
+		}
+		if res := dao.GormDB.Where("message_id IN ?", messageIds).Delete(&model.MessageMention{}); res.Error != nil {
+			zlog.Error(res.Error.Error())
+		}
+		if err := removePollsOfMessages(messageIds); err != nil {
+			zlog.Error(err.Error())
+		}
+		purgeMessageListCache(expiredList)
+		removeExpiredFiles(expiredList)
+		notifyExpired(expiredList)
+		if len(expiredList) < disappearSweepBatch {
+			return
+		}
+	}
+}
+
+// removeExpiredFiles 删除过期消息上传的文件、语音和图片
+// 转发会复制url，仍被其他消息引用的文件保留；表情包等公共资源不在上传目录下，不会被删除
+func removeExpiredFiles(expiredList []model.Message) {
+	for _, message := range expiredList {
+		urls := []string{message.Url}
+		if message.Type == message_type_enum.Image && message.Extra != "" {
+			var extra respond.ImageExtraRespond
+			if err := json.Unmarshal([]byte(message.Extra), &extra); err == nil && extra.ThumbnailUrl != message.Url {
+				urls = append(urls, extra.ThumbnailUrl)
+			}
+		}
+		for _, url := range urls {
+			localPath := uploadedFilePath(url)
+			if localPath == "" {
+				continue
+			}
+			var refCnt int64
+			if res := dao.GormDB.Model(&model.Message{}).Where("url = ?", url).Count(&refCnt); res.Error != nil {
+				zlog.Error(res.Error.Error())
+				continue
+			}
+			if refCnt > 0 {
+				continue
+			}
+			if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+				zlog.Error(err.Error())
+			}
+		}
+	}
+}
+
+// uploadedFilePath 把消息中的静态资源地址映射为本地文件路径，不是用户上传的文件时返回空
+func uploadedFilePath(url string) string {
+	var dir, name string
+	switch {
+	case strings.HasPrefix(url, "/static/files/"):
+		dir, name = config.AppConfig.StaticSrcConfig.StaticFilePath, strings.TrimPrefix(url, "/static/files/")
+	case strings.HasPrefix(url, "/static/voices/"):
+		dir, name = config.AppConfig.StaticSrcConfig.StaticVoicePath, strings.TrimPrefix(url, "/static/voices/")
+	default:
+		return ""
+	}
+	// 只处理目录下的文件名，防止url中带有../
+	if name == "" || name != filepath.Base(name) {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
+
+// notifyExpired 按用户汇总过期消息，通知连在本实例上的会话参与者删除本地记录
+func notifyExpired(expiredList []model.Message) {
+	userMessages := make(map[string][]string)
+	groupMembers := make(map[string][]string)
+	for _, message := range expiredList {
+		var receivers []string
+		if message.ReceiveId[0] == 'G' {
+			members, ok := groupMembers[message.ReceiveId]
+			if !ok {
+				var group model.GroupInfo
+				if res := dao.GormDB.Where("uuid = ?", message.ReceiveId).First(&group); res.Error != nil {
+					zlog.Error(res.Error.Error())
+				} else if err := json.Unmarshal(group.Members, &members); err != nil {
+					zlog.Error(err.Error())
+				}
+				groupMembers[message.ReceiveId] = members
+			}
+			receivers = members
+		} else {
+			receivers = []string{message.SendId, message.ReceiveId}
+		}
+		for _, receiver := range receivers {
+			userMessages[receiver] = append(userMessages[receiver], message.Uuid)
+		}
+	}
+	hub := currentHub()
+	for userId, messageIds := range userMessages {
+		client, ok := hub.GetClient(userId)
+		if !ok {
+			continue
+		}
+		jsonMessage, err := json.Marshal(respond.MessagesExpiredEventRespond{
+			Event:      EventMessagesExpired,
+			MessageIds: messageIds,
+		})
+		if err != nil {
+			zlog.Error(err.Error())
+			continue
+		}
+		sendEventToClient(client, &MessageBack{Message: jsonMessage})
+	}
+}
@@ -0,0 +1,95 @@
+package chat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_status_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/random"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+// messagePreparer 校验某一类消息的内容，并写入待保存的消息
+type messagePreparer func(message *model.Message, req *request.ChatMessageRequest) error
+
+// messagePreparers 走通用分发流程的消息类型，新增消息类型只需要在这里注册校验函数
+var messagePreparers = map[int8]messagePreparer{
+	message_type_enum.Voice:       prepareVoice,
+	message_type_enum.Image:       prepareImage,
+	message_type_enum.Location:    prepareLocation,
+	message_type_enum.ContactCard: prepareContactCard,
+	message_type_enum.Sticker:     prepareSticker,
+	message_type_enum.Poll:        preparePoll,
+}
+
+// messageCreatedHooks 需要随消息一起落库的附属记录，与消息的写入在同一事务中
+var messageCreatedHooks = map[int8]func(tx *gorm.DB, message *model.Message) error{
+	message_type_enum.Poll: createPoll,
+}
+
+// dispatchMessage 通用的消息处理流程：构造消息、按类型校验、处理引用、落库、推送给在线用户
+// validate用于单聊的好友关系校验，为nil时不校验
+func dispatchMessage(req *request.ChatMessageRequest, prepare messagePreparer, validate func(*model.Message) bool) {
+	message := model.Message{
+		Uuid:       fmt.Sprintf("M%s", random.GetNowAndLenRandomString(11)),
+		SessionId:  req.SessionId,
+		Type:       req.Type,
+		SendId:     req.SendId,
+		SendName:   req.SendName,
+		SendAvatar: req.SendAvatar,
+		ReceiveId:  req.ReceiveId,
+		FileSize:   req.FileSize,
+		FileName:   req.FileName,
+		Status:     message_status_enum.Unsent,
+		CreatedAt:  time.Now(),
+	}
+	if message.ReceiveId == "" || (message.ReceiveId[0] != 'U' && message.ReceiveId[0] != 'G') {
+		zlog.Warn("消息接收者不合法: " + message.ReceiveId)
+		return
+	}
+	message.SendAvatar = normalizePath(message.SendAvatar)
+	if err := prepare(&message, req); err != nil {
+		zlog.Warn(fmt.Sprintf("消息校验失败, type=%d: %s", message.Type, err.Error()))
+		rejectMessage(&message)
+		return
+	}
+	if err := prepareReply(&message, req.ReplyTo, req.ThreadId); err != nil {
+		zlog.Warn("引用消息校验失败: " + err.Error())
+		rejectMessage(&message)
+		return
+	}
+	if !claimClientMsgId(&message, req.ClientMsgId) {
+		return
+	}
+	ApplyDisappearTimer(&message)
+	if err := dao.GormDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		if created, ok := messageCreatedHooks[message.Type]; ok {
+			return created(tx, &message)
+		}
+		return nil
+	}); err != nil {
+		zlog.Error(err.Error())
+		releaseClientMsgId(&message)
+		return
+	}
+	if message.ReceiveId[0] == 'U' && validate != nil && !validate(&message) {
+		return
+	}
+	clearDraftOnSend(&message, req)
+	DeliverMessage(&message)
+}
+
+// rejectMessage 通知发送者消息不合法
+func rejectMessage(message *model.Message) {
+	if sendClient, ok := currentHub().GetClient(message.SendId); ok {
+		sendMessageToClient(sendClient, message, MsgStatusBadRequest)
+	}
+}
@@ -0,0 +1,51 @@
+package chat
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+const EventDraftUpdated = "draft_updated" // 草稿在其他设备上被修改或清除
+
+// PushDraft 把草稿变化推送给用户在本实例上的在线连接
+func PushDraft(userId string, draft respond.DraftRespond) {
+	client, ok := currentHub().GetClient(userId)
+	if !ok {
+		return
+	}
+	jsonMessage, err := json.Marshal(respond.DraftEventRespond{
+		Event:        EventDraftUpdated,
+		DraftRespond: draft,
+	})
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	sendEventToClient(client, &MessageBack{Message: jsonMessage})
+}
+
+// clearDraftOnSend 消息落库并通过校验后清除发送者在该会话的草稿
+// 只在用户主动发送时清除，定时消息到点投递不会清掉用户正在编辑的草稿
+func clearDraftOnSend(message *model.Message, req *request.ChatMessageRequest) {
+	if req.Scheduled {
+		return
+	}
+	userId, targetId := message.SendId, message.ReceiveId
+	res := dao.GormDB.Where("user_id = ? AND target_id = ?", userId, targetId).Delete(&model.Draft{})
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	if res.RowsAffected > 0 {
+		PushDraft(userId, respond.DraftRespond{
+			TargetId:  targetId,
+			UpdatedAt: time.Now().Format("2006-01-02 15:04:05"),
+		})
+	}
+}
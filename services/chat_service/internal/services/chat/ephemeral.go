@@ -0,0 +1,186 @@
+package chat
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+// 临时事件，只转发给会话参与者，不写入message表和缓存
+const (
+	EventTypingStart    = "typing_start"    // 开始输入
+	EventTypingStop     = "typing_stop"     // 停止输入
+	EventRecordingVoice = "recording_voice" // 正在录音
+)
+
+const (
+	eventRepeatInterval = 3 * time.Second // 同一用户对同一会话的同类事件最短转发间隔
+	eventRateWindow     = time.Second     // 单个用户的事件限流窗口
+	eventRateLimit      = 5               // 单个用户每个窗口内最多转发的事件数
+	eventThrottleMaxKey = 10000           // 限流记录超过该数量时清理过期记录
+)
+
+var allowedEvents = map[string]bool{
+	EventTypingStart:    true,
+	EventTypingStop:     true,
+	EventRecordingVoice: true,
+	EventPresenceAway:   true,
+	EventPresenceActive: true,
+}
+
+type eventThrottleKey struct {
+	sendId    string
+	receiveId string
+	event     string
+}
+
+type eventRate struct {
+	windowStart time.Time
+	count       int
+}
+
+// eventThrottle 临时事件限流：同一会话的重复事件按间隔去重，单个用户按窗口限制总量
+type eventThrottle struct {
+	mutex    sync.Mutex
+	lastSent map[eventThrottleKey]time.Time
+	rates    map[string]*eventRate
+}
+
+var ephemeralThrottle = &eventThrottle{
+	lastSent: make(map[eventThrottleKey]time.Time),
+	rates:    make(map[string]*eventRate),
+}
+
+// allow 判断事件是否可以转发
+// 停止输入不做重复去重，避免接收方的输入状态一直停留，但仍计入用户的总量限制
+func (t *eventThrottle) allow(sendId, receiveId, event string, now time.Time) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if len(t.lastSent) > eventThrottleMaxKey {
+		t.cleanup(now)
+	}
+	rate, ok := t.rates[sendId]
+	if !ok || now.Sub(rate.windowStart) >= eventRateWindow {
+		rate = &eventRate{windowStart: now}
+		t.rates[sendId] = rate
+	}
+	if rate.count >= eventRateLimit {
+		return false
+	}
+	key := eventThrottleKey{sendId: sendId, receiveId: receiveId, event: event}
+	if event != EventTypingStop {
+		if last, ok := t.lastSent[key]; ok && now.Sub(last) < eventRepeatInterval {
+			return false
+		}
+		t.lastSent[key] = now
+	} else {
+		// 停止后再次开始输入需要立即通知
+		delete(t.lastSent, eventThrottleKey{sendId: sendId, receiveId: receiveId, event: EventTypingStart})
+	}
+	rate.count++
+	return true
+}
+
+// cleanup 清理过期的限流记录，调用方需持有锁
+func (t *eventThrottle) cleanup(now time.Time) {
+	for key, last := range t.lastSent {
+		if now.Sub(last) >= eventRepeatInterval {
+			delete(t.lastSent, key)
+		}
+	}
+	for sendId, rate := range t.rates {
+		if now.Sub(rate.windowStart) >= eventRateWindow {
+			delete(t.rates, sendId)
+		}
+	}
+}
+
+// handleEphemeralEvent 处理websocket上的临时事件，发送者以连接身份为准
+func handleEphemeralEvent(c *Client, req *request.WsEventRequest) {
+	if !allowedEvents[req.Event] {
+		zlog.Warn("未知的临时事件: " + req.Event)
+		return
+	}
+	now := time.Now()
+	// 前后台切换只改变自己的在线状态，不需要接收者
+	if req.Event == EventPresenceAway || req.Event == EventPresenceActive {
+		if ephemeralThrottle.allow(c.Uuid, "", req.Event, now) {
+			setPresenceAway(c.Uuid, req.Event == EventPresenceAway)
+		}
+		return
+	}
+	if req.ReceiveId == "" || (req.ReceiveId[0] != 'U' && req.ReceiveId[0] != 'G') || req.ReceiveId == c.Uuid {
+		return
+	}
+	if !ephemeralThrottle.allow(c.Uuid, req.ReceiveId, req.Event, now) {
+		return
+	}
+	eventRsp := respond.WsEventRespond{
+		Event:     req.Event,
+		SendId:    c.Uuid,
+		ReceiveId: req.ReceiveId,
+		CreatedAt: now.Format("2006-01-02 15:04:05"),
+	}
+	jsonMessage, err := json.Marshal(eventRsp)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	// Uuid为空，Write不会更新消息状态
+	eventBack := &MessageBack{Message: jsonMessage}
+	hub := currentHub()
+	if req.ReceiveId[0] == 'U' {
+		userClient, err := clients.GetGlobalUserClient()
+		if err != nil {
+			zlog.Error("获取用户客户端失败: " + err.Error())
+			return
+		}
+		if resp := userClient.GetContactStatus(c.Uuid, req.ReceiveId); resp == nil || resp.Status != 0 {
+			return
+		}
+		if receiveClient, ok := hub.GetClient(req.ReceiveId); ok {
+			sendEventToClient(receiveClient, eventBack)
+		}
+		return
+	}
+	var group model.GroupInfo
+	if res := dao.GormDB.Where("uuid = ?", req.ReceiveId).First(&group); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	var members []string
+	if err := json.Unmarshal(group.Members, &members); err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	isMember := false
+	for _, member := range members {
+		if member == c.Uuid {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		return
+	}
+	for _, member := range members {
+		if member == c.Uuid {
+			continue
+		}
+		if receiveClient, ok := hub.GetClient(member); ok {
+			sendEventToClient(receiveClient, eventBack)
+		}
+	}
+}
+
+// sendEventToClient 非阻塞地下发临时事件，通道满或连接已退出时直接丢弃
+func sendEventToClient(client *Client, eventBack *MessageBack) {
+	client.trySendBack(eventBack)
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"sync"
 	"time"
@@ -14,6 +14,7 @@ import (
 	mykafka "github.com/puoxiu/gogochat/common/kafka"
 	"github.com/puoxiu/gogochat/common/cache"
 	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/conversation"
 	"github.com/puoxiu/gogochat/pkg/enum/message/message_status_enum"
 	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
 	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/puoxiu/gogochat/pkg/random"
 	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/segmentio/kafka-go"
 )
 
 type KafkaServer struct {
@@ -59,399 +61,405 @@ func (k *KafkaServer) Start() {
 	}()
 
 	// read chat message
-	go func() {
+	// handle 处理一条聊天消息，单条消息处理panic不影响该分区后续的消息
+	handle := func(kafkaMessage kafka.Message) {
 		defer func() {
 			if r := recover(); r != nil {
 				zlog.Error(fmt.Sprintf("kafka server panic: %v", r))
 			}
 		}()
-		for {
-			kafkaMessage, err := mykafka.KafkaService.ChatReader.ReadMessage(ctx)
-			if err != nil {
-				zlog.Error(err.Error())
+		// log.Printf("topic=%s, partition=%d, offset=%d, key=%s, value=%s", kafkaMessage.Topic, kafkaMessage.Partition, kafkaMessage.Offset, kafkaMessage.Key, kafkaMessage.Value)
+		zlog.Info(fmt.Sprintf("topic=%s, partition=%d, offset=%d, key=%s, value=%s", kafkaMessage.Topic, kafkaMessage.Partition, kafkaMessage.Offset, kafkaMessage.Key, kafkaMessage.Value))
+		data := kafkaMessage.Value
+		var chatMessageReq request.ChatMessageRequest
+		if err := json.Unmarshal(data, &chatMessageReq); err != nil {
+			zlog.Error(err.Error())
+		}
+		// 禁言检查和斜杠命令在落库前执行
+		if interceptMessage(&chatMessageReq) {
+			return
+		}
+		// 语音、图片、位置、名片、表情等消息走通用分发流程
+		if prepare, ok := messagePreparers[chatMessageReq.Type]; ok {
+			// kafka模式下与文本消息一致，不校验好友关系
+			dispatchMessage(&chatMessageReq, prepare, nil)
+			return
+		}
+		if chatMessageReq.Type == message_type_enum.Text {
+			// 存message
+			message := model.Message{
+				Uuid:       fmt.Sprintf("M%s", random.GetNowAndLenRandomString(11)),
+				SessionId:  chatMessageReq.SessionId,
+				Type:       chatMessageReq.Type,
+				Content:    chatMessageReq.Content,
+				Url:        "",
+				SendId:     chatMessageReq.SendId,
+				SendName:   chatMessageReq.SendName,
+				SendAvatar: chatMessageReq.SendAvatar,
+				ReceiveId:  chatMessageReq.ReceiveId,
+				FileSize:   "0B",
+				FileType:   "",
+				FileName:   "",
+				Status:     message_status_enum.Unsent,
+				CreatedAt:  time.Now(),
+				AVdata:     "",
 			}
-			// log.Printf("topic=%s, partition=%d, offset=%d, key=%s, value=%s", kafkaMessage.Topic, kafkaMessage.Partition, kafkaMessage.Offset, kafkaMessage.Key, kafkaMessage.Value)
-			zlog.Info(fmt.Sprintf("topic=%s, partition=%d, offset=%d, key=%s, value=%s", kafkaMessage.Topic, kafkaMessage.Partition, kafkaMessage.Offset, kafkaMessage.Key, kafkaMessage.Value))
-			data := kafkaMessage.Value
-			var chatMessageReq request.ChatMessageRequest
-			if err := json.Unmarshal(data, &chatMessageReq); err != nil {
-				zlog.Error(err.Error())
+			// 对SendAvatar去除前面/static之前的所有内容，防止ip前缀引入
+			message.SendAvatar = normalizePath(message.SendAvatar)
+			if err := prepareReply(&message, chatMessageReq.ReplyTo, chatMessageReq.ThreadId); err != nil {
+				zlog.Warn("引用消息校验失败: " + err.Error())
+				if sendClient, ok := k.GetClient(message.SendId); ok {
+					sendMessageToClient(sendClient, &message, MsgStatusBadRequest)
+				}
+				return
 			}
-			log.Println("原消息为：", data, "反序列化后为：", chatMessageReq)
-			if chatMessageReq.Type == message_type_enum.Text {
-				// 存message
-				message := model.Message{
-					Uuid:       fmt.Sprintf("M%s", random.GetNowAndLenRandomString(11)),
-					SessionId:  chatMessageReq.SessionId,
-					Type:       chatMessageReq.Type,
-					Content:    chatMessageReq.Content,
-					Url:        "",
-					SendId:     chatMessageReq.SendId,
-					SendName:   chatMessageReq.SendName,
-					SendAvatar: chatMessageReq.SendAvatar,
-					ReceiveId:  chatMessageReq.ReceiveId,
-					FileSize:   "0B",
-					FileType:   "",
-					FileName:   "",
-					Status:     message_status_enum.Unsent,
-					CreatedAt:  time.Now(),
-					AVdata:     "",
+			if err := prepareMentions(&message, chatMessageReq.Mentions, chatMessageReq.MentionAll); err != nil {
+				zlog.Warn("@成员校验失败: " + err.Error())
+				if sendClient, ok := k.GetClient(message.SendId); ok {
+					sendMessageToClient(sendClient, &message, MsgStatusBadRequest)
 				}
-				// 对SendAvatar去除前面/static之前的所有内容，防止ip前缀引入
-				message.SendAvatar = normalizePath(message.SendAvatar)
-				if res := dao.GormDB.Create(&message); res.Error != nil {
-					zlog.Error(res.Error.Error())
+				return
+			}
+			if !claimClientMsgId(&message, chatMessageReq.ClientMsgId) {
+				return
+			}
+			ApplyDisappearTimer(&message)
+			saved := true
+			if res := dao.GormDB.Create(&message); res.Error != nil {
+				zlog.Error(res.Error.Error())
+				releaseClientMsgId(&message)
+				saved = false
+			}
+			// kafka模式下不校验好友关系，落库成功即视为通过校验
+			if saved {
+				onTextMessageAccepted(&message)
+				enqueueLastMessage(&message)
+				clearDraftOnSend(&message, &chatMessageReq)
+			}
+			if message.ReceiveId[0] == 'U' { 
+				// 发送给User
+				// 如果能找到ReceiveId，说明在线，可以发送，否则存表后跳过
+				// 因为在线的时候是通过websocket更新消息记录的，离线后通过存表，登录时只调用一次数据库操作
+				// 切换chat对象后，前端的messageList也会改变，获取messageList从第二次就是从redis中获取
+				messageRsp := respond.NewGetMessageListRespond(&message)
+				jsonMessage, err := json.Marshal(messageRsp)
+				if err != nil {
+					zlog.Error(err.Error())
 				}
-				if message.ReceiveId[0] == 'U' { 
-					// 发送给User
-					// 如果能找到ReceiveId，说明在线，可以发送，否则存表后跳过
-					// 因为在线的时候是通过websocket更新消息记录的，离线后通过存表，登录时只调用一次数据库操作
-					// 切换chat对象后，前端的messageList也会改变，获取messageList从第二次就是从redis中获取
-					messageRsp := respond.GetMessageListRespond{
-						SendId:     message.SendId,
-						SendName:   message.SendName,
-						SendAvatar: chatMessageReq.SendAvatar,
-						ReceiveId:  message.ReceiveId,
-						Type:       message.Type,
-						Content:    message.Content,
-						Url:        message.Url,
-						FileSize:   message.FileSize,
-						FileName:   message.FileName,
-						FileType:   message.FileType,
-						CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
-					}
-					jsonMessage, err := json.Marshal(messageRsp)
-					if err != nil {
-						zlog.Error(err.Error())
-					}
-					log.Println("返回的消息为：", messageRsp, "序列化后为：", jsonMessage)
-					var messageBack = &MessageBack{
-						Message: jsonMessage,
-						Uuid:    message.Uuid,
-					}
-					k.mutex.Lock()
-					if receiveClient, ok := k.Clients[message.ReceiveId]; ok {
-						//messageBack.Message = jsonMessage
-						//messageBack.Uuid = message.Uuid
-						receiveClient.SendBack <- messageBack // 向client.Send发送
-					}
-					// 因为send_id肯定在线，所以这里在后端进行在线回显message，其实优化的话前端可以直接回显
-					// 问题在于前后端的req和rsp结构不同，前端存储message的messageList不能存req，只能存rsp
-					// 所以这里后端进行回显，前端不回显
-					sendClient := k.Clients[message.SendId]
-					sendClient.SendBack <- messageBack
-					k.mutex.Unlock()
-
-					// redis
-					var rspString string
-					rspString, err = cache.GetGlobalCache().GetKeyNilIsErr("message_list_" + message.SendId + "_" + message.ReceiveId)
-					if err == nil {
-						var rsp []respond.GetMessageListRespond
-						if err = json.Unmarshal([]byte(rspString), &rsp); err != nil {
-							zlog.Error(err.Error())
-						}
-						rsp = append(rsp, messageRsp)
-						rspByte, err := json.Marshal(rsp)
-						if err != nil {
-							zlog.Error(err.Error())
-						}
-						if err := cache.GetGlobalCache().SetKeyEx("message_list_"+message.SendId+"_"+message.ReceiveId, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
-							zlog.Error(err.Error())
-						}
-					} else {
-						if !errors.Is(err, redis.Nil) {
-							zlog.Error(err.Error())
-						}
-					}
+				var messageBack = &MessageBack{
+					Message: jsonMessage,
+					Uuid:    message.Uuid,
+				}
+				deliverUserMessage(k, message.ReceiveId, message.SendId, messageBack)
 
-				} else if message.ReceiveId[0] == 'G' { // 发送给Group
-					messageRsp := respond.GetGroupMessageListRespond{
-						SendId:     message.SendId,
-						SendName:   message.SendName,
-						SendAvatar: chatMessageReq.SendAvatar,
-						ReceiveId:  message.ReceiveId,
-						Type:       message.Type,
-						Content:    message.Content,
-						Url:        message.Url,
-						FileSize:   message.FileSize,
-						FileName:   message.FileName,
-						FileType:   message.FileType,
-						CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
+				// redis
+				var rspString string
+				rspString, err = cache.GetGlobalCache().GetKeyNilIsErr("message_list_" + message.SendId + "_" + message.ReceiveId)
+				if err == nil {
+					var rsp []respond.GetMessageListRespond
+					if err = json.Unmarshal([]byte(rspString), &rsp); err != nil {
+						zlog.Error(err.Error())
 					}
-					jsonMessage, err := json.Marshal(messageRsp)
+					rsp = append(rsp, messageRsp)
+					rspByte, err := json.Marshal(rsp)
 					if err != nil {
 						zlog.Error(err.Error())
 					}
-					log.Println("返回的消息为：", messageRsp, "序列化后为：", jsonMessage)
-					var messageBack = &MessageBack{
-						Message: jsonMessage,
-						Uuid:    message.Uuid,
-					}
-					var group model.GroupInfo
-					if res := dao.GormDB.Where("uuid = ?", message.ReceiveId).First(&group); res.Error != nil {
-						zlog.Error(res.Error.Error())
-					}
-					var members []string
-					if err := json.Unmarshal(group.Members, &members); err != nil {
+					if err := cache.GetGlobalCache().SetKeyEx("message_list_"+message.SendId+"_"+message.ReceiveId, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
 						zlog.Error(err.Error())
 					}
-					k.mutex.Lock()
-					for _, member := range members {
-						if member != message.SendId {
-							if receiveClient, ok := k.Clients[member]; ok {
-								receiveClient.SendBack <- messageBack
-							}
-						} else {
-							sendClient := k.Clients[message.SendId]
-							sendClient.SendBack <- messageBack
-						}
+				} else {
+					if !errors.Is(err, redis.Nil) {
+						zlog.Error(err.Error())
 					}
-					k.mutex.Unlock()
+				}
 
-					// redis
-					var rspString string
-					rspString, err = cache.GetGlobalCache().GetKeyNilIsErr("group_messagelist_" + message.ReceiveId)
-					if err == nil {
-						var rsp []respond.GetGroupMessageListRespond
-						if err = json.Unmarshal([]byte(rspString), &rsp); err != nil {
-							zlog.Error(err.Error())
-						}
-						rsp = append(rsp, messageRsp)
-						rspByte, err := json.Marshal(rsp)
-						if err != nil {
-							zlog.Error(err.Error())
-						}
-						if err := cache.GetGlobalCache().SetKeyEx("group_messagelist_"+message.ReceiveId, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
-							zlog.Error(err.Error())
-						}
-					} else {
-						if !errors.Is(err, redis.Nil) {
-							zlog.Error(err.Error())
-						}
-					}
+			} else if message.ReceiveId[0] == 'G' { // 发送给Group
+				messageRsp := respond.NewGetGroupMessageListRespond(&message)
+				jsonMessage, err := json.Marshal(messageRsp)
+				if err != nil {
+					zlog.Error(err.Error())
 				}
-			} else if chatMessageReq.Type == message_type_enum.File {
-				// 存message
-				message := model.Message{
-					Uuid:       fmt.Sprintf("M%s", random.GetNowAndLenRandomString(11)),
-					SessionId:  chatMessageReq.SessionId,
-					Type:       chatMessageReq.Type,
-					Content:    "",
-					Url:        chatMessageReq.Url,
-					SendId:     chatMessageReq.SendId,
-					SendName:   chatMessageReq.SendName,
-					SendAvatar: chatMessageReq.SendAvatar,
-					ReceiveId:  chatMessageReq.ReceiveId,
-					FileSize:   chatMessageReq.FileSize,
-					FileType:   chatMessageReq.FileType,
-					FileName:   chatMessageReq.FileName,
-					Status:     message_status_enum.Unsent,
-					CreatedAt:  time.Now(),
-					AVdata:     "",
+				var messageBack = &MessageBack{
+					Message: jsonMessage,
+					Uuid:    message.Uuid,
 				}
-				// 对SendAvatar去除前面/static之前的所有内容，防止ip前缀引入
-				message.SendAvatar = normalizePath(message.SendAvatar)
-				if res := dao.GormDB.Create(&message); res.Error != nil {
+				var group model.GroupInfo
+				if res := dao.GormDB.Where("uuid = ?", message.ReceiveId).First(&group); res.Error != nil {
 					zlog.Error(res.Error.Error())
 				}
-				if message.ReceiveId[0] == 'U' { // 发送给User
-					// 如果能找到ReceiveId，说明在线，可以发送，否则存表后跳过
-					// 因为在线的时候是通过websocket更新消息记录的，离线后通过存表，登录时只调用一次数据库操作
-					// 切换chat对象后，前端的messageList也会改变，获取messageList从第二次就是从redis中获取
-					messageRsp := respond.GetMessageListRespond{
-						SendId:     message.SendId,
-						SendName:   message.SendName,
-						SendAvatar: chatMessageReq.SendAvatar,
-						ReceiveId:  message.ReceiveId,
-						Type:       message.Type,
-						Content:    message.Content,
-						Url:        message.Url,
-						FileSize:   message.FileSize,
-						FileName:   message.FileName,
-						FileType:   message.FileType,
-						CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
+				var members []string
+				if err := json.Unmarshal(group.Members, &members); err != nil {
+					zlog.Error(err.Error())
+				}
+				if saved {
+					recordMentions(&message, members)
+				}
+				mentionedBack := newMentionedBack(messageRsp)
+				deliverGroupMessage(k, message.ReceiveId, message.SendId, members, messageBack, mentionedBack, func(member string) bool {
+					return isMentioned(&messageRsp, member)
+				})
+
+				// 话题回复不追加到群聊主时间线的缓存中
+				if message.ThreadId != "" {
+					if saved {
+						onThreadReply(&message)
+					}
+					return
+				}
+
+				// redis
+				var rspString string
+				rspString, err = cache.GetGlobalCache().GetKeyNilIsErr("group_messagelist_" + message.ReceiveId)
+				if err == nil {
+					var rsp []respond.GetGroupMessageListRespond
+					if err = json.Unmarshal([]byte(rspString), &rsp); err != nil {
+						zlog.Error(err.Error())
 					}
-					jsonMessage, err := json.Marshal(messageRsp)
+					rsp = append(rsp, messageRsp)
+					rspByte, err := json.Marshal(rsp)
 					if err != nil {
 						zlog.Error(err.Error())
 					}
-					log.Println("返回的消息为：", messageRsp, "序列化后为：", jsonMessage)
-					var messageBack = &MessageBack{
-						Message: jsonMessage,
-						Uuid:    message.Uuid,
+					if err := cache.GetGlobalCache().SetKeyEx("group_messagelist_"+message.ReceiveId, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
+						zlog.Error(err.Error())
 					}
-					k.mutex.Lock()
-					if receiveClient, ok := k.Clients[message.ReceiveId]; ok {
-						//messageBack.Message = jsonMessage
-						//messageBack.Uuid = message.Uuid
-						receiveClient.SendBack <- messageBack // 向client.Send发送
+				} else {
+					if !errors.Is(err, redis.Nil) {
+						zlog.Error(err.Error())
 					}
-					// 因为send_id肯定在线，所以这里在后端进行在线回显message，其实优化的话前端可以直接回显
-					// 问题在于前后端的req和rsp结构不同，前端存储message的messageList不能存req，只能存rsp
-					// 所以这里后端进行回显，前端不回显
-					sendClient := k.Clients[message.SendId]
-					sendClient.SendBack <- messageBack
-					k.mutex.Unlock()
+				}
+			}
+		} else if chatMessageReq.Type == message_type_enum.File {
+			// 存message
+			message := model.Message{
+				Uuid:       fmt.Sprintf("M%s", random.GetNowAndLenRandomString(11)),
+				SessionId:  chatMessageReq.SessionId,
+				Type:       chatMessageReq.Type,
+				Content:    "",
+				Url:        chatMessageReq.Url,
+				SendId:     chatMessageReq.SendId,
+				SendName:   chatMessageReq.SendName,
+				SendAvatar: chatMessageReq.SendAvatar,
+				ReceiveId:  chatMessageReq.ReceiveId,
+				FileSize:   chatMessageReq.FileSize,
+				FileType:   chatMessageReq.FileType,
+				FileName:   chatMessageReq.FileName,
+				Status:     message_status_enum.Unsent,
+				CreatedAt:  time.Now(),
+				AVdata:     "",
+			}
+			// 对SendAvatar去除前面/static之前的所有内容，防止ip前缀引入
+			message.SendAvatar = normalizePath(message.SendAvatar)
+			if err := prepareReply(&message, chatMessageReq.ReplyTo, chatMessageReq.ThreadId); err != nil {
+				zlog.Warn("引用消息校验失败: " + err.Error())
+				if sendClient, ok := k.GetClient(message.SendId); ok {
+					sendMessageToClient(sendClient, &message, MsgStatusBadRequest)
+				}
+				return
+			}
+			if !claimClientMsgId(&message, chatMessageReq.ClientMsgId) {
+				return
+			}
+			ApplyDisappearTimer(&message)
+			saved := true
+			if res := dao.GormDB.Create(&message); res.Error != nil {
+				zlog.Error(res.Error.Error())
+				releaseClientMsgId(&message)
+				saved = false
+			}
+			if saved {
+				notifyBots(&message)
+				enqueueLastMessage(&message)
+				clearDraftOnSend(&message, &chatMessageReq)
+			}
+			if message.ReceiveId[0] == 'U' { // 发送给User
+				// 如果能找到ReceiveId，说明在线，可以发送，否则存表后跳过
+				// 因为在线的时候是通过websocket更新消息记录的，离线后通过存表，登录时只调用一次数据库操作
+				// 切换chat对象后，前端的messageList也会改变，获取messageList从第二次就是从redis中获取
+				messageRsp := respond.NewGetMessageListRespond(&message)
+				jsonMessage, err := json.Marshal(messageRsp)
+				if err != nil {
+					zlog.Error(err.Error())
+				}
+				var messageBack = &MessageBack{
+					Message: jsonMessage,
+					Uuid:    message.Uuid,
+				}
+				deliverUserMessage(k, message.ReceiveId, message.SendId, messageBack)
 
-					// redis
-					var rspString string
-					rspString, err = cache.GetGlobalCache().GetKeyNilIsErr("message_list_" + message.SendId + "_" + message.ReceiveId)
-					if err == nil {
-						var rsp []respond.GetMessageListRespond
-						if err = json.Unmarshal([]byte(rspString), &rsp); err != nil {
-							zlog.Error(err.Error())
-						}
-						rsp = append(rsp, messageRsp)
-						rspByte, err := json.Marshal(rsp)
-						if err != nil {
-							zlog.Error(err.Error())
-						}
-						if err := cache.GetGlobalCache().SetKeyEx("message_list_"+message.SendId+"_"+message.ReceiveId, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
-							zlog.Error(err.Error())
-						}
-					} else {
-						if !errors.Is(err, redis.Nil) {
-							zlog.Error(err.Error())
-						}
-					}
-				} else {
-					messageRsp := respond.GetGroupMessageListRespond{
-						SendId:     message.SendId,
-						SendName:   message.SendName,
-						SendAvatar: chatMessageReq.SendAvatar,
-						ReceiveId:  message.ReceiveId,
-						Type:       message.Type,
-						Content:    message.Content,
-						Url:        message.Url,
-						FileSize:   message.FileSize,
-						FileName:   message.FileName,
-						FileType:   message.FileType,
-						CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
+				// redis
+				var rspString string
+				rspString, err = cache.GetGlobalCache().GetKeyNilIsErr("message_list_" + message.SendId + "_" + message.ReceiveId)
+				if err == nil {
+					var rsp []respond.GetMessageListRespond
+					if err = json.Unmarshal([]byte(rspString), &rsp); err != nil {
+						zlog.Error(err.Error())
 					}
-					jsonMessage, err := json.Marshal(messageRsp)
+					rsp = append(rsp, messageRsp)
+					rspByte, err := json.Marshal(rsp)
 					if err != nil {
 						zlog.Error(err.Error())
 					}
-					log.Println("返回的消息为：", messageRsp, "序列化后为：", jsonMessage)
-					var messageBack = &MessageBack{
-						Message: jsonMessage,
-						Uuid:    message.Uuid,
-					}
-					var group model.GroupInfo
-					if res := dao.GormDB.Where("uuid = ?", message.ReceiveId).First(&group); res.Error != nil {
-						zlog.Error(res.Error.Error())
-					}
-					var members []string
-					if err = json.Unmarshal(group.Members, &members); err != nil {
+					if err := cache.GetGlobalCache().SetKeyEx("message_list_"+message.SendId+"_"+message.ReceiveId, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
 						zlog.Error(err.Error())
 					}
-					k.mutex.Lock()
-					for _, member := range members {
-						if member != message.SendId {
-							if receiveClient, ok := k.Clients[member]; ok {
-								receiveClient.SendBack <- messageBack
-							}
-						} else {
-							sendClient := k.Clients[message.SendId]
-							sendClient.SendBack <- messageBack
-						}
-					}
-					k.mutex.Unlock()
-
-					// redis
-					var rspString string
-					rspString, err = cache.GetGlobalCache().GetKeyNilIsErr("group_messagelist_" + message.ReceiveId)
-					if err == nil {
-						var rsp []respond.GetGroupMessageListRespond
-						if err = json.Unmarshal([]byte(rspString), &rsp); err != nil {
-							zlog.Error(err.Error())
-						}
-						rsp = append(rsp, messageRsp)
-						rspByte, err := json.Marshal(rsp)
-						if err != nil {
-							zlog.Error(err.Error())
-						}
-						if err := cache.GetGlobalCache().SetKeyEx("group_messagelist_"+message.ReceiveId, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
-							zlog.Error(err.Error())
-						}
-					} else {
-						if !errors.Is(err, redis.Nil) {
-							zlog.Error(err.Error())
-						}
+				} else {
+					if !errors.Is(err, redis.Nil) {
+						zlog.Error(err.Error())
 					}
 				}
-			} else if chatMessageReq.Type == message_type_enum.AudioOrVideo {
-				var avData request.AVData
-				if err := json.Unmarshal([]byte(chatMessageReq.AVdata), &avData); err != nil {
+			} else {
+				messageRsp := respond.NewGetGroupMessageListRespond(&message)
+				jsonMessage, err := json.Marshal(messageRsp)
+				if err != nil {
 					zlog.Error(err.Error())
 				}
-				//log.Println(avData)
-				message := model.Message{
-					Uuid:       fmt.Sprintf("M%s", random.GetNowAndLenRandomString(11)),
-					SessionId:  chatMessageReq.SessionId,
-					Type:       chatMessageReq.Type,
-					Content:    "",
-					Url:        "",
-					SendId:     chatMessageReq.SendId,
-					SendName:   chatMessageReq.SendName,
-					SendAvatar: chatMessageReq.SendAvatar,
-					ReceiveId:  chatMessageReq.ReceiveId,
-					FileSize:   "",
-					FileType:   "",
-					FileName:   "",
-					Status:     message_status_enum.Unsent,
-					CreatedAt:  time.Now(),
-					AVdata:     chatMessageReq.AVdata,
+				var messageBack = &MessageBack{
+					Message: jsonMessage,
+					Uuid:    message.Uuid,
+				}
+				var group model.GroupInfo
+				if res := dao.GormDB.Where("uuid = ?", message.ReceiveId).First(&group); res.Error != nil {
+					zlog.Error(res.Error.Error())
+				}
+				var members []string
+				if err = json.Unmarshal(group.Members, &members); err != nil {
+					zlog.Error(err.Error())
 				}
-				if avData.MessageId == "PROXY" && (avData.Type == "start_call" || avData.Type == "receive_call" || avData.Type == "reject_call") {
-					// 存message
-					// 对SendAvatar去除前面/static之前的所有内容，防止ip前缀引入
-					message.SendAvatar = normalizePath(message.SendAvatar)
-					if res := dao.GormDB.Create(&message); res.Error != nil {
-						zlog.Error(res.Error.Error())
+				deliverGroupMessage(k, message.ReceiveId, message.SendId, members, messageBack, nil, nil)
+
+				// 话题回复不追加到群聊主时间线的缓存中
+				if message.ThreadId != "" {
+					if saved {
+						onThreadReply(&message)
 					}
+					return
 				}
 
-				if chatMessageReq.ReceiveId[0] == 'U' { // 发送给User
-					// 如果能找到ReceiveId，说明在线，可以发送，否则存表后跳过
-					// 因为在线的时候是通过websocket更新消息记录的，离线后通过存表，登录时只调用一次数据库操作
-					// 切换chat对象后，前端的messageList也会改变，获取messageList从第二次就是从redis中获取
-					messageRsp := respond.AVMessageRespond{
-						SendId:     message.SendId,
-						SendName:   message.SendName,
-						SendAvatar: message.SendAvatar,
-						ReceiveId:  message.ReceiveId,
-						Type:       message.Type,
-						Content:    message.Content,
-						Url:        message.Url,
-						FileSize:   message.FileSize,
-						FileName:   message.FileName,
-						FileType:   message.FileType,
-						CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
-						AVdata:     message.AVdata,
+				// redis
+				var rspString string
+				rspString, err = cache.GetGlobalCache().GetKeyNilIsErr("group_messagelist_" + message.ReceiveId)
+				if err == nil {
+					var rsp []respond.GetGroupMessageListRespond
+					if err = json.Unmarshal([]byte(rspString), &rsp); err != nil {
+						zlog.Error(err.Error())
 					}
-					jsonMessage, err := json.Marshal(messageRsp)
+					rsp = append(rsp, messageRsp)
+					rspByte, err := json.Marshal(rsp)
 					if err != nil {
 						zlog.Error(err.Error())
 					}
-					// log.Println("返回的消息为：", messageRsp, "序列化后为：", jsonMessage)
-					log.Println("返回的消息为：", messageRsp)
-					var messageBack = &MessageBack{
-						Message: jsonMessage,
-						Uuid:    message.Uuid,
+					if err := cache.GetGlobalCache().SetKeyEx("group_messagelist_"+message.ReceiveId, string(rspByte), time.Minute*constants.REDIS_TIMEOUT); err != nil {
+						zlog.Error(err.Error())
 					}
-					k.mutex.Lock()
-					if receiveClient, ok := k.Clients[message.ReceiveId]; ok {
-						//messageBack.Message = jsonMessage
-						//messageBack.Uuid = message.Uuid
-						receiveClient.SendBack <- messageBack // 向client.Send发送
+				} else {
+					if !errors.Is(err, redis.Nil) {
+						zlog.Error(err.Error())
 					}
-					// 通话这不能回显，发回去的话就会出现两个start_call。
-					//sendClient := s.Clients[message.SendId]
-					//sendClient.SendBack <- messageBack
-					k.mutex.Unlock()
 				}
 			}
+		} else if chatMessageReq.Type == message_type_enum.AudioOrVideo {
+			var avData request.AVData
+			if err := json.Unmarshal([]byte(chatMessageReq.AVdata), &avData); err != nil {
+				zlog.Error(err.Error())
+			}
+			//log.Println(avData)
+			message := model.Message{
+				Uuid:       fmt.Sprintf("M%s", random.GetNowAndLenRandomString(11)),
+				SessionId:  chatMessageReq.SessionId,
+				Type:       chatMessageReq.Type,
+				Content:    "",
+				Url:        "",
+				SendId:     chatMessageReq.SendId,
+				SendName:   chatMessageReq.SendName,
+				SendAvatar: chatMessageReq.SendAvatar,
+				ReceiveId:  chatMessageReq.ReceiveId,
+				FileSize:   "",
+				FileType:   "",
+				FileName:   "",
+				Status:     message_status_enum.Unsent,
+				CreatedAt:  time.Now(),
+				AVdata:     chatMessageReq.AVdata,
+			}
+			if avData.MessageId == "PROXY" && (avData.Type == "start_call" || avData.Type == "receive_call" || avData.Type == "reject_call") {
+				// 存message
+				// 对SendAvatar去除前面/static之前的所有内容，防止ip前缀引入
+				message.SendAvatar = normalizePath(message.SendAvatar)
+				ApplyDisappearTimer(&message)
+				if res := dao.GormDB.Create(&message); res.Error != nil {
+					zlog.Error(res.Error.Error())
+				}
+			}
+
+			if chatMessageReq.ReceiveId[0] == 'U' { // 发送给User
+				// 如果能找到ReceiveId，说明在线，可以发送，否则存表后跳过
+				// 因为在线的时候是通过websocket更新消息记录的，离线后通过存表，登录时只调用一次数据库操作
+				// 切换chat对象后，前端的messageList也会改变，获取messageList从第二次就是从redis中获取
+				messageRsp := respond.AVMessageRespond{
+					SendId:     message.SendId,
+					SendName:   message.SendName,
+					SendAvatar: message.SendAvatar,
+					ReceiveId:  message.ReceiveId,
+					Type:       message.Type,
+					Content:    message.Content,
+					Url:        message.Url,
+					FileSize:   message.FileSize,
+					FileName:   message.FileName,
+					FileType:   message.FileType,
+					CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
+					AVdata:     message.AVdata,
+				}
+				jsonMessage, err := json.Marshal(messageRsp)
+				if err != nil {
+					zlog.Error(err.Error())
+				}
+				// log.Println("返回的消息为：", messageRsp, "序列化后为：", jsonMessage)
+				var messageBack = &MessageBack{
+					Message: jsonMessage,
+					Uuid:    message.Uuid,
+				}
+				if receiveClient, ok := k.GetClient(message.ReceiveId); ok {
+					deliverMessageBack(receiveClient, messageBack) // 向client.Send发送
+				}
+				// 通话这不能回显，发回去的话就会出现两个start_call。
+			}
+		}
+	}
+	// 同一会话的消息key相同，落在同一分区；每个分区一个处理协程，分区内按顺序处理，分区之间并发
+	consume := func(partitionMessages <-chan kafka.Message) {
+		for kafkaMessage := range partitionMessages {
+			handle(kafkaMessage)
+			// 处理完成后再提交位移，实例崩溃时未处理完的消息会被重新消费
+			if err := mykafka.KafkaService.ChatReader.CommitMessages(ctx, kafkaMessage); err != nil {
+				zlog.Error(err.Error())
+			}
+		}
+	}
+	go func() {
+		partitionWorkers := make(map[int]chan kafka.Message)
+		defer func() {
+			for _, worker := range partitionWorkers {
+				close(worker)
+			}
+		}()
+		for {
+			kafkaMessage, err := mykafka.KafkaService.ChatReader.FetchMessage(ctx)
+			if err != nil {
+				zlog.Error(err.Error())
+				if errors.Is(err, io.EOF) {
+					// reader已关闭
+					return
+				}
+				continue
+			}
+			worker, ok := partitionWorkers[kafkaMessage.Partition]
+			if !ok {
+				worker = make(chan kafka.Message, constants.CHANNEL_SIZE)
+				partitionWorkers[kafkaMessage.Partition] = worker
+				go consume(worker)
+			}
+			worker <- kafkaMessage
 		}
 	}()
 
@@ -463,6 +471,7 @@ func (k *KafkaServer) Start() {
 				k.mutex.Lock()
 				k.Clients[client.Uuid] = client
 				k.mutex.Unlock()
+				onClientLogin(client.Uuid)
 				zlog.Debug(fmt.Sprintf("欢迎来到kama聊天服务器，亲爱的用户%s\n", client.Uuid))
 				err := client.Conn.WriteMessage(websocket.TextMessage, []byte("欢迎来到kama聊天服务器"))
 				if err != nil {
@@ -473,8 +482,14 @@ func (k *KafkaServer) Start() {
 		case client := <-k.Logout:
 			{
 				k.mutex.Lock()
+				// 同一用户重新登录后，旧连接的退出不影响在线状态
+				current, ok := k.Clients[client.Uuid]
+				isCurrent := ok && current == client
 				delete(k.Clients, client.Uuid)
 				k.mutex.Unlock()
+				if isCurrent {
+					onClientLogout(client.Uuid)
+				}
 				zlog.Info(fmt.Sprintf("用户%s退出登录\n", client.Uuid))
 				if err := client.Conn.WriteMessage(websocket.TextMessage, []byte("已退出登录")); err != nil {
 					zlog.Error(err.Error())
@@ -513,3 +528,9 @@ func (k *KafkaServer) RemoveClient(uuid string) {
 	delete(k.Clients, uuid)
 	k.mutex.Unlock()
 }
+
+// chatMessageKey 聊天消息的kafka key，单聊双方和同一群聊的消息使用相同的key
+// 同一会话的消息落在同一分区，保证会话内有序，不同会话分散到各个分区
+func chatMessageKey(sendId, receiveId string) []byte {
+	return []byte(conversation.Id(sendId, receiveId))
+}
@@ -0,0 +1,136 @@
+package chat
+
+import (
+	"sync"
+	"testing"
+)
+
+func newTestKafkaServer() *KafkaServer {
+	return &KafkaServer{
+		Clients: make(map[string]*Client),
+		mutex:   &sync.Mutex{},
+		Login:   make(chan *Client),
+		Logout:  make(chan *Client),
+	}
+}
+
+func newTestClient(uuid string) *Client {
+	return &Client{
+		Uuid:          uuid,
+		SendTo:        make(chan []byte, 1),
+		SendBack:      make(chan *MessageBack, 4),
+		HeartBeatDone: make(chan struct{}),
+	}
+}
+
+func assertUnlocked(t *testing.T, k *KafkaServer) {
+	t.Helper()
+	if !k.mutex.TryLock() {
+		t.Fatal("mutex still held after delivery")
+	}
+	k.mutex.Unlock()
+}
+
+// 定时消息由调度器经injectMessage写入kafka，消费时发送者通常不在线
+func TestKafkaDeliverScheduledMessageSenderOffline(t *testing.T) {
+	useNotifySettings(t, nil)
+	k := newTestKafkaServer()
+	receiver := newTestClient("U2")
+	k.Clients[receiver.Uuid] = receiver
+
+	messageBack := &MessageBack{Message: []byte(`{"content":"scheduled"}`), Uuid: "M1"}
+	deliverUserMessage(k, receiver.Uuid, "U1", messageBack)
+
+	select {
+	case got := <-receiver.SendBack:
+		if got != messageBack {
+			t.Fatalf("receiver got %v, want %v", got, messageBack)
+		}
+	default:
+		t.Fatal("receiver did not get the scheduled message")
+	}
+	assertUnlocked(t, k)
+}
+
+func TestKafkaDeliverEchoesToOnlineSender(t *testing.T) {
+	useNotifySettings(t, nil)
+	k := newTestKafkaServer()
+	sender := newTestClient("U1")
+	k.Clients[sender.Uuid] = sender
+
+	messageBack := &MessageBack{Message: []byte(`{}`), Uuid: "M2"}
+	deliverUserMessage(k, "U2", sender.Uuid, messageBack)
+
+	select {
+	case got := <-sender.SendBack:
+		if got != messageBack {
+			t.Fatalf("sender got %v, want %v", got, messageBack)
+		}
+	default:
+		t.Fatal("sender did not get the echo")
+	}
+}
+
+func TestKafkaDeliverToClosedClient(t *testing.T) {
+	useNotifySettings(t, nil)
+	k := newTestKafkaServer()
+	receiver := newTestClient("U2")
+	receiver.closeChannels()
+	k.Clients[receiver.Uuid] = receiver
+
+	deliverUserMessage(k, receiver.Uuid, "U1", &MessageBack{Message: []byte(`{}`), Uuid: "M3"})
+	assertUnlocked(t, k)
+}
+
+func TestKafkaDeliverToGroupMentioned(t *testing.T) {
+	useNotifySettings(t, nil)
+	k := newTestKafkaServer()
+	sender := newTestClient("U1")
+	mentioned := newTestClient("U2")
+	other := newTestClient("U3")
+	for _, c := range []*Client{sender, mentioned, other} {
+		k.Clients[c.Uuid] = c
+	}
+
+	messageBack := &MessageBack{Message: []byte(`{}`), Uuid: "M4"}
+	mentionedBack := &MessageBack{Message: []byte(`{"is_mentioned":true}`), Uuid: "M4"}
+	deliverGroupMessage(k, "G1", sender.Uuid, []string{"U1", "U2", "U3", "U4"}, messageBack, mentionedBack, func(member string) bool {
+		return member == mentioned.Uuid
+	})
+
+	for c, want := range map[*Client]*MessageBack{sender: messageBack, mentioned: mentionedBack, other: messageBack} {
+		select {
+		case got := <-c.SendBack:
+			if got != want {
+				t.Fatalf("%s got %v, want %v", c.Uuid, got, want)
+			}
+		default:
+			t.Fatalf("%s did not get the group message", c.Uuid)
+		}
+	}
+	assertUnlocked(t, k)
+}
+
+// 机器人经SubmitBotMessage发送的消息，发送者没有websocket连接
+func TestKafkaDeliverBotReply(t *testing.T) {
+	useNotifySettings(t, nil)
+	k := newTestKafkaServer()
+	user := newTestClient("U2")
+	k.Clients[user.Uuid] = user
+
+	messageBack := &MessageBack{Message: []byte(`{"content":"bot reply"}`), Uuid: "M5"}
+	deliverUserMessage(k, user.Uuid, "UBOT", messageBack)
+	deliverGroupMessage(k, "G1", "UBOT", []string{"UBOT", user.Uuid}, messageBack, nil, nil)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case got := <-user.SendBack:
+			if got != messageBack {
+				t.Fatalf("user got %v, want %v", got, messageBack)
+			}
+		default:
+			t.Fatalf("user did not get bot reply %d", i+1)
+		}
+	}
+	assertUnlocked(t, k)
+}
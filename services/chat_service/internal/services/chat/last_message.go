@@ -0,0 +1,82 @@
+package chat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+const (
+	lastMessageWorkers   = 2           // 更新会话最新消息的协程数
+	lastMessageQueueSize = 1000        // 待更新的消息队列长度
+	lastMessageWait      = time.Second // 队列满时等待空位的最长时间
+)
+
+var lastMessageQueue = make(chan model.Message, lastMessageQueueSize)
+
+// StartLastMessageWorker 启动更新会话最新消息和未读数的协程
+// 多个协程之间可能乱序，由session_service按消息时间只保留最新的一条
+func StartLastMessageWorker() {
+	for i := 0; i < lastMessageWorkers; i++ {
+		go func() {
+			for message := range lastMessageQueue {
+				updateLastMessage(&message)
+				incrUnread(&message)
+			}
+		}()
+	}
+}
+
+// enqueueLastMessage 消息投递后加入更新队列，不阻塞消息发送，未成功落库的消息不更新
+// 队列满时短暂等待空位，仍然满则丢弃并记录日志
+func enqueueLastMessage(message *model.Message) {
+	if message.Id == 0 || message.Type == message_type_enum.AudioOrVideo {
+		return
+	}
+	select {
+	case lastMessageQueue <- *message:
+		return
+	default:
+	}
+	// 不另起协程计数，积压时协程数量会失控；等待期间阻塞的是当前会话的消息处理
+	select {
+	case lastMessageQueue <- *message:
+	case <-time.After(lastMessageWait):
+		zlog.Warn("会话最新消息队列已满，丢弃: " + message.Uuid)
+	}
+}
+
+// updateLastMessage 调用会话服务更新会话的最新消息摘要和时间
+func updateLastMessage(message *model.Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("update last message panic: %v", r))
+		}
+	}()
+	sessionClient, err := clients.GetGlobalSessionClient()
+	if err != nil {
+		zlog.Error("获取会话客户端失败: " + err.Error())
+		return
+	}
+	resp := sessionClient.UpdateLastMessage(message.SendId, message.ReceiveId, lastMessageSnippet(message), message.CreatedAt)
+	if resp == nil || resp.Code != 0 {
+		zlog.Error("更新会话最新消息失败: " + message.Uuid)
+	}
+}
+
+// lastMessageSnippet 会话列表中展示的最新消息摘要，群聊带上发送者昵称
+// 阅后即焚的消息不展示内容，避免过期后仍留在会话列表中
+func lastMessageSnippet(message *model.Message) string {
+	snippet := PinSnippet(message)
+	if message.ExpireAt.Valid {
+		snippet = "[阅后即焚消息]"
+	}
+	if message.ReceiveId[0] == 'G' && message.Type != message_type_enum.System {
+		return message.SendName + ": " + snippet
+	}
+	return snippet
+}
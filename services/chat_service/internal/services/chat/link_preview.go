@@ -0,0 +1,394 @@
+package chat
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"golang.org/x/net/html"
+)
+
+const EventLinkPreview = "link_preview" // 文本消息的链接预览已生成
+
+const (
+	linkPreviewWorkers      = 4               // 抓取协程数
+	linkPreviewQueueSize    = 1000            // 待抓取队列长度，满了直接丢弃
+	linkPreviewTimeout      = 5 * time.Second // 单次抓取的总超时，包括重定向
+	linkPreviewMaxBody      = 512 << 10       // 最多读取的网页大小
+	linkPreviewMaxRedirects = 3               // 最多跟随的重定向次数
+	linkPreviewCacheTTL     = 24 * time.Hour  // 预览结果的缓存时间
+	linkPreviewMissTTL      = time.Hour       // 抓取失败的缓存时间，避免反复抓取
+	linkPreviewTitleMaxLen  = 100             // 标题最大字符数
+	linkPreviewDescMaxLen   = 200             // 描述最大字符数
+	linkPreviewUrlMaxLen    = 2048            // 参与预览的链接最大长度
+	linkPreviewUserAgent    = "gogochat-link-preview/1.0"
+)
+
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"'，。！？、）】》]+`)
+
+var linkPreviewQueue = make(chan model.Message, linkPreviewQueueSize)
+
+// linkPreviewClient 抓取网页的http客户端，所有连接（包括重定向）都经过safeDialContext
+var linkPreviewClient = &http.Client{
+	Timeout: linkPreviewTimeout,
+	Transport: &http.Transport{
+		Proxy:                 nil, // 不走代理，代理会绕过对目标地址的校验
+		DialContext:           safeDialContext,
+		TLSHandshakeTimeout:   linkPreviewTimeout,
+		ResponseHeaderTimeout: linkPreviewTimeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= linkPreviewMaxRedirects {
+			return errors.New("重定向次数过多")
+		}
+		return checkPreviewUrl(req.URL)
+	},
+}
+
+// StartLinkPreviewWorker 启动链接预览的抓取协程
+func StartLinkPreviewWorker() {
+	for i := 0; i < linkPreviewWorkers; i++ {
+		go func() {
+			for message := range linkPreviewQueue {
+				generateLinkPreview(&message)
+			}
+		}()
+	}
+}
+
+// enqueueLinkPreview 文本消息落库后，包含链接时加入抓取队列，不阻塞消息发送
+func enqueueLinkPreview(message *model.Message) {
+	if message.Uuid == "" || !linkPattern.MatchString(message.Content) {
+		return
+	}
+	select {
+	case linkPreviewQueue <- *message:
+	default:
+		zlog.Warn("链接预览队列已满，丢弃: " + message.Uuid)
+	}
+}
+
+// generateLinkPreview 抓取消息中第一个链接的预览，写入消息的extra并通知会话参与者
+func generateLinkPreview(message *model.Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("link preview panic: %v", r))
+		}
+	}()
+	link := strings.TrimRight(linkPattern.FindString(message.Content), ".,;:!?)]}")
+	if link == "" || len(link) > linkPreviewUrlMaxLen {
+		return
+	}
+	preview, err := loadLinkPreview(link)
+	if err != nil {
+		zlog.Warn(fmt.Sprintf("生成链接预览失败 %s: %s", link, err.Error()))
+		return
+	}
+	if preview == nil {
+		return
+	}
+	extraByte, err := json.Marshal(respond.TextExtraRespond{LinkPreview: preview})
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	if res := dao.GormDB.Model(&model.Message{}).Where("uuid = ?", message.Uuid).Update("extra", string(extraByte)); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	purgeMessageListCache([]model.Message{*message})
+
+	receivers, err := messageReceivers(message)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	jsonMessage, err := json.Marshal(respond.LinkPreviewEventRespond{
+		Event:       EventLinkPreview,
+		MessageId:   message.Uuid,
+		SendId:      message.SendId,
+		ReceiveId:   message.ReceiveId,
+		LinkPreview: *preview,
+	})
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	eventBack := &MessageBack{Message: jsonMessage}
+	hub := currentHub()
+	for _, receiver := range receivers {
+		if client, ok := hub.GetClient(receiver); ok {
+			sendEventToClient(client, eventBack)
+		}
+	}
+}
+
+// link_preview_<sha1(url)>: 链接的预览结果，抓取失败时缓存空字符串
+func linkPreviewKey(link string) string {
+	sum := sha1.Sum([]byte(link))
+	return "link_preview_" + hex.EncodeToString(sum[:])
+}
+
+// loadLinkPreview 优先读取缓存，没有时抓取网页；网页没有可用的标题时返回nil
+func loadLinkPreview(link string) (*respond.LinkPreviewRespond, error) {
+	key := linkPreviewKey(link)
+	cached, err := cache.GetGlobalCache().GetKeyNilIsErr(key)
+	if err == nil {
+		if cached == "" {
+			return nil, nil
+		}
+		var preview respond.LinkPreviewRespond
+		if err := json.Unmarshal([]byte(cached), &preview); err != nil {
+			return nil, err
+		}
+		return &preview, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		zlog.Error(err.Error())
+	}
+	preview, fetchErr := fetchLinkPreview(link)
+	if fetchErr != nil || preview == nil {
+		if err := cache.GetGlobalCache().SetKeyEx(key, "", linkPreviewMissTTL); err != nil {
+			zlog.Error(err.Error())
+		}
+		return nil, fetchErr
+	}
+	previewByte, err := json.Marshal(preview)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.GetGlobalCache().SetKeyEx(key, string(previewByte), linkPreviewCacheTTL); err != nil {
+		zlog.Error(err.Error())
+	}
+	return preview, nil
+}
+
+// fetchLinkPreview 抓取网页并解析预览信息，只读取html且限制大小
+func fetchLinkPreview(link string) (*respond.LinkPreviewRespond, error) {
+	pageUrl, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPreviewUrl(pageUrl); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), linkPreviewTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageUrl.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", linkPreviewUserAgent)
+	req.Header.Set("Accept", "text/html")
+	rsp, err := linkPreviewClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("状态码%d", rsp.StatusCode)
+	}
+	mediaType, _, err := mime.ParseMediaType(rsp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return nil, errors.New("不是html页面")
+	}
+	if rsp.ContentLength > linkPreviewMaxBody {
+		return nil, errors.New("页面过大")
+	}
+	// 重定向后以最终地址为准解析相对路径
+	return parseLinkPreview(io.LimitReader(rsp.Body, linkPreviewMaxBody), rsp.Request.URL), nil
+}
+
+// parseLinkPreview 从html的head中提取og:*和twitter:*标签，缺少时退回到title和description
+func parseLinkPreview(body io.Reader, pageUrl *url.URL) *respond.LinkPreviewRespond {
+	meta := make(map[string]string)
+	var title string
+	tokenizer := html.NewTokenizer(body)
+	inTitle := false
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+		if tokenType == html.EndTagToken && token.Data == "head" {
+			break
+		}
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch token.Data {
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				var name, content string
+				for _, attr := range token.Attr {
+					switch strings.ToLower(attr.Key) {
+					case "property", "name":
+						name = strings.ToLower(attr.Val)
+					case "content":
+						content = attr.Val
+					}
+				}
+				if name != "" && content != "" {
+					if _, ok := meta[name]; !ok {
+						meta[name] = content
+					}
+				}
+			case "body":
+				// head结束标签可以省略，进入body后不再解析
+				inTitle = false
+			}
+		case html.TextToken:
+			if inTitle {
+				title = token.Data
+				inTitle = false
+			}
+		}
+	}
+	preview := &respond.LinkPreviewRespond{
+		Url:         pageUrl.String(),
+		Title:       firstNonEmpty(meta["og:title"], meta["twitter:title"], title),
+		Description: firstNonEmpty(meta["og:description"], meta["twitter:description"], meta["description"]),
+		SiteName:    meta["og:site_name"],
+	}
+	preview.Title = truncateRunes(strings.TrimSpace(preview.Title), linkPreviewTitleMaxLen)
+	if preview.Title == "" {
+		return nil
+	}
+	preview.Description = truncateRunes(strings.TrimSpace(preview.Description), linkPreviewDescMaxLen)
+	preview.SiteName = truncateRunes(strings.TrimSpace(preview.SiteName), linkPreviewTitleMaxLen)
+	if image := firstNonEmpty(meta["og:image"], meta["og:image:url"], meta["twitter:image"]); image != "" {
+		// 图片只下发地址，由客户端加载，服务端不请求图片
+		if imageUrl, err := pageUrl.Parse(strings.TrimSpace(image)); err == nil &&
+			(imageUrl.Scheme == "http" || imageUrl.Scheme == "https") && len(imageUrl.String()) <= linkPreviewUrlMaxLen {
+			preview.Image = imageUrl.String()
+		}
+	}
+	return preview
+}
+
+// checkPreviewUrl 只允许默认端口上的http和https，ip直接写在链接中时同样需要是公网地址
+func checkPreviewUrl(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("不支持的协议: " + u.Scheme)
+	}
+	if u.User != nil {
+		return errors.New("链接中不能带有用户信息")
+	}
+	if port := u.Port(); port != "" && !allowPreviewPort(port) {
+		return errors.New("不支持的端口: " + port)
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !allowDialIP(ip) {
+		return errors.New("不允许访问内网地址")
+	}
+	return nil
+}
+
+// safeDialContext 解析域名后校验全部地址，只连接校验过的ip，防止通过DNS重绑定访问内网
+func safeDialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ipAddrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ipAddrs) == 0 {
+		return nil, errors.New("域名没有解析结果: " + host)
+	}
+	for _, ipAddr := range ipAddrs {
+		if !allowDialIP(ipAddr.IP) {
+			return nil, errors.New("不允许访问内网地址: " + host)
+		}
+	}
+	dialer := &net.Dialer{Timeout: linkPreviewTimeout}
+	return dialer.DialContext(ctx, network, net.JoinHostPort(ipAddrs[0].IP.String(), port))
+}
+
+// allowDialIP 对外发起请求时允许连接的地址，默认只允许公网地址；测试中替换以便连接本地的测试服务器
+var allowDialIP = isPublicIP
+
+// allowPreviewPort 链接预览允许访问的端口，默认只允许http和https的默认端口
+var allowPreviewPort = func(port string) bool {
+	return port == "80" || port == "443"
+}
+
+// 不允许访问的地址段：共享地址、基准测试、文档示例等非公网地址
+var blockedNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",
+		"100.64.0.0/10",
+		"192.0.0.0/24",
+		"192.0.2.0/24",
+		"198.18.0.0/15",
+		"198.51.100.0/24",
+		"203.0.113.0/24",
+		"240.0.0.0/4",
+		"64:ff9b::/96",
+		"2001:db8::/32",
+	} {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}()
+
+// isPublicIP 判断是否为可以访问的公网地址，排除回环、内网、链路本地、组播等地址
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	// ipv4映射的ipv6地址按ipv4判断
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, ipNet := range blockedNets {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// truncateRunes 按字符截断，避免中文被截成乱码
+func truncateRunes(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	return string([]rune(s)[:maxLen]) + "..."
+}
@@ -0,0 +1,151 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+// prepareMentions 校验@参数，并把@列表写入待保存的消息
+// 只有群聊消息可以@成员，被@的必须是群成员；@所有人仅限群主或管理员
+func prepareMentions(message *model.Message, mentions []string, mentionAll bool) error {
+	if len(mentions) == 0 && !mentionAll {
+		return nil
+	}
+	if message.ReceiveId[0] != 'G' {
+		return errors.New("只有群聊消息可以@成员")
+	}
+	var group model.GroupInfo
+	if res := dao.GormDB.Where("uuid = ?", message.ReceiveId).First(&group); res.Error != nil {
+		return res.Error
+	}
+	var members []string
+	if err := json.Unmarshal(group.Members, &members); err != nil {
+		return err
+	}
+	memberSet := make(map[string]bool, len(members))
+	for _, member := range members {
+		memberSet[member] = true
+	}
+	if mentionAll && !IsGroupManager(&group, message.SendId) {
+		return errors.New("只有群主或管理员可以@所有人")
+	}
+	// 去重，保持客户端传入的顺序
+	seen := make(map[string]bool, len(mentions))
+	var validMentions []string
+	for _, userId := range mentions {
+		if seen[userId] {
+			continue
+		}
+		if !memberSet[userId] {
+			return errors.New("被@的用户" + userId + "不是群成员")
+		}
+		seen[userId] = true
+		validMentions = append(validMentions, userId)
+	}
+	if len(validMentions) > 0 {
+		mentionsByte, err := json.Marshal(validMentions)
+		if err != nil {
+			return err
+		}
+		message.Mentions = string(mentionsByte)
+	}
+	message.MentionAll = mentionAll
+	return nil
+}
+
+// IsGroupManager 判断用户是否为群主或群管理员
+func IsGroupManager(group *model.GroupInfo, userId string) bool {
+	if group.OwnerId == userId {
+		return true
+	}
+	if len(group.Admins) == 0 {
+		return false
+	}
+	var admins []string
+	if err := json.Unmarshal(group.Admins, &admins); err != nil {
+		zlog.Error(err.Error())
+		return false
+	}
+	for _, admin := range admins {
+		if admin == userId {
+			return true
+		}
+	}
+	return false
+}
+
+// recordMentions 消息落库后记录@关系，@所有人时展开为除发送者外的全部成员
+func recordMentions(message *model.Message, members []string) {
+	if message.Mentions == "" && !message.MentionAll {
+		return
+	}
+	var mentionRecords []model.MessageMention
+	if message.MentionAll {
+		for _, member := range members {
+			if member == message.SendId {
+				continue
+			}
+			mentionRecords = append(mentionRecords, model.MessageMention{
+				MessageId: message.Uuid,
+				GroupId:   message.ReceiveId,
+				UserId:    member,
+				SendId:    message.SendId,
+				IsAll:     true,
+				CreatedAt: message.CreatedAt,
+			})
+		}
+	} else {
+		for _, userId := range respond.NewGetGroupMessageListRespond(message).Mentions {
+			if userId == message.SendId {
+				continue
+			}
+			mentionRecords = append(mentionRecords, model.MessageMention{
+				MessageId: message.Uuid,
+				GroupId:   message.ReceiveId,
+				UserId:    userId,
+				SendId:    message.SendId,
+				CreatedAt: message.CreatedAt,
+			})
+		}
+	}
+	if len(mentionRecords) == 0 {
+		return
+	}
+	if res := dao.GormDB.Create(&mentionRecords); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+}
+
+// isMentioned 判断群成员是否被这条消息@到
+func isMentioned(messageRsp *respond.GetGroupMessageListRespond, member string) bool {
+	if member == messageRsp.SendId {
+		return false
+	}
+	if messageRsp.MentionAll {
+		return true
+	}
+	for _, userId := range messageRsp.Mentions {
+		if userId == member {
+			return true
+		}
+	}
+	return false
+}
+
+// newMentionedBack 构造下发给被@成员的消息帧，带上is_mentioned标记
+func newMentionedBack(messageRsp respond.GetGroupMessageListRespond) *MessageBack {
+	messageRsp.IsMentioned = true
+	jsonMessage, err := json.Marshal(messageRsp)
+	if err != nil {
+		zlog.Error(err.Error())
+	}
+	return &MessageBack{
+		Message: jsonMessage,
+		Uuid:    messageRsp.Uuid,
+	}
+}
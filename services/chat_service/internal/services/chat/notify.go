@@ -0,0 +1,78 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/notify"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+// LoadNotifySettings 从数据库读取用户的免打扰设置，缓存失效时由notify.Load调用
+func LoadNotifySettings(userId string) (*notify.Settings, error) {
+	var muteList []model.SessionMute
+	if res := dao.GormDB.Where("user_id = ?", userId).Find(&muteList); res.Error != nil {
+		return nil, res.Error
+	}
+	settings := &notify.Settings{Mutes: make(map[string]notify.Mute, len(muteList))}
+	for _, mute := range muteList {
+		m := notify.Mute{
+			TargetId:     mute.TargetId,
+			AllowMention: mute.AllowMention,
+		}
+		if mute.MutedUntil.Valid {
+			mutedUntil := mute.MutedUntil.Time
+			m.MutedUntil = &mutedUntil
+		}
+		settings.Mutes[mute.TargetId] = m
+	}
+	var dnd model.DndSetting
+	if res := dao.GormDB.Where("user_id = ?", userId).First(&dnd); res.Error != nil {
+		if !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, res.Error
+		}
+		return settings, nil
+	}
+	settings.Dnd = &notify.Dnd{
+		Enabled:      dnd.Enabled,
+		StartTime:    dnd.StartTime,
+		EndTime:      dnd.EndTime,
+		Timezone:     dnd.Timezone,
+		AllowMention: dnd.AllowMention,
+	}
+	return settings, nil
+}
+
+// isSilenced 判断给用户的新消息提醒是否应该静默，查询失败时照常提醒
+func isSilenced(userId, targetId string, mentioned bool) bool {
+	settings, err := notify.Load(userId, LoadNotifySettings)
+	if err != nil {
+		zlog.Error(err.Error())
+		return false
+	}
+	return settings.Silenced(targetId, mentioned, time.Now())
+}
+
+// recipientBack 按接收者的免打扰设置返回下发给他的消息，静默时复制一份带silent标记的消息
+// 提醒与否随消息本身下发，不依赖之后的unread_updated事件
+func recipientBack(recipient, targetId string, mentioned bool, messageBack *MessageBack) *MessageBack {
+	if !isSilenced(recipient, targetId, mentioned) {
+		return messageBack
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(messageBack.Message, &fields); err != nil {
+		zlog.Error(err.Error())
+		return messageBack
+	}
+	fields["silent"] = json.RawMessage("true")
+	jsonMessage, err := json.Marshal(fields)
+	if err != nil {
+		zlog.Error(err.Error())
+		return messageBack
+	}
+	return &MessageBack{Message: jsonMessage, Uuid: messageBack.Uuid}
+}
@@ -0,0 +1,74 @@
+package chat
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+const (
+	EventMessagePinned   = "message_pinned"   // 消息被置顶
+	EventMessageUnpinned = "message_unpinned" // 消息被取消置顶
+)
+
+// 非文本消息在置顶摘要中的展示
+var pinSnippetLabels = map[int8]string{
+	message_type_enum.Voice:         "[语音]",
+	message_type_enum.File:          "[文件]",
+	message_type_enum.MergedForward: "[聊天记录]",
+	message_type_enum.Image:         "[图片]",
+	message_type_enum.Location:      "[位置]",
+	message_type_enum.ContactCard:   "[名片]",
+	message_type_enum.Sticker:       "[表情]",
+	message_type_enum.Poll:          "[投票]",
+}
+
+// PinSnippet 生成置顶消息的摘要，文本按引用摘要的规则截断
+func PinSnippet(message *model.Message) string {
+	if label, ok := pinSnippetLabels[message.Type]; ok {
+		if message.Type == message_type_enum.File && message.FileName != "" {
+			return label + quoteSnippet(message.FileName)
+		}
+		if message.Type == message_type_enum.Poll {
+			return label + quoteSnippet(message.Content)
+		}
+		return label
+	}
+	return quoteSnippet(message.Content)
+}
+
+// BroadcastPinEvent 把置顶变更推送给会话中在线的参与者，包括操作人自己
+func BroadcastPinEvent(event, operatorId string, message *model.Message, snippet string) {
+	eventRsp := respond.PinEventRespond{
+		Event:     event,
+		SendId:    operatorId,
+		ReceiveId: message.ReceiveId,
+		MessageId: message.Uuid,
+		Snippet:   snippet,
+		CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
+	}
+	if message.ReceiveId[0] == 'U' && operatorId == message.ReceiveId {
+		eventRsp.ReceiveId = message.SendId
+	}
+	receivers, err := messageReceivers(message)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	jsonMessage, err := json.Marshal(eventRsp)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	eventBack := &MessageBack{Message: jsonMessage}
+	hub := currentHub()
+	for _, receiver := range receivers {
+		if client, ok := hub.GetClient(receiver); ok {
+			sendEventToClient(client, eventBack)
+		}
+	}
+}
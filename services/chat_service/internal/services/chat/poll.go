@@ -0,0 +1,363 @@
+package chat
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	EventPollVote       = "poll_vote"        // 客户端通过websocket投票
+	EventPollVoteFailed = "poll_vote_failed" // websocket投票失败
+	EventPollUpdated    = "poll_updated"     // 投票统计变化
+)
+
+const (
+	pollQuestionMaxLen = 200 // 投票题目最大字符数
+	pollOptionMaxLen   = 50  // 单个选项最大字符数
+	pollMinOptions     = 2   // 最少选项数
+	pollMaxOptions     = 10  // 最多选项数
+	pollMaxDays        = 30  // 截止时间最远的天数
+)
+
+// errPollRejected 投票请求不满足业务条件，具体原因由调用方单独返回
+var errPollRejected = errors.New("poll rejected")
+
+// preparePoll 校验投票消息，只能在自己所在的群聊中发起
+func preparePoll(message *model.Message, req *request.ChatMessageRequest) error {
+	if message.ReceiveId[0] != 'G' {
+		return errors.New("只有群聊可以发起投票")
+	}
+	members, err := groupMembers(message.ReceiveId)
+	if err != nil {
+		return err
+	}
+	if !containsString(members, message.SendId) {
+		return errors.New("你不是该群成员")
+	}
+	var extra respond.PollExtraRespond
+	if err := decodeExtra(req.Extra, &extra); err != nil {
+		return err
+	}
+	extra.Question = strings.TrimSpace(extra.Question)
+	if extra.Question == "" || utf8.RuneCountInString(extra.Question) > pollQuestionMaxLen {
+		return errors.New("投票题目不合法")
+	}
+	if len(extra.Options) < pollMinOptions || len(extra.Options) > pollMaxOptions {
+		return errors.New("投票选项数量不合法")
+	}
+	seen := make(map[string]bool, len(extra.Options))
+	for i, option := range extra.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || utf8.RuneCountInString(option) > pollOptionMaxLen {
+			return errors.New("投票选项不合法")
+		}
+		if seen[option] {
+			return errors.New("投票选项重复: " + option)
+		}
+		seen[option] = true
+		extra.Options[i] = option
+	}
+	if extra.Deadline != "" {
+		deadline, err := time.ParseInLocation("2006-01-02 15:04:05", extra.Deadline, time.Local)
+		if err != nil {
+			return errors.New("截止时间格式不合法")
+		}
+		now := time.Now()
+		if !deadline.After(now) || deadline.After(now.AddDate(0, 0, pollMaxDays)) {
+			return errors.New("截止时间不合法")
+		}
+	}
+	// 题目作为消息内容，便于搜索和生成摘要
+	message.Content = extra.Question
+	return setExtra(message, extra)
+}
+
+// createPoll 投票消息落库时在同一事务中创建投票记录
+func createPoll(tx *gorm.DB, message *model.Message) error {
+	var extra respond.PollExtraRespond
+	if err := json.Unmarshal([]byte(message.Extra), &extra); err != nil {
+		return err
+	}
+	optionsByte, err := json.Marshal(extra.Options)
+	if err != nil {
+		return err
+	}
+	poll := model.Poll{
+		MessageId: message.Uuid,
+		GroupId:   message.ReceiveId,
+		CreatorId: message.SendId,
+		Question:  extra.Question,
+		Options:   string(optionsByte),
+		Multiple:  extra.Multiple,
+		Anonymous: extra.Anonymous,
+		CreatedAt: message.CreatedAt,
+	}
+	if extra.Deadline != "" {
+		deadline, err := time.ParseInLocation("2006-01-02 15:04:05", extra.Deadline, time.Local)
+		if err != nil {
+			return err
+		}
+		poll.Deadline = sql.NullTime{Time: deadline, Valid: true}
+	}
+	return tx.Create(&poll).Error
+}
+
+// pollClosed 手动结束或已过截止时间的投票不能再修改
+func pollClosed(poll *model.Poll, now time.Time) bool {
+	return poll.Closed || (poll.Deadline.Valid && !now.Before(poll.Deadline.Time))
+}
+
+// pollOptions 解析投票的选项列表
+func pollOptions(poll *model.Poll) ([]string, error) {
+	var options []string
+	if err := json.Unmarshal([]byte(poll.Options), &options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// containsString 判断列表中是否包含指定字符串
+func containsString(list []string, target string) bool {
+	for _, item := range list {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
+
+// VotePoll 投票，重复投票时覆盖之前的选择，选项为空表示撤回投票
+// 投票时以群成员列表为准，退群后不能再投票
+func VotePoll(userId, messageId string, optionIndexes []int) (string, int) {
+	var poll model.Poll
+	var rejectMsg string
+	err := dao.GormDB.Transaction(func(tx *gorm.DB) error {
+		// 锁住投票记录，避免与结束投票并发
+		if res := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("message_id = ?", messageId).First(&poll); res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				rejectMsg = "投票不存在"
+				return errPollRejected
+			}
+			return res.Error
+		}
+		if pollClosed(&poll, time.Now()) {
+			rejectMsg = "投票已结束"
+			return errPollRejected
+		}
+		members, err := groupMembers(poll.GroupId)
+		if err != nil {
+			return err
+		}
+		if !containsString(members, userId) {
+			rejectMsg = "你不是该群成员"
+			return errPollRejected
+		}
+		options, err := pollOptions(&poll)
+		if err != nil {
+			return err
+		}
+		seen := make(map[int]bool, len(optionIndexes))
+		var voteList []model.PollVote
+		now := time.Now()
+		for _, index := range optionIndexes {
+			if index < 0 || index >= len(options) {
+				rejectMsg = "投票选项不存在"
+				return errPollRejected
+			}
+			if seen[index] {
+				continue
+			}
+			seen[index] = true
+			voteList = append(voteList, model.PollVote{
+				MessageId:   poll.MessageId,
+				UserId:      userId,
+				OptionIndex: index,
+				CreatedAt:   now,
+			})
+		}
+		if !poll.Multiple && len(voteList) > 1 {
+			rejectMsg = "该投票为单选"
+			return errPollRejected
+		}
+		if res := tx.Where("message_id = ? AND user_id = ?", poll.MessageId, userId).Delete(&model.PollVote{}); res.Error != nil {
+			return res.Error
+		}
+		if len(voteList) == 0 {
+			return nil
+		}
+		return tx.Create(&voteList).Error
+	})
+	if err != nil {
+		if errors.Is(err, errPollRejected) {
+			return rejectMsg, -2
+		}
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	broadcastPollResult(userId, &poll)
+	return "投票成功", 0
+}
+
+// ClosePoll 提前结束投票，发起人、群主和管理员可以操作
+func ClosePoll(userId, messageId string) (string, int) {
+	var poll model.Poll
+	if res := dao.GormDB.Where("message_id = ?", messageId).First(&poll); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "投票不存在", -2
+		}
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	var group model.GroupInfo
+	if res := dao.GormDB.Where("uuid = ?", poll.GroupId).First(&group); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	var members []string
+	if err := json.Unmarshal(group.Members, &members); err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if !containsString(members, userId) || (poll.CreatorId != userId && !IsGroupManager(&group, userId)) {
+		return "只有发起人、群主或管理员可以结束投票", -2
+	}
+	now := time.Now()
+	// 条件更新，已结束的投票不会被重复结束
+	res := dao.GormDB.Model(&model.Poll{}).
+		Where("message_id = ? AND closed = ? AND (deadline IS NULL OR deadline > ?)", messageId, false, now).
+		Updates(map[string]interface{}{
+			"closed":    true,
+			"closed_by": userId,
+			"closed_at": now,
+		})
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res.RowsAffected == 0 {
+		return "投票已结束", -2
+	}
+	poll.Closed = true
+	poll.ClosedBy = userId
+	poll.ClosedAt = sql.NullTime{Time: now, Valid: true}
+	broadcastPollResult(userId, &poll)
+	return "投票已结束", 0
+}
+
+// PollResult 统计投票结果，viewerId不为空时带上查询者自己的选择
+func PollResult(poll *model.Poll, viewerId string) (*respond.PollResultRespond, error) {
+	options, err := pollOptions(poll)
+	if err != nil {
+		return nil, err
+	}
+	var voteList []model.PollVote
+	if res := dao.GormDB.Where("message_id = ?", poll.MessageId).Order("id ASC").Find(&voteList); res.Error != nil {
+		return nil, res.Error
+	}
+	rsp := &respond.PollResultRespond{
+		MessageId: poll.MessageId,
+		GroupId:   poll.GroupId,
+		Question:  poll.Question,
+		Options:   make([]respond.PollOptionRespond, len(options)),
+		Multiple:  poll.Multiple,
+		Anonymous: poll.Anonymous,
+		Closed:    pollClosed(poll, time.Now()),
+	}
+	if poll.Deadline.Valid {
+		rsp.Deadline = poll.Deadline.Time.Format("2006-01-02 15:04:05")
+	}
+	for i, option := range options {
+		rsp.Options[i] = respond.PollOptionRespond{Index: i, Text: option}
+	}
+	voters := make(map[string]bool)
+	for _, vote := range voteList {
+		if vote.OptionIndex < 0 || vote.OptionIndex >= len(options) {
+			continue
+		}
+		option := &rsp.Options[vote.OptionIndex]
+		option.Count++
+		if !poll.Anonymous {
+			option.Voters = append(option.Voters, vote.UserId)
+		}
+		voters[vote.UserId] = true
+		if viewerId != "" && vote.UserId == viewerId {
+			rsp.MyVotes = append(rsp.MyVotes, vote.OptionIndex)
+		}
+	}
+	rsp.TotalVoters = len(voters)
+	return rsp, nil
+}
+
+// broadcastPollResult 把最新的投票统计推送给群内在线成员
+func broadcastPollResult(operatorId string, poll *model.Poll) {
+	result, err := PollResult(poll, "")
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	members, err := groupMembers(poll.GroupId)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	jsonMessage, err := json.Marshal(respond.PollEventRespond{
+		Event:     EventPollUpdated,
+		SendId:    operatorId,
+		ReceiveId: poll.GroupId,
+		Poll:      *result,
+		CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	eventBack := &MessageBack{Message: jsonMessage}
+	hub := currentHub()
+	for _, member := range members {
+		if client, ok := hub.GetClient(member); ok {
+			sendEventToClient(client, eventBack)
+		}
+	}
+}
+
+// handlePollVoteEvent 处理websocket上的投票，投票人以连接身份为准，失败时只通知投票人
+func handlePollVoteEvent(c *Client, req *request.WsEventRequest) {
+	msg, ret := VotePoll(c.Uuid, req.MessageId, req.Options)
+	if ret == 0 {
+		return
+	}
+	jsonMessage, err := json.Marshal(respond.PollVoteFailedRespond{
+		Event:     EventPollVoteFailed,
+		MessageId: req.MessageId,
+		Message:   msg,
+	})
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	sendEventToClient(c, &MessageBack{Message: jsonMessage})
+}
+
+// removePollsOfMessages 投票消息被删除时清理投票和投票记录
+func removePollsOfMessages(messageIds []string) error {
+	if len(messageIds) == 0 {
+		return nil
+	}
+	if res := dao.GormDB.Where("message_id IN ?", messageIds).Delete(&model.PollVote{}); res.Error != nil {
+		return res.Error
+	}
+	return dao.GormDB.Where("message_id IN ?", messageIds).Delete(&model.Poll{}).Error
+}
@@ -0,0 +1,326 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/puoxiu/gogochat/common/cache"
+	mykafka "github.com/puoxiu/gogochat/common/kafka"
+	"github.com/puoxiu/gogochat/pkg/enum/user_info/presence_status_enum"
+	"github.com/puoxiu/gogochat/pkg/random"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/config"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	EventPresence       = "presence"        // 在线状态变更
+	EventPresenceAway   = "presence_away"   // 客户端切到后台，状态改为离开
+	EventPresenceActive = "presence_active" // 客户端回到前台，状态改回在线
+)
+
+const (
+	presenceTTL          = 30 * time.Second // 在线状态的过期时间，心跳续期，实例宕机后自动变为离线
+	presenceSubscribeTTL = 24 * time.Hour   // 订阅关系的过期时间，兜底清理未正常退出的订阅
+)
+
+// 在线状态相关的redis key
+// presence_<uuid>: 用户当前的在线状态
+// presence_conn_<uuid>: 用户连接所在的实例，field为实例id，value为最近一次心跳的时间戳
+// presence_sub_<uuid>: 订阅了该用户状态的用户集合
+// presence_subscribing_<uuid>: 该用户订阅的用户集合，退出时据此清理
+func presenceKey(userId string) string            { return "presence_" + userId }
+func presenceConnKey(userId string) string        { return "presence_conn_" + userId }
+func presenceSubKey(userId string) string         { return "presence_sub_" + userId }
+func presenceSubscribingKey(userId string) string { return "presence_subscribing_" + userId }
+
+// presenceInstanceId 本实例的标识，用户可能同时连在多个实例上，只有所有连接都断开才算离线
+var presenceInstanceId = fmt.Sprintf("%d-%s", os.Getpid(), random.GetNowAndLenRandomString(6))
+
+// holdPresenceConn 记录用户在本实例上有连接，心跳时续期
+func holdPresenceConn(userId string) {
+	if err := cache.GetGlobalCache().SetHashField(presenceConnKey(userId), presenceInstanceId, strconv.FormatInt(time.Now().Unix(), 10)); err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	if err := cache.GetGlobalCache().ExpireKey(presenceConnKey(userId), presenceTTL); err != nil {
+		zlog.Error(err.Error())
+	}
+}
+
+// releasePresenceConn 移除用户在本实例上的连接，返回用户是否还连在其他实例上
+// 其他实例的心跳超过presenceTTL没有续期时视为已宕机，不再算在线
+func releasePresenceConn(userId string) (bool, error) {
+	if err := cache.GetGlobalCache().DelHashField(presenceConnKey(userId), presenceInstanceId); err != nil {
+		return false, err
+	}
+	conns, err := cache.GetGlobalCache().GetHashAll(presenceConnKey(userId))
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for _, value := range conns {
+		heartbeatAt, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		if now.Sub(time.Unix(heartbeatAt, 0)) < presenceTTL {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// onClientLogin 用户连上聊天服务器，记录上线时间并通知订阅者
+func onClientLogin(userId string) {
+	holdPresenceConn(userId)
+	now := time.Now()
+	if res := dao.GormDB.Model(&model.UserInfo{}).Where("uuid = ?", userId).Update("last_online_at", now); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+	presence := respond.PresenceRespond{
+		UserId:       userId,
+		Status:       presence_status_enum.ONLINE,
+		LastOnlineAt: now.Format("2006-01-02 15:04:05"),
+	}
+	if err := savePresence(&presence); err != nil {
+		zlog.Error(err.Error())
+	}
+	publishPresence(&presence)
+}
+
+// onClientLogout 用户断开连接，记录离线时间，通知订阅者并清理自己的订阅
+// 用户还连在其他实例上时只移除本实例的连接，状态保持在线
+func onClientLogout(userId string) {
+	stillOnline, err := releasePresenceConn(userId)
+	if err != nil {
+		zlog.Error(err.Error())
+	} else if stillOnline {
+		return
+	}
+	now := time.Now()
+	if res := dao.GormDB.Model(&model.UserInfo{}).Where("uuid = ?", userId).Update("last_offline_at", now); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+	presence := respond.PresenceRespond{
+		UserId:        userId,
+		Status:        presence_status_enum.OFFLINE,
+		LastOfflineAt: now.Format("2006-01-02 15:04:05"),
+	}
+	if old, err := loadPresence(userId); err == nil && old != nil {
+		presence.LastOnlineAt = old.LastOnlineAt
+	}
+	if err := cache.GetGlobalCache().DelKeyIfExists(presenceKey(userId)); err != nil {
+		zlog.Error(err.Error())
+	}
+	publishPresence(&presence)
+	if err := ReplacePresenceSubscriptions(userId, nil); err != nil {
+		zlog.Error(err.Error())
+	}
+}
+
+// refreshPresence 心跳时续期在线状态
+func refreshPresence(userId string) {
+	holdPresenceConn(userId)
+	if err := cache.GetGlobalCache().ExpireKey(presenceKey(userId), presenceTTL); err != nil {
+		zlog.Error(err.Error())
+	}
+}
+
+// setPresenceAway 客户端上报前后台切换，在线和离开之间变化时通知订阅者
+func setPresenceAway(userId string, away bool) {
+	presence, err := loadPresence(userId)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	if presence == nil {
+		return
+	}
+	status := int8(presence_status_enum.ONLINE)
+	if away {
+		status = presence_status_enum.AWAY
+	}
+	if presence.Status == status {
+		return
+	}
+	presence.Status = status
+	if err := savePresence(presence); err != nil {
+		zlog.Error(err.Error())
+	}
+	publishPresence(presence)
+}
+
+func savePresence(presence *respond.PresenceRespond) error {
+	presenceByte, err := json.Marshal(presence)
+	if err != nil {
+		return err
+	}
+	return cache.GetGlobalCache().SetKeyEx(presenceKey(presence.UserId), string(presenceByte), presenceTTL)
+}
+
+// loadPresence 读取用户当前的在线状态，不在线时返回nil
+func loadPresence(userId string) (*respond.PresenceRespond, error) {
+	presenceString, err := cache.GetGlobalCache().GetKeyNilIsErr(presenceKey(userId))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var presence respond.PresenceRespond
+	if err := json.Unmarshal([]byte(presenceString), &presence); err != nil {
+		return nil, err
+	}
+	return &presence, nil
+}
+
+// GetPresence 批量读取在线状态，不在线的用户不在返回的map中
+func GetPresence(userIds []string) (map[string]*respond.PresenceRespond, error) {
+	keys := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		keys = append(keys, presenceKey(userId))
+	}
+	values, err := cache.GetGlobalCache().GetKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	rsp := make(map[string]*respond.PresenceRespond, len(values))
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		var presence respond.PresenceRespond
+		if err := json.Unmarshal([]byte(value), &presence); err != nil {
+			zlog.Error(err.Error())
+			continue
+		}
+		rsp[presence.UserId] = &presence
+	}
+	return rsp, nil
+}
+
+// ReplacePresenceSubscriptions 用新的订阅列表替换用户之前的订阅，userIds为空时取消全部订阅
+func ReplacePresenceSubscriptions(ownerId string, userIds []string) error {
+	oldIds, err := cache.GetGlobalCache().GetSetMembers(presenceSubscribingKey(ownerId))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	for _, userId := range oldIds {
+		if err := cache.GetGlobalCache().RemoveFromSet(presenceSubKey(userId), ownerId); err != nil {
+			return err
+		}
+	}
+	if err := cache.GetGlobalCache().DelKeyIfExists(presenceSubscribingKey(ownerId)); err != nil {
+		return err
+	}
+	if len(userIds) == 0 {
+		return nil
+	}
+	for _, userId := range userIds {
+		if err := cache.GetGlobalCache().AddToSet(presenceSubKey(userId), ownerId); err != nil {
+			return err
+		}
+		if err := cache.GetGlobalCache().ExpireKey(presenceSubKey(userId), presenceSubscribeTTL); err != nil {
+			return err
+		}
+	}
+	if err := cache.GetGlobalCache().AddToSet(presenceSubscribingKey(ownerId), userIds...); err != nil {
+		return err
+	}
+	return cache.GetGlobalCache().ExpireKey(presenceSubscribingKey(ownerId), presenceSubscribeTTL)
+}
+
+// publishPresence 广播在线状态变更
+// kafka模式下可能有多个聊天实例，订阅者可能连在其他实例上，通过login/logout主题广播；channel模式直接推送
+func publishPresence(presence *respond.PresenceRespond) {
+	if config.AppConfig.KafkaConfig.MessageMode == "channel" {
+		pushPresence(presence)
+		return
+	}
+	presenceByte, err := json.Marshal(presence)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	writer := mykafka.KafkaService.LoginWriter
+	if presence.Status == presence_status_enum.OFFLINE {
+		writer = mykafka.KafkaService.LogoutWriter
+	}
+	if writer == nil {
+		pushPresence(presence)
+		return
+	}
+	if err := writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(presence.UserId),
+		Value: presenceByte,
+	}); err != nil {
+		zlog.Error(err.Error())
+	}
+}
+
+// StartPresenceConsumer 消费login/logout主题，把状态变更推送给连在本实例上的订阅者
+func StartPresenceConsumer() {
+	for _, reader := range []*kafka.Reader{mykafka.KafkaService.LoginReader, mykafka.KafkaService.LogoutReader} {
+		if reader == nil {
+			continue
+		}
+		go func(reader *kafka.Reader) {
+			defer func() {
+				if r := recover(); r != nil {
+					zlog.Error(fmt.Sprintf("presence consumer panic: %v", r))
+				}
+			}()
+			for {
+				kafkaMessage, err := reader.ReadMessage(ctx)
+				if err != nil {
+					zlog.Error(err.Error())
+					return
+				}
+				var presence respond.PresenceRespond
+				if err := json.Unmarshal(kafkaMessage.Value, &presence); err != nil {
+					zlog.Error(err.Error())
+					continue
+				}
+				pushPresence(&presence)
+			}
+		}(reader)
+	}
+}
+
+// pushPresence 推送给连在本实例上的订阅者
+func pushPresence(presence *respond.PresenceRespond) {
+	subscribers, err := cache.GetGlobalCache().GetSetMembers(presenceSubKey(presence.UserId))
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			zlog.Error(err.Error())
+		}
+		return
+	}
+	if len(subscribers) == 0 {
+		return
+	}
+	jsonMessage, err := json.Marshal(respond.PresenceEventRespond{
+		Event:           EventPresence,
+		PresenceRespond: *presence,
+	})
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	eventBack := &MessageBack{Message: jsonMessage}
+	hub := currentHub()
+	for _, subscriber := range subscribers {
+		if client, ok := hub.GetClient(subscriber); ok {
+			sendEventToClient(client, eventBack)
+		}
+	}
+}
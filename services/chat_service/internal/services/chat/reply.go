@@ -0,0 +1,94 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+const quoteSnippetLen = 50 // 引用摘要保留的最大字符数
+
+// prepareReply 校验引用和话题参数，并把引用快照写入待保存的消息
+// 引用的消息必须属于同一会话；话题只在群聊中可用，且根消息不能本身是话题回复
+func prepareReply(message *model.Message, replyTo, threadId string) error {
+	if threadId != "" {
+		if message.ReceiveId[0] != 'G' {
+			return errors.New("只有群聊消息可以回复话题")
+		}
+		var root model.Message
+		if res := dao.GormDB.Where("uuid = ?", threadId).First(&root); res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				return errors.New("话题不存在")
+			}
+			return res.Error
+		}
+		if root.ReceiveId != message.ReceiveId || root.ThreadId != "" {
+			return errors.New("话题不属于该群聊")
+		}
+		message.ThreadId = threadId
+	}
+	if replyTo == "" {
+		return nil
+	}
+	var quoted model.Message
+	if res := dao.GormDB.Where("uuid = ?", replyTo).First(&quoted); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return errors.New("引用的消息不存在")
+		}
+		return res.Error
+	}
+	if message.ReceiveId[0] == 'G' {
+		if quoted.ReceiveId != message.ReceiveId {
+			return errors.New("引用的消息不属于该群聊")
+		}
+	} else if !(quoted.SendId == message.SendId && quoted.ReceiveId == message.ReceiveId) &&
+		!(quoted.SendId == message.ReceiveId && quoted.ReceiveId == message.SendId) {
+		return errors.New("引用的消息不属于该会话")
+	}
+	quote := respond.QuoteMessageRespond{
+		Uuid:     quoted.Uuid,
+		SendId:   quoted.SendId,
+		SendName: quoted.SendName,
+		Type:     quoted.Type,
+		Content:  quoteSnippet(quoted.Content),
+		Url:      quoted.Url,
+		FileName: quoted.FileName,
+	}
+	quoteByte, err := json.Marshal(quote)
+	if err != nil {
+		return err
+	}
+	message.ReplyTo = replyTo
+	message.ReplyQuote = string(quoteByte)
+	return nil
+}
+
+// quoteSnippet 按字符截断被引用的内容，避免中文被截成乱码
+func quoteSnippet(content string) string {
+	runes := []rune(content)
+	if len(runes) <= quoteSnippetLen {
+		return content
+	}
+	return string(runes[:quoteSnippetLen]) + "..."
+}
+
+// onThreadReply 话题回复落库后更新根消息的回复数
+// 话题回复不进入群聊主时间线，根消息的回复数变化后直接删除群聊消息缓存
+func onThreadReply(message *model.Message) {
+	if res := dao.GormDB.Model(&model.Message{}).Where("uuid = ?", message.ThreadId).Updates(map[string]interface{}{
+		"reply_cnt":     gorm.Expr("reply_cnt + 1"),
+		"last_reply_at": time.Now(),
+	}); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+	if err := cache.GetGlobalCache().DelKeyIfExists("group_messagelist_" + message.ReceiveId); err != nil {
+		zlog.Error(err.Error())
+	}
+}
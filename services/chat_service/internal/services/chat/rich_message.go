@@ -0,0 +1,194 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/enum/user_info/user_status_enum"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	imageMaxEdge          = 20000 // 图片宽高的最大值
+	locationTitleMaxLen   = 50    // 位置名称最大字符数
+	locationAddressMaxLen = 200   // 位置详细地址最大字符数
+)
+
+// 允许的图片格式
+var allowedImageTypes = map[string]bool{
+	"jpg":  true,
+	"jpeg": true,
+	"png":  true,
+	"gif":  true,
+	"webp": true,
+	"bmp":  true,
+}
+
+// decodeExtra 解析请求中的extra，不允许出现未定义的字段
+func decodeExtra(extra json.RawMessage, v interface{}) error {
+	if len(extra) == 0 {
+		return errors.New("缺少extra")
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(extra)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return errors.New("extra格式不合法: " + err.Error())
+	}
+	return nil
+}
+
+// setExtra 将校验后的结构化内容写入消息
+func setExtra(message *model.Message, v interface{}) error {
+	extraByte, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	message.Extra = string(extraByte)
+	return nil
+}
+
+// staticPath 截取/static/开始的路径，不是本服务的静态资源时返回空
+func staticPath(url string) string {
+	staticIndex := strings.Index(url, "/static/")
+	if staticIndex < 0 {
+		return ""
+	}
+	return url[staticIndex:]
+}
+
+// prepareImage 校验图片消息：原图放在url中，extra中为宽高和缩略图
+func prepareImage(message *model.Message, req *request.ChatMessageRequest) error {
+	fileType := strings.ToLower(strings.TrimPrefix(req.FileType, "."))
+	if !allowedImageTypes[fileType] {
+		return errors.New("不支持的图片格式: " + req.FileType)
+	}
+	url := staticPath(req.Url)
+	if url == "" {
+		return errors.New("图片地址不合法")
+	}
+	var extra respond.ImageExtraRespond
+	if err := decodeExtra(req.Extra, &extra); err != nil {
+		return err
+	}
+	if extra.Width <= 0 || extra.Width > imageMaxEdge || extra.Height <= 0 || extra.Height > imageMaxEdge {
+		return errors.New("图片宽高不合法")
+	}
+	if extra.ThumbnailUrl == "" {
+		// 没有单独的缩略图时直接使用原图
+		extra.ThumbnailUrl = url
+	} else if extra.ThumbnailUrl = staticPath(extra.ThumbnailUrl); extra.ThumbnailUrl == "" {
+		return errors.New("缩略图地址不合法")
+	}
+	message.Url = url
+	message.FileType = fileType
+	return setExtra(message, extra)
+}
+
+// prepareLocation 校验位置消息
+func prepareLocation(message *model.Message, req *request.ChatMessageRequest) error {
+	var extra respond.LocationExtraRespond
+	if err := decodeExtra(req.Extra, &extra); err != nil {
+		return err
+	}
+	if extra.Latitude < -90 || extra.Latitude > 90 || extra.Longitude < -180 || extra.Longitude > 180 {
+		return errors.New("经纬度超出范围")
+	}
+	extra.Title = strings.TrimSpace(extra.Title)
+	if extra.Title == "" || utf8.RuneCountInString(extra.Title) > locationTitleMaxLen {
+		return errors.New("位置名称不合法")
+	}
+	if utf8.RuneCountInString(extra.Address) > locationAddressMaxLen {
+		return errors.New("位置地址过长")
+	}
+	return setExtra(message, extra)
+}
+
+// prepareContactCard 校验名片消息，名称和头像以服务端查询结果为准
+// 分享群聊名片时发送者需要是群成员
+func prepareContactCard(message *model.Message, req *request.ChatMessageRequest) error {
+	var extra respond.ContactCardExtraRespond
+	if err := decodeExtra(req.Extra, &extra); err != nil {
+		return err
+	}
+	if extra.Uuid == "" {
+		return errors.New("名片uuid不能为空")
+	}
+	switch extra.Uuid[0] {
+	case 'U':
+		userClient, err := clients.GetGlobalUserClient()
+		if err != nil {
+			return err
+		}
+		user := userClient.GetUserInfo(extra.Uuid)
+		if user == nil || user.Uuid == "" {
+			return errors.New("名片用户不存在")
+		}
+		if user.Status == user_status_enum.DISABLE {
+			return errors.New("名片用户已被禁用")
+		}
+		extra.Name = user.Nickname
+		extra.Avatar = user.Avatar
+	case 'G':
+		var group model.GroupInfo
+		if res := dao.GormDB.Where("uuid = ?", extra.Uuid).First(&group); res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				return errors.New("名片群聊不存在")
+			}
+			return res.Error
+		}
+		var members []string
+		if err := json.Unmarshal(group.Members, &members); err != nil {
+			return err
+		}
+		isMember := false
+		for _, member := range members {
+			if member == message.SendId {
+				isMember = true
+				break
+			}
+		}
+		if !isMember {
+			return errors.New("只能分享自己所在的群聊")
+		}
+		extra.Name = group.Name
+		extra.Avatar = group.Avatar
+	default:
+		return errors.New("名片uuid不合法")
+	}
+	return setExtra(message, extra)
+}
+
+// prepareSticker 校验表情消息，表情必须属于对应的表情包且表情包未下架
+func prepareSticker(message *model.Message, req *request.ChatMessageRequest) error {
+	var extra respond.StickerExtraRespond
+	if err := decodeExtra(req.Extra, &extra); err != nil {
+		return err
+	}
+	var item model.StickerItem
+	if res := dao.GormDB.Where("uuid = ? AND pack_id = ?", extra.StickerId, extra.PackId).First(&item); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return errors.New("表情不存在")
+		}
+		return res.Error
+	}
+	var pack model.StickerPack
+	if res := dao.GormDB.Where("uuid = ? AND status = 0", item.PackId).First(&pack); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return errors.New("表情包已下架")
+		}
+		return res.Error
+	}
+	extra.Name = item.Name
+	extra.Url = item.Url
+	extra.Width = item.Width
+	extra.Height = item.Height
+	message.Url = item.Url
+	return setExtra(message, extra)
+}
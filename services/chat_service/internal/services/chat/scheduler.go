@@ -0,0 +1,131 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	mykafka "github.com/puoxiu/gogochat/common/kafka"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/message/scheduled_status_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/config"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	scheduleScanInterval = time.Second     // 扫描到期定时消息的间隔
+	scheduleBatchSize    = 100             // 每次扫描最多处理的定时消息数
+	scheduleClaimTimeout = 5 * time.Minute // 领取后超过该时间仍未完成，视为领取的实例已宕机
+)
+
+// IsSchedulableType 判断消息类型是否可以定时发送，通话和合并转发不走正常的发送流程
+func IsSchedulableType(messageType int8) bool {
+	if messageType == message_type_enum.Text || messageType == message_type_enum.File {
+		return true
+	}
+	_, ok := messagePreparers[messageType]
+	return ok
+}
+
+// ValidateMessageRequest 不经过websocket发送的消息（定时消息、机器人消息）提前做内容校验，投递时还会按正常流程再校验一次
+func ValidateMessageRequest(req *request.ChatMessageRequest) error {
+	if !IsSchedulableType(req.Type) {
+		return errors.New("不支持发送该类型的消息")
+	}
+	switch req.Type {
+	case message_type_enum.Text:
+		if req.Content == "" {
+			return errors.New("消息内容不能为空")
+		}
+		return nil
+	case message_type_enum.File:
+		if staticPath(req.Url) == "" {
+			return errors.New("文件地址不合法")
+		}
+		return nil
+	}
+	message := model.Message{SendId: req.SendId, ReceiveId: req.ReceiveId, Type: req.Type}
+	return messagePreparers[req.Type](&message, req)
+}
+
+// StartScheduler 启动定时消息调度器，每个实例都会运行
+// 多个实例通过条件更新领取同一条定时消息，只有一个实例能领取成功，保证只投递一次
+func StartScheduler() {
+	go func() {
+		ticker := time.NewTicker(scheduleScanInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			runDueScheduledMessages()
+		}
+	}()
+}
+
+// runDueScheduledMessages 领取并投递到期的定时消息
+func runDueScheduledMessages() {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("scheduler panic: %v", r))
+		}
+	}()
+	now := time.Now()
+	// 领取后长时间没有完成的不再重试，实例可能在投递之后、更新状态之前宕机，重试会导致重复发送
+	if res := dao.GormDB.Model(&model.ScheduledMessage{}).
+		Where("status = ? AND claimed_at < ?", scheduled_status_enum.Sending, now.Add(-scheduleClaimTimeout)).
+		Update("status", scheduled_status_enum.Failed); res.Error != nil {
+		zlog.Error(res.Error.Error())
+	}
+	var dueList []model.ScheduledMessage
+	if res := dao.GormDB.Select("id").Where("status = ? AND send_at <= ?", scheduled_status_enum.Pending, now).
+		Order("send_at ASC").Limit(scheduleBatchSize).Find(&dueList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return
+	}
+	for _, due := range dueList {
+		res := dao.GormDB.Model(&model.ScheduledMessage{}).
+			Where("id = ? AND status = ? AND send_at <= ?", due.Id, scheduled_status_enum.Pending, now).
+			Updates(map[string]interface{}{
+				"status":     scheduled_status_enum.Sending,
+				"claimed_at": now,
+			})
+		if res.Error != nil {
+			zlog.Error(res.Error.Error())
+			continue
+		}
+		if res.RowsAffected == 0 {
+			// 已被其他实例领取，或者在此期间被修改、取消
+			continue
+		}
+		// 领取成功后重新读取，保证投递的是最后一次编辑的内容
+		var scheduled model.ScheduledMessage
+		if res := dao.GormDB.Where("id = ?", due.Id).First(&scheduled); res.Error != nil {
+			zlog.Error(res.Error.Error())
+			continue
+		}
+		status := scheduled_status_enum.Sent
+		if err := injectMessage(chatMessageKey(scheduled.OwnerId, scheduled.ReceiveId), []byte(scheduled.Payload)); err != nil {
+			zlog.Error(fmt.Sprintf("投递定时消息%s失败: %s", scheduled.Uuid, err.Error()))
+			status = scheduled_status_enum.Failed
+		}
+		if res := dao.GormDB.Model(&model.ScheduledMessage{}).
+			Where("id = ? AND status = ?", scheduled.Id, scheduled_status_enum.Sending).
+			Update("status", status); res.Error != nil {
+			zlog.Error(res.Error.Error())
+		}
+	}
+}
+
+// injectMessage 把消息请求投递到正常的消息通道，与websocket上收到的消息走同样的处理流程
+func injectMessage(key, jsonMessage []byte) error {
+	if config.AppConfig.KafkaConfig.MessageMode == "channel" {
+		ChatServer.SendMessageToTransmit(jsonMessage)
+		return nil
+	}
+	return mykafka.KafkaService.ChatWriter.WriteMessages(ctx, kafka.Message{
+		Key:   key,
+		Value: jsonMessage,
+	})
+}
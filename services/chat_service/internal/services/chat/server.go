@@ -28,6 +28,8 @@ const (
 	MsgStatusSuccess      = 0  // 发送成功
 	MsgStatusServerError  = -1 // 服务端错误
 	MsgStatusNotFriend    = -2 // 检查好友关系 可能被删、拉黑等
+	MsgStatusBadRequest   = -3 // 消息不合法 如引用的消息不存在
+	MsgStatusMuted        = -4 // 在群聊中被禁言
 )
 
 type Server struct {
@@ -67,6 +69,14 @@ func normalizePath(path string) string {
 	return path[staticIndex:]
 }
 
+// onTextMessageAccepted 文本消息落库并通过校验后，生成链接预览、机器人事件和AI助手回复
+// 未通过好友关系校验的消息不会送达，也不能触发机器人和AI助手
+func onTextMessageAccepted(message *model.Message) {
+	enqueueLinkPreview(message)
+	notifyBots(message)
+	enqueueAssistantReply(message)
+}
+
 // validateMessage 在发送之前 进行检验 准备工作
 func (s *Server) validateMessage(message *model.Message) bool {
 		// 判断是否是正常好友关系
@@ -125,6 +135,7 @@ func (s *Server) Start() {
 				s.mutex.Lock()
 				s.Clients[client.Uuid] = client
 				s.mutex.Unlock()
+				onClientLogin(client.Uuid)
 				zlog.Debug(fmt.Sprintf("欢迎来到gogo聊天服务器,亲爱的用户%s\n", client.Uuid))
 				err := client.Conn.WriteMessage(websocket.TextMessage, []byte("欢迎来到gogo聊天服务器"))
 				if err != nil {
@@ -135,8 +146,14 @@ func (s *Server) Start() {
 		case client := <-s.Logout:
 			{
 				s.mutex.Lock()
+				// 同一用户重新登录后，旧连接的退出不影响在线状态
+				current, ok := s.Clients[client.Uuid]
+				isCurrent := ok && current == client
 				delete(s.Clients, client.Uuid)
 				s.mutex.Unlock()
+				if isCurrent {
+					onClientLogout(client.Uuid)
+				}
 				zlog.Info(fmt.Sprintf("用户%s退出登录\n", client.Uuid))
 				if err := client.Conn.WriteMessage(websocket.TextMessage, []byte("已退出登录")); err != nil {
 					zlog.Error(err.Error())
@@ -150,6 +167,15 @@ func (s *Server) Start() {
 				if err := json.Unmarshal(data, &chatMessageReq); err != nil {
 					zlog.Error(err.Error())
 				}
+				// 禁言检查和斜杠命令在落库前执行
+				if interceptMessage(&chatMessageReq) {
+					continue
+				}
+				// 语音、图片、位置、名片、表情等消息走通用分发流程
+				if prepare, ok := messagePreparers[chatMessageReq.Type]; ok {
+					dispatchMessage(&chatMessageReq, prepare, s.validateMessage)
+					continue
+				}
 				if chatMessageReq.Type == message_type_enum.Text {
 					// 存message
 					message := model.Message{
@@ -172,35 +198,44 @@ func (s *Server) Start() {
 					// 对SendAvatar去除前面/static之前的所有内容，防止ip前缀引入 避免后续服务部署 IP 变更导致头像加载失败。
 					// 例如：https://127.0.0.1:8000/static/xxx 转为 /static/xxx
 					message.SendAvatar = normalizePath(message.SendAvatar)
+					if err := prepareReply(&message, chatMessageReq.ReplyTo, chatMessageReq.ThreadId); err != nil {
+						zlog.Warn("引用消息校验失败: " + err.Error())
+						if sendClient, ok := s.GetClient(message.SendId); ok {
+							sendMessageToClient(sendClient, &message, MsgStatusBadRequest)
+						}
+						continue
+					}
+					if err := prepareMentions(&message, chatMessageReq.Mentions, chatMessageReq.MentionAll); err != nil {
+						zlog.Warn("@成员校验失败: " + err.Error())
+						if sendClient, ok := s.GetClient(message.SendId); ok {
+							sendMessageToClient(sendClient, &message, MsgStatusBadRequest)
+						}
+						continue
+					}
+					if !claimClientMsgId(&message, chatMessageReq.ClientMsgId) {
+						continue
+					}
+					ApplyDisappearTimer(&message)
+					saved := true
 					if res := dao.GormDB.Create(&message); res.Error != nil {
 						zlog.Error(res.Error.Error())
+						releaseClientMsgId(&message)
+						saved = false
 					}
 					if message.ReceiveId[0] == 'U' {
 						if !s.validateMessage(&message) {
 							continue
 						}
-
-						messageRsp := respond.GetMessageListRespond{
-							SendId:     message.SendId,
-							SendName:   message.SendName,
-							SendAvatar: message.SendAvatar,
-							ReceiveId:  message.ReceiveId,
-							Type:       message.Type,
-							Content:    message.Content,
-							Url:        message.Url,
-							FileSize:   message.FileSize,
-							FileName:   message.FileName,
-							FileType:   message.FileType,
-							CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
-						}
-						s.mutex.Lock()
-						if receiveClient, ok := s.Clients[message.ReceiveId]; ok {
-							sendMessageToClient(receiveClient, &message, MsgStatusSuccess)
+						if saved {
+							onTextMessageAccepted(&message)
+							clearDraftOnSend(&message, &chatMessageReq)
 						}
-						if sendClient, ok := s.Clients[message.SendId]; ok {
-							sendMessageToClient(sendClient, &message, MsgStatusSuccess)
+						enqueueLastMessage(&message)
+
+						messageRsp := respond.NewGetMessageListRespond(&message)
+						if messageBack := newMessageBack(&message); messageBack != nil {
+							deliverUserMessage(s, message.ReceiveId, message.SendId, messageBack)
 						}
-						s.mutex.Unlock()
 
 						if rspString, err := cache.GetGlobalCache().GetKeyNilIsErr("message_list_" + message.SendId + "_" + message.ReceiveId); err == nil {
 							var rsp []respond.GetMessageListRespond
@@ -222,19 +257,12 @@ func (s *Server) Start() {
 							}
 						}
 					} else if message.ReceiveId[0] == 'G' {
-						messageRsp := respond.GetGroupMessageListRespond{
-							SendId:     message.SendId,
-							SendName:   message.SendName,
-							SendAvatar: chatMessageReq.SendAvatar,
-							ReceiveId:  message.ReceiveId,
-							Type:       message.Type,
-							Content:    message.Content,
-							Url:        message.Url,
-							FileSize:   message.FileSize,
-							FileName:   message.FileName,
-							FileType:   message.FileType,
-							CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
+						if saved {
+							onTextMessageAccepted(&message)
+							clearDraftOnSend(&message, &chatMessageReq)
 						}
+						enqueueLastMessage(&message)
+						messageRsp := respond.NewGetGroupMessageListRespond(&message)
 						jsonMessage, err := json.Marshal(messageRsp)
 						if err != nil {
 							zlog.Error(err.Error())
@@ -252,20 +280,21 @@ func (s *Server) Start() {
 						if err = json.Unmarshal(group.Members, &members); err != nil {
 							zlog.Error(err.Error())
 						}
-						s.mutex.Lock()
-						for _, member := range members {
-							// 遍历发送 并且排除发送者
-							if member != message.SendId {
-								if receiveClient, ok := s.Clients[member]; ok {
-									receiveClient.SendBack <- messageBack
-								}
-							} else {
-								// 发送给自己
-								sendClient := s.Clients[message.SendId]
-								sendClient.SendBack <- messageBack
+						if saved {
+							recordMentions(&message, members)
+						}
+						mentionedBack := newMentionedBack(messageRsp)
+						deliverGroupMessage(s, message.ReceiveId, message.SendId, members, messageBack, mentionedBack, func(member string) bool {
+							return isMentioned(&messageRsp, member)
+						})
+
+						// 话题回复不追加到群聊主时间线的缓存中
+						if message.ThreadId != "" {
+							if saved {
+								onThreadReply(&message)
 							}
+							continue
 						}
-						s.mutex.Unlock()
 
 						// redis
 						var rspString string
@@ -309,38 +338,39 @@ func (s *Server) Start() {
 					}
 					// 对SendAvatar去除前面/static之前的所有内容，防止ip前缀引入
 					message.SendAvatar = normalizePath(message.SendAvatar)
+					if err := prepareReply(&message, chatMessageReq.ReplyTo, chatMessageReq.ThreadId); err != nil {
+						zlog.Warn("引用消息校验失败: " + err.Error())
+						if sendClient, ok := s.GetClient(message.SendId); ok {
+							sendMessageToClient(sendClient, &message, MsgStatusBadRequest)
+						}
+						continue
+					}
+					if !claimClientMsgId(&message, chatMessageReq.ClientMsgId) {
+						continue
+					}
+					ApplyDisappearTimer(&message)
+					saved := true
 					if res := dao.GormDB.Create(&message); res.Error != nil {
 						zlog.Error(res.Error.Error())
+						releaseClientMsgId(&message)
+						saved = false
 					}
 					if message.ReceiveId[0] == 'U' {
 						if !s.validateMessage(&message) {
-							fmt.Println("验证不通过")
+							zlog.Info(fmt.Sprintf("文件消息%s未通过好友关系校验", message.Uuid))
 							continue
 						}
-						fmt.Println("验证通过")
-
-						messageRsp := respond.GetMessageListRespond{
-							SendId:     message.SendId,
-							SendName:   message.SendName,
-							SendAvatar: chatMessageReq.SendAvatar,
-							ReceiveId:  message.ReceiveId,
-							Type:       message.Type,
-							Content:    message.Content,
-							Url:        message.Url,
-							FileSize:   message.FileSize,
-							FileName:   message.FileName,
-							FileType:   message.FileType,
-							CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
+						if saved {
+							notifyBots(&message)
+							clearDraftOnSend(&message, &chatMessageReq)
 						}
+						enqueueLastMessage(&message)
 
-						s.mutex.Lock()
-						if receiveClient, ok := s.Clients[message.ReceiveId]; ok {
-							sendMessageToClient(receiveClient, &message, MsgStatusSuccess)
-						}
-						if sendClient, ok := s.Clients[message.SendId]; ok {
-							sendMessageToClient(sendClient, &message, MsgStatusSuccess)
+						messageRsp := respond.NewGetMessageListRespond(&message)
+
+						if messageBack := newMessageBack(&message); messageBack != nil {
+							deliverUserMessage(s, message.ReceiveId, message.SendId, messageBack)
 						}
-						s.mutex.Unlock()
 
 						if rspString, err := cache.GetGlobalCache().GetKeyNilIsErr("message_list_" + message.SendId + "_" + message.ReceiveId); err == nil {
 							var rsp []respond.GetMessageListRespond
@@ -361,19 +391,12 @@ func (s *Server) Start() {
 							}
 						}
 					} else {
-						messageRsp := respond.GetGroupMessageListRespond{
-							SendId:     message.SendId,
-							SendName:   message.SendName,
-							SendAvatar: chatMessageReq.SendAvatar,
-							ReceiveId:  message.ReceiveId,
-							Type:       message.Type,
-							Content:    message.Content,
-							Url:        message.Url,
-							FileSize:   message.FileSize,
-							FileName:   message.FileName,
-							FileType:   message.FileType,
-							CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
+						if saved {
+							notifyBots(&message)
+							clearDraftOnSend(&message, &chatMessageReq)
 						}
+						enqueueLastMessage(&message)
+						messageRsp := respond.NewGetGroupMessageListRespond(&message)
 						jsonMessage, err := json.Marshal(messageRsp)
 						if err != nil {
 							zlog.Error(err.Error())
@@ -391,18 +414,15 @@ func (s *Server) Start() {
 						if err := json.Unmarshal(group.Members, &members); err != nil {
 							zlog.Error(err.Error())
 						}
-						s.mutex.Lock()
-						for _, member := range members {
-							if member != message.SendId {
-								if receiveClient, ok := s.Clients[member]; ok {
-									receiveClient.SendBack <- messageBack
-								}
-							} else {
-								sendClient := s.Clients[message.SendId]
-								sendClient.SendBack <- messageBack
+						deliverGroupMessage(s, message.ReceiveId, message.SendId, members, messageBack, nil, nil)
+
+						// 话题回复不追加到群聊主时间线的缓存中
+						if message.ThreadId != "" {
+							if saved {
+								onThreadReply(&message)
 							}
+							continue
 						}
-						s.mutex.Unlock()
 
 						// redis
 						var rspString string
@@ -426,7 +446,6 @@ func (s *Server) Start() {
 							}
 						}
 					}
-					fmt.Println("到这里")
 				} else if chatMessageReq.Type == message_type_enum.AudioOrVideo {
 					var avData request.AVData	//  音视频信令结构体（含通话类型、通话ID等）
 					if err := json.Unmarshal([]byte(chatMessageReq.AVdata), &avData); err != nil {
@@ -453,6 +472,7 @@ func (s *Server) Start() {
 						// 存message
 						// 对SendAvatar去除前面/static之前的所有内容，防止ip前缀引入
 						message.SendAvatar = normalizePath(message.SendAvatar)
+						ApplyDisappearTimer(&message)
 						if res := dao.GormDB.Create(&message); res.Error != nil {
 							zlog.Error(res.Error.Error())
 						}
@@ -491,7 +511,7 @@ func (s *Server) Start() {
 						if receiveClient, ok := s.Clients[message.ReceiveId]; ok {
 							//messageBack.Message = jsonMessage
 							//messageBack.Uuid = message.Uuid
-							receiveClient.SendBack <- messageBack // 向client.Send发送
+							deliverMessageBack(receiveClient, messageBack) // 向client.Send发送
 						}
 						// 通话这不能回显，发回去的话就会出现两个start_call。
 						//sendClient := s.Clients[message.SendId]
@@ -508,18 +528,7 @@ func (s *Server) Start() {
 
 func sendMessageToClient(client *Client, message *model.Message, code int8) {
     // 基础响应体
-    messageRsp := respond.GetMessageListRespond{
-        SendId:     message.SendId,
-        SendName:   message.SendName,
-        SendAvatar: message.SendAvatar,
-        ReceiveId:  message.ReceiveId,
-        Type:       message.Type,
-        Url:        message.Url,
-        FileSize:   message.FileSize,
-        FileName:   message.FileName,
-        FileType:   message.FileType,
-        CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"), // 补充时间
-    }
+    messageRsp := respond.NewGetMessageListRespond(message)
 
     // 根据状态码设置提示内容
     switch code {
@@ -527,6 +536,10 @@ func sendMessageToClient(client *Client, message *model.Message, code int8) {
         messageRsp.Content = "系统消息：消息发送失败（服务端错误）"
     case MsgStatusNotFriend:
         messageRsp.Content = "系统消息：消息发送失败，请检查好友关系"
+    case MsgStatusBadRequest:
+        messageRsp.Content = "系统消息：消息发送失败（消息不合法）"
+    case MsgStatusMuted:
+        messageRsp.Content = "系统消息：消息发送失败，你已被禁言"
     default:
         messageRsp.Content = message.Content // 正常消息用原内容
     }
@@ -543,11 +556,10 @@ func sendMessageToClient(client *Client, message *model.Message, code int8) {
     }
 
     // 非阻塞发送，避免通道阻塞导致的问题
-    select {
-    case client.SendBack <- messageBack:
+    if client.trySendBack(messageBack) {
         zlog.Info("消息已发送到客户端: " + client.Uuid)
-    default:
-        zlog.Warn("客户端通道已满，消息发送失败: " + client.Uuid)
+    } else {
+        zlog.Warn("客户端通道已满或已断开，消息发送失败: " + client.Uuid)
     }
 }
 
@@ -0,0 +1,206 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/unread"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	EventUnreadUpdated = "unread_updated" // 会话未读数变化
+	EventSessionRead   = "session_read"   // 客户端打开会话或读到最新消息，清零未读数
+)
+
+const (
+	unreadFlushInterval = 30 * time.Second // 未读数写回数据库的间隔
+	unreadFlushBatch    = 100              // 每批写回的用户数
+)
+
+// StartUnreadFlusher 定期把redis中变化过的未读数写回数据库，每个实例都会运行
+func StartUnreadFlusher() {
+	go func() {
+		ticker := time.NewTicker(unreadFlushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			flushUnread()
+		}
+	}()
+}
+
+// LoadUnreadCounters 从数据库读取用户持久化的未读数
+func LoadUnreadCounters(userId string) (map[string]int, error) {
+	var counterList []model.UnreadCounter
+	if res := dao.GormDB.Where("user_id = ? AND count > 0", userId).Find(&counterList); res.Error != nil {
+		return nil, res.Error
+	}
+	counts := make(map[string]int, len(counterList))
+	for _, counter := range counterList {
+		counts[counter.TargetId] = counter.Count
+	}
+	return counts, nil
+}
+
+// incrUnread 消息投递后为每个接收者的会话未读数加一，发送者自己不计入
+// 免打扰的会话照常计数，下发的消息和推送的事件上都标记静默
+func incrUnread(message *model.Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("incr unread panic: %v", r))
+		}
+	}()
+	if message.Type == message_type_enum.System {
+		return
+	}
+	recipients := []string{message.ReceiveId}
+	targetId := message.SendId
+	var messageRsp *respond.GetGroupMessageListRespond
+	if message.ReceiveId[0] == 'G' {
+		members, err := groupMembers(message.ReceiveId)
+		if err != nil {
+			zlog.Error(err.Error())
+			return
+		}
+		recipients = members
+		targetId = message.ReceiveId
+		groupRsp := respond.NewGetGroupMessageListRespond(message)
+		messageRsp = &groupRsp
+	}
+	for _, recipient := range recipients {
+		if recipient == message.SendId {
+			continue
+		}
+		n, err := unread.Incr(recipient, targetId, LoadUnreadCounters)
+		if err != nil {
+			zlog.Error(err.Error())
+			continue
+		}
+		mentioned := messageRsp != nil && isMentioned(messageRsp, recipient)
+		pushUnread(recipient, targetId, n, isSilenced(recipient, targetId, mentioned))
+	}
+}
+
+// ReadSession 清零用户在会话中的未读数，并通知用户在线的设备
+func ReadSession(ownerId, targetId string) error {
+	if err := unread.Reset(ownerId, targetId, LoadUnreadCounters); err != nil {
+		return err
+	}
+	pushUnread(ownerId, targetId, 0, true)
+	return nil
+}
+
+// UnreadCounts 查询用户所有会话的未读数
+func UnreadCounts(ownerId string) (map[string]int, error) {
+	return unread.Counts(ownerId, LoadUnreadCounters)
+}
+
+// pushUnread 把会话最新的未读数和总数推送给用户，silent为true时客户端不提醒
+func pushUnread(userId, targetId string, n int, silent bool) {
+	client, ok := currentHub().GetClient(userId)
+	if !ok {
+		return
+	}
+	counts, err := UnreadCounts(userId)
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	jsonMessage, err := json.Marshal(respond.UnreadEventRespond{
+		Event:    EventUnreadUpdated,
+		TargetId: targetId,
+		Unread:   n,
+		Total:    unread.Total(counts),
+		Silent:   silent,
+	})
+	if err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	sendEventToClient(client, &MessageBack{Message: jsonMessage})
+}
+
+// handleSessionReadEvent 处理websocket上的已读事件，receive_id为会话对方
+func handleSessionReadEvent(c *Client, req *request.WsEventRequest) {
+	if req.ReceiveId == "" {
+		return
+	}
+	if err := ReadSession(c.Uuid, req.ReceiveId); err != nil {
+		zlog.Error(err.Error())
+	}
+}
+
+// flushUnread 取出变化过的用户，把redis中的未读数写回数据库，清零的会话删除记录
+func flushUnread() {
+	defer func() {
+		if r := recover(); r != nil {
+			zlog.Error(fmt.Sprintf("unread flush panic: %v", r))
+		}
+	}()
+	for {
+		userIds, err := unread.PopDirty(unreadFlushBatch)
+		if err != nil {
+			zlog.Error(err.Error())
+			return
+		}
+		if len(userIds) == 0 {
+			return
+		}
+		for _, userId := range userIds {
+			if err := flushUserUnread(userId); err != nil {
+				zlog.Error(fmt.Sprintf("写回用户%s的未读数失败: %s", userId, err.Error()))
+				// 放回待写回集合，下次重试
+				if err := unread.MarkDirty(userId); err != nil {
+					zlog.Error(err.Error())
+				}
+			}
+		}
+		if len(userIds) < unreadFlushBatch {
+			return
+		}
+	}
+}
+
+// flushUserUnread 写回单个用户的未读数
+func flushUserUnread(userId string) error {
+	counts, ok, err := unread.Snapshot(userId)
+	if err != nil || !ok {
+		return err
+	}
+	now := time.Now()
+	var counterList []model.UnreadCounter
+	var readTargets []string
+	for targetId, n := range counts {
+		if n <= 0 {
+			readTargets = append(readTargets, targetId)
+			continue
+		}
+		counterList = append(counterList, model.UnreadCounter{
+			UserId:    userId,
+			TargetId:  targetId,
+			Count:     n,
+			UpdatedAt: now,
+		})
+	}
+	return dao.GormDB.Transaction(func(tx *gorm.DB) error {
+		if len(readTargets) > 0 {
+			if err := tx.Where("user_id = ? AND target_id IN ?", userId, readTargets).Delete(&model.UnreadCounter{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(counterList) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"count", "updated_at"}),
+		}).Create(&counterList).Error
+	})
+}
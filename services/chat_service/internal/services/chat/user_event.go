@@ -0,0 +1,17 @@
+package chat
+
+import "encoding/json"
+
+// PushUserEvent 把其他服务产生的事件推送给用户连在本实例上的连接，返回用户是否连在本实例上
+// 用户的设备可能连在其他实例上，调用方需要发给所有实例；事件必须是合法的json，客户端按event字段区分
+func PushUserEvent(userId string, event []byte) bool {
+	if !json.Valid(event) {
+		return false
+	}
+	client, ok := currentHub().GetClient(userId)
+	if !ok {
+		return false
+	}
+	sendEventToClient(client, &MessageBack{Message: event})
+	return true
+}
@@ -0,0 +1,82 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+const voiceUrlPrefix = "/static/voices/" // 语音文件的访问路径前缀
+
+// 允许的语音格式，覆盖主流浏览器录音和移动端录音的输出
+var allowedVoiceTypes = map[string]bool{
+	"mp3":  true,
+	"m4a":  true,
+	"aac":  true,
+	"amr":  true,
+	"wav":  true,
+	"ogg":  true,
+	"opus": true,
+	"webm": true,
+}
+
+// IsAllowedVoiceType 判断语音格式是否允许，fileType可以带前导点
+func IsAllowedVoiceType(fileType string) bool {
+	return allowedVoiceTypes[strings.ToLower(strings.TrimPrefix(fileType, "."))]
+}
+
+// prepareVoice 校验语音消息的文件和元数据，并把时长、波形写入消息的extra
+func prepareVoice(message *model.Message, req *request.ChatMessageRequest) error {
+	if !IsAllowedVoiceType(req.FileType) {
+		return errors.New("不支持的语音格式: " + req.FileType)
+	}
+	staticIndex := strings.Index(req.Url, voiceUrlPrefix)
+	if staticIndex < 0 {
+		return errors.New("语音地址不合法")
+	}
+	duration, waveform, err := normalizeVoiceMeta(req.Duration, req.Waveform)
+	if err != nil {
+		return err
+	}
+	extraByte, err := json.Marshal(respond.VoiceExtraRespond{
+		Duration: duration,
+		Waveform: waveform,
+	})
+	if err != nil {
+		return err
+	}
+	// 和头像一样只保存/static之后的部分，避免服务地址变更后无法访问
+	message.Url = req.Url[staticIndex:]
+	message.FileType = strings.ToLower(strings.TrimPrefix(req.FileType, "."))
+	message.Extra = string(extraByte)
+	return nil
+}
+
+// normalizeVoiceMeta 校验客户端上报的语音时长和波形，时长和波形都不可信，只用于展示
+// 时长超过上限、波形的采样数和取值范围超出时拒绝
+func normalizeVoiceMeta(duration int, waveform []int) (int, []int, error) {
+	if duration <= 0 {
+		return 0, nil, errors.New("语音时长不合法")
+	}
+	if duration > constants.VOICE_MAX_DURATION {
+		return 0, nil, fmt.Errorf("语音时长不能超过%d秒", constants.VOICE_MAX_DURATION)
+	}
+	if len(waveform) > constants.VOICE_WAVEFORM_MAX_LEN {
+		return 0, nil, fmt.Errorf("语音波形最多%d个采样", constants.VOICE_WAVEFORM_MAX_LEN)
+	}
+	for _, sample := range waveform {
+		if sample < 0 || sample > 255 {
+			return 0, nil, errors.New("语音波形采样需在0-255之间")
+		}
+	}
+	if waveform == nil {
+		waveform = []int{}
+	}
+	return duration, waveform, nil
+}
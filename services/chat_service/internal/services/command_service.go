@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	botCommandUsageMaxLen       = 100 // 命令用法最大字符数
+	botCommandDescriptionMaxLen = 200 // 命令说明最大字符数
+	botCommandMaxPerGroup       = 20  // 每个机器人在一个群聊中最多注册的命令数
+)
+
+type commandService struct {
+}
+
+var CommandService = new(commandService)
+
+// GetCommandList 获取群聊中可用的命令，用于客户端的命令补全，只有群成员可以查看
+func (c *commandService) GetCommandList(req request.GetCommandListRequest) (string, []respond.CommandRespond, int) {
+	group, err := loadGroup(req.GroupId, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "群聊不存在", nil, -2
+		}
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if !isGroupMember(group, req.OwnerId) {
+		return "你不是该群成员", nil, -2
+	}
+	rsp, err := chat.GroupCommandList(req.GroupId)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "获取命令列表成功", rsp, 0
+}
+
+// RegisterBotCommand 机器人在所在的群聊中注册命令，命令名不能与内置命令或其他机器人的命令重复
+func (c *commandService) RegisterBotCommand(token string, req request.RegisterBotCommandRequest) (string, int) {
+	bot, msg, ret := BotService.Authenticate(token)
+	if ret != 0 {
+		return msg, ret
+	}
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.Name), "/"))
+	if !chat.IsValidCommandName(name) {
+		return "命令名只能包含小写字母、数字和下划线，最长32个字符", -2
+	}
+	if chat.IsBuiltinCommand(name) {
+		return "不能注册与内置命令同名的命令", -2
+	}
+	usage := strings.TrimSpace(req.Usage)
+	if usage == "" {
+		usage = "/" + name
+	}
+	description := strings.TrimSpace(req.Description)
+	if utf8.RuneCountInString(usage) > botCommandUsageMaxLen || utf8.RuneCountInString(description) > botCommandDescriptionMaxLen {
+		return "命令用法或说明过长", -2
+	}
+	group, err := loadGroup(req.GroupId, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "群聊不存在", -2
+		}
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if !isGroupMember(group, bot.Uuid) {
+		return "机器人不在该群聊中", -2
+	}
+	var existing model.BotCommand
+	res := dao.GormDB.Where("group_id = ? AND name = ?", req.GroupId, name).First(&existing)
+	if res.Error == nil {
+		if existing.BotId != bot.Uuid {
+			return "该命令已被其他机器人注册", -2
+		}
+		if res := dao.GormDB.Model(&existing).Updates(map[string]interface{}{
+			"usage":       usage,
+			"description": description,
+			"updated_at":  time.Now(),
+		}); res.Error != nil {
+			zlog.Error(res.Error.Error())
+			return constants.SYSTEM_ERROR, -1
+		}
+		return "命令更新成功", 0
+	}
+	if !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	var count int64
+	if res := dao.GormDB.Model(&model.BotCommand{}).Where("group_id = ? AND bot_id = ?", req.GroupId, bot.Uuid).Count(&count); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if count >= botCommandMaxPerGroup {
+		return "在该群聊中注册的命令数已达上限", -2
+	}
+	now := time.Now()
+	// 并发注册同名命令时由唯一索引兜底
+	res = dao.GormDB.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.BotCommand{
+		GroupId:     req.GroupId,
+		Name:        name,
+		BotId:       bot.Uuid,
+		Usage:       usage,
+		Description: description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res.RowsAffected == 0 {
+		return "该命令已被注册", -2
+	}
+	return "命令注册成功", 0
+}
+
+// UnregisterBotCommand 机器人注销自己注册的命令
+func (c *commandService) UnregisterBotCommand(token string, req request.UnregisterBotCommandRequest) (string, int) {
+	bot, msg, ret := BotService.Authenticate(token)
+	if ret != 0 {
+		return msg, ret
+	}
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.Name), "/"))
+	res := dao.GormDB.Where("group_id = ? AND name = ? AND bot_id = ?", req.GroupId, name, bot.Uuid).Delete(&model.BotCommand{})
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res.RowsAffected == 0 {
+		return "命令不存在", -2
+	}
+	return "命令注销成功", 0
+}
+
+// GetBotCommandList 获取机器人在群聊中注册的命令
+func (c *commandService) GetBotCommandList(token string, req request.GetBotCommandListRequest) (string, []respond.CommandRespond, int) {
+	bot, msg, ret := BotService.Authenticate(token)
+	if ret != 0 {
+		return msg, nil, ret
+	}
+	var commandList []model.BotCommand
+	if res := dao.GormDB.Where("group_id = ? AND bot_id = ?", req.GroupId, bot.Uuid).Order("name ASC").Find(&commandList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rsp := make([]respond.CommandRespond, 0, len(commandList))
+	for _, command := range commandList {
+		rsp = append(rsp, respond.CommandRespond{
+			Name:        command.Name,
+			Usage:       command.Usage,
+			Description: command.Description,
+			BotId:       command.BotId,
+		})
+	}
+	return "获取命令列表成功", rsp, 0
+}
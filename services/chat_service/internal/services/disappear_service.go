@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/conversation"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type disappearService struct {
+}
+
+var DisappearService = new(disappearService)
+
+// SetDisappearTimer 修改会话的阅后即焚时长，只影响之后发送的消息
+// 群聊需要群主或管理员，单聊双方都可以修改；修改后在会话中插入一条系统消息
+func (d *disappearService) SetDisappearTimer(ownerId, targetId string, duration int) (string, int) {
+	label, ok := chat.DisappearDurations[duration]
+	if !ok {
+		return "不支持的阅后即焚时长", -2
+	}
+	if targetId != "" && targetId[0] == 'G' {
+		group, err := loadGroup(targetId, nil)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "群聊不存在", -2
+			}
+			zlog.Error(err.Error())
+			return constants.SYSTEM_ERROR, -1
+		}
+		if !isGroupMember(group, ownerId) || !chat.IsGroupManager(group, ownerId) {
+			return "只有群主或管理员可以设置阅后即焚", -2
+		}
+	}
+	sessionId, msg := checkSendTarget(ownerId, targetId)
+	if msg != "" {
+		if msg == constants.SYSTEM_ERROR {
+			return msg, -1
+		}
+		return msg, -2
+	}
+	oldDuration, err := chat.GetDisappearDuration(ownerId, targetId)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if oldDuration == duration {
+		return "设置成功", 0
+	}
+	setting := model.DisappearSetting{
+		ConversationId: conversation.Id(ownerId, targetId),
+		Duration:       duration,
+		UpdatedBy:      ownerId,
+		UpdatedAt:      time.Now(),
+	}
+	if res := dao.GormDB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "conversation_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"duration", "updated_by", "updated_at"}),
+	}).Create(&setting); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+
+	userClient, err := clients.GetGlobalUserClient()
+	if err != nil {
+		zlog.Error("获取用户客户端失败: " + err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	owner := userClient.GetUserInfo(ownerId)
+	if owner == nil || owner.Uuid == "" {
+		zlog.Error("获取用户信息失败: " + ownerId)
+		return constants.SYSTEM_ERROR, -1
+	}
+	message := newServerMessage(owner.Uuid, owner.Nickname, owner.Avatar, targetId, sessionId)
+	message.Type = message_type_enum.System
+	if duration == 0 {
+		message.Content = owner.Nickname + "关闭了阅后即焚"
+	} else {
+		message.Content = fmt.Sprintf("%s开启了阅后即焚，新消息将在%s后自动删除", owner.Nickname, label)
+	}
+	extraByte, err := json.Marshal(respond.DisappearTimerExtraRespond{Event: chat.EventDisappearTimer, Duration: duration})
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	message.Extra = string(extraByte)
+	if res := dao.GormDB.Create(&message); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	chat.DeliverMessage(&message)
+	return "设置成功", 0
+}
+
+// GetDisappearTimer 查询会话的阅后即焚设置
+func (d *disappearService) GetDisappearTimer(ownerId, targetId string) (string, *respond.DisappearTimerRespond, int) {
+	if targetId == "" {
+		return "会话不存在", nil, -2
+	}
+	if targetId[0] == 'G' {
+		group, err := loadGroup(targetId, nil)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "群聊不存在", nil, -2
+			}
+			zlog.Error(err.Error())
+			return constants.SYSTEM_ERROR, nil, -1
+		}
+		if !isGroupMember(group, ownerId) {
+			return "你不是该群成员", nil, -2
+		}
+	}
+	var setting model.DisappearSetting
+	if res := dao.GormDB.Where("conversation_id = ?", conversation.Id(ownerId, targetId)).First(&setting); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "获取成功", &respond.DisappearTimerRespond{}, 0
+		}
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "获取成功", &respond.DisappearTimerRespond{
+		Duration:  setting.Duration,
+		UpdatedBy: setting.UpdatedBy,
+		UpdatedAt: setting.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}, 0
+}
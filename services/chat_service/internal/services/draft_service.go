@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+	"gorm.io/gorm/clause"
+)
+
+type draftService struct {
+}
+
+var DraftService = new(draftService)
+
+// SaveDraft 保存会话草稿并同步到用户的其他在线设备，内容为空时删除草稿
+func (d *draftService) SaveDraft(ownerId, targetId, content string) (string, int) {
+	if targetId == "" || (targetId[0] != 'U' && targetId[0] != 'G') || targetId == ownerId {
+		return "会话不存在", -2
+	}
+	if utf8.RuneCountInString(content) > constants.DRAFT_MAX_LEN {
+		return fmt.Sprintf("草稿最多%d个字符", constants.DRAFT_MAX_LEN), -2
+	}
+	now := time.Now()
+	if content == "" {
+		if res := dao.GormDB.Where("user_id = ? AND target_id = ?", ownerId, targetId).Delete(&model.Draft{}); res.Error != nil {
+			zlog.Error(res.Error.Error())
+			return constants.SYSTEM_ERROR, -1
+		}
+	} else {
+		draft := model.Draft{
+			UserId:    ownerId,
+			TargetId:  targetId,
+			Content:   content,
+			UpdatedAt: now,
+		}
+		if res := dao.GormDB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "target_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"content", "updated_at"}),
+		}).Create(&draft); res.Error != nil {
+			zlog.Error(res.Error.Error())
+			return constants.SYSTEM_ERROR, -1
+		}
+	}
+	chat.PushDraft(ownerId, respond.DraftRespond{
+		TargetId:  targetId,
+		Content:   content,
+		UpdatedAt: now.Format("2006-01-02 15:04:05"),
+	})
+	return "保存草稿成功", 0
+}
+
+// GetDraftList 获取用户全部会话的草稿，按更新时间倒序
+func (d *draftService) GetDraftList(ownerId string) (string, []respond.DraftRespond, int) {
+	var draftList []model.Draft
+	if res := dao.GormDB.Where("user_id = ?", ownerId).Order("updated_at DESC").Find(&draftList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rspList := make([]respond.DraftRespond, 0, len(draftList))
+	for _, draft := range draftList {
+		rspList = append(rspList, respond.DraftRespond{
+			TargetId:  draft.TargetId,
+			Content:   draft.Content,
+			UpdatedAt: draft.UpdatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return "获取草稿成功", rspList, 0
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/random"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+type favoriteService struct {
+}
+
+var FavoriteService = new(favoriteService)
+
+// AddFavorite 收藏消息，保存消息快照，同一条消息只能收藏一次
+func (f *favoriteService) AddFavorite(req request.AddFavoriteRequest) (string, *respond.FavoriteRespond, int) {
+	tagsJson, msg := f.normalizeTags(req.Tags)
+	if msg != "" {
+		return msg, nil, -2
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > constants.FAVORITE_NOTE_MAX_LEN {
+		return fmt.Sprintf("备注不能超过%d个字", constants.FAVORITE_NOTE_MAX_LEN), nil, -2
+	}
+	var message model.Message
+	if res := dao.GormDB.Where("uuid = ?", req.MessageId).First(&message); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "消息不存在", nil, -2
+		}
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	ok, err := canAccessMessage(req.OwnerId, &message, nil)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if !ok {
+		return "只能收藏自己所在会话的消息", nil, -2
+	}
+	if message.Type == message_type_enum.AudioOrVideo || message.Type == message_type_enum.System {
+		return "该消息不能收藏", nil, -2
+	}
+	// 阅后即焚的消息到期后应从所有地方消失，不允许通过收藏保留
+	if message.ExpireAt.Valid {
+		return "阅后即焚消息不能收藏", nil, -2
+	}
+	conversationId := message.ReceiveId
+	if message.ReceiveId[0] == 'U' && message.ReceiveId == req.OwnerId {
+		conversationId = message.SendId
+	}
+	now := time.Now()
+	favorite := model.Favorite{
+		Uuid:             fmt.Sprintf("F%s", random.GetNowAndLenRandomString(11)),
+		OwnerId:          req.OwnerId,
+		MessageId:        message.Uuid,
+		ConversationId:   conversationId,
+		SendId:           message.SendId,
+		SendName:         message.SendName,
+		SendAvatar:       message.SendAvatar,
+		Type:             message.Type,
+		Content:          message.Content,
+		Url:              message.Url,
+		FileType:         message.FileType,
+		FileName:         message.FileName,
+		FileSize:         message.FileSize,
+		Extra:            message.Extra,
+		Tags:             tagsJson,
+		Note:             note,
+		MessageCreatedAt: message.CreatedAt,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	// 唯一索引兜底并发的重复收藏
+	res := dao.GormDB.Where("owner_id = ? AND message_id = ?", req.OwnerId, message.Uuid).FirstOrCreate(&favorite)
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if res.RowsAffected == 0 {
+		return "该消息已收藏", nil, -2
+	}
+	rsp := respond.NewFavoriteRespond(&favorite)
+	return "收藏成功", &rsp, 0
+}
+
+// UpdateFavorite 修改收藏的标签和备注
+func (f *favoriteService) UpdateFavorite(req request.UpdateFavoriteRequest) (string, int) {
+	tagsJson, msg := f.normalizeTags(req.Tags)
+	if msg != "" {
+		return msg, -2
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > constants.FAVORITE_NOTE_MAX_LEN {
+		return fmt.Sprintf("备注不能超过%d个字", constants.FAVORITE_NOTE_MAX_LEN), -2
+	}
+	res := dao.GormDB.Model(&model.Favorite{}).Where("uuid = ? AND owner_id = ?", req.FavoriteId, req.OwnerId).
+		Updates(map[string]interface{}{
+			"tags":       tagsJson,
+			"note":       note,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res.RowsAffected == 0 {
+		return "收藏不存在", -2
+	}
+	return "修改成功", 0
+}
+
+// DeleteFavorite 批量删除收藏，只能删除自己的收藏
+func (f *favoriteService) DeleteFavorite(ownerId string, favoriteIds []string) (string, int) {
+	favoriteIds = uniqueStrings(favoriteIds)
+	if len(favoriteIds) == 0 {
+		return "请选择要删除的收藏", -2
+	}
+	if res := dao.GormDB.Where("uuid IN ? AND owner_id = ?", favoriteIds, ownerId).Delete(&model.Favorite{}); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	return "删除成功", 0
+}
+
+// GetFavoriteList 获取收藏列表，按收藏时间倒序
+func (f *favoriteService) GetFavoriteList(req request.GetFavoriteListRequest) (string, *respond.GetFavoriteListRespond, int) {
+	query := f.filterQuery(req.OwnerId, req.Tag, req.Type)
+	rsp, err := f.findPage(query, req.Page, req.PageSize)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "获取收藏列表成功", rsp, 0
+}
+
+// SearchFavorite 在自己的收藏中搜索，收藏数量有限，直接在消息内容、文件名和备注上模糊匹配
+func (f *favoriteService) SearchFavorite(req request.SearchFavoriteRequest) (string, *respond.GetFavoriteListRespond, int) {
+	keyword := strings.TrimSpace(req.Keyword)
+	if keyword == "" {
+		return "请输入搜索关键词", nil, -2
+	}
+	pattern := "%" + escapeLike(keyword) + "%"
+	query := f.filterQuery(req.OwnerId, req.Tag, req.Type).
+		Where("content LIKE ? OR file_name LIKE ? OR note LIKE ?", pattern, pattern, pattern)
+	rsp, err := f.findPage(query, req.Page, req.PageSize)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "搜索成功", rsp, 0
+}
+
+// filterQuery 列表和搜索共用的过滤条件
+func (f *favoriteService) filterQuery(ownerId, tag string, messageType *int8) *gorm.DB {
+	query := dao.GormDB.Model(&model.Favorite{}).Where("owner_id = ?", ownerId)
+	if tag = strings.TrimSpace(tag); tag != "" {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", tag)
+	}
+	if messageType != nil {
+		query = query.Where("type = ?", *messageType)
+	}
+	return query
+}
+
+// findPage 分页查询收藏
+func (f *favoriteService) findPage(query *gorm.DB, page, pageSize int) (*respond.GetFavoriteListRespond, error) {
+	page, pageSize = normalizePage(page, pageSize)
+	var total int64
+	if res := query.Session(&gorm.Session{}).Count(&total); res.Error != nil {
+		return nil, res.Error
+	}
+	var favoriteList []model.Favorite
+	if res := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&favoriteList); res.Error != nil {
+		return nil, res.Error
+	}
+	rsp := &respond.GetFavoriteListRespond{
+		Total:     total,
+		Favorites: make([]respond.FavoriteRespond, 0, len(favoriteList)),
+	}
+	for i := range favoriteList {
+		rsp.Favorites = append(rsp.Favorites, respond.NewFavoriteRespond(&favoriteList[i]))
+	}
+	return rsp, nil
+}
+
+// normalizeTags 去掉空白和重复的标签，返回标签的json；不合法时返回提示信息
+func (f *favoriteService) normalizeTags(tags []string) (string, string) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > constants.FAVORITE_TAG_MAX_LEN {
+			return "", fmt.Sprintf("标签不能超过%d个字", constants.FAVORITE_TAG_MAX_LEN)
+		}
+		normalized = append(normalized, tag)
+	}
+	normalized = uniqueStrings(normalized)
+	if len(normalized) > constants.MAX_FAVORITE_TAGS {
+		return "", fmt.Sprintf("最多添加%d个标签", constants.MAX_FAVORITE_TAGS)
+	}
+	tagsByte, err := json.Marshal(normalized)
+	if err != nil {
+		zlog.Error(err.Error())
+		return "", constants.SYSTEM_ERROR
+	}
+	return string(tagsByte), ""
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/group_info/group_status_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_status_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/random"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+)
+
+type forwardService struct {
+}
+
+var ForwardService = new(forwardService)
+
+// ForwardMessage 将消息逐条或合并转发到多个目标，返回每个目标的转发结果
+func (f *forwardService) ForwardMessage(req request.ForwardMessageRequest) (string, []respond.ForwardMessageRespond, int) {
+	if len(req.MessageIds) == 0 || len(req.ReceiveIds) == 0 {
+		return "请选择要转发的消息和转发目标", nil, -2
+	}
+	if len(req.MessageIds) > constants.MAX_FORWARD_MESSAGES {
+		return fmt.Sprintf("一次最多转发%d条消息", constants.MAX_FORWARD_MESSAGES), nil, -2
+	}
+	if len(req.ReceiveIds) > constants.MAX_FORWARD_TARGETS {
+		return fmt.Sprintf("一次最多转发给%d个会话", constants.MAX_FORWARD_TARGETS), nil, -2
+	}
+	var messageList []model.Message
+	if res := dao.GormDB.Where("uuid IN ?", req.MessageIds).Order("created_at ASC").Find(&messageList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if len(messageList) != len(uniqueStrings(req.MessageIds)) {
+		return "消息不存在", nil, -2
+	}
+	groupCache := make(map[string]*model.GroupInfo)
+	for _, message := range messageList {
+		if message.Type == message_type_enum.AudioOrVideo {
+			return "通话消息不能转发", nil, -2
+		}
+		if message.Type == message_type_enum.System {
+			return "系统消息不能转发", nil, -2
+		}
+		if message.Type == message_type_enum.Poll {
+			return "投票消息不能转发", nil, -2
+		}
+		ok, err := canAccessMessage(req.OwnerId, &message, groupCache)
+		if err != nil {
+			zlog.Error(err.Error())
+			return constants.SYSTEM_ERROR, nil, -1
+		}
+		if !ok {
+			return "只能转发自己所在会话的消息", nil, -2
+		}
+	}
+
+	userClient, err := clients.GetGlobalUserClient()
+	if err != nil {
+		zlog.Error("获取用户客户端失败: " + err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	owner := userClient.GetUserInfo(req.OwnerId)
+	if owner == nil || owner.Uuid == "" {
+		zlog.Error("获取转发者信息失败: " + req.OwnerId)
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+
+	var mergedExtra string
+	if req.IsMerged {
+		mergedExtra, err = buildMergedForward(messageList, groupCache)
+		if err != nil {
+			zlog.Error(err.Error())
+			return constants.SYSTEM_ERROR, nil, -1
+		}
+	}
+
+	rspList := make([]respond.ForwardMessageRespond, 0, len(req.ReceiveIds))
+	for _, receiveId := range uniqueStrings(req.ReceiveIds) {
+		rsp := respond.ForwardMessageRespond{ReceiveId: receiveId}
+		sessionId, msg := checkSendTarget(req.OwnerId, receiveId)
+		if msg != "" {
+			rsp.Message = msg
+			rspList = append(rspList, rsp)
+			continue
+		}
+		var forwardList []model.Message
+		if req.IsMerged {
+			forward := newServerMessage(owner.Uuid, owner.Nickname, owner.Avatar, receiveId, sessionId)
+			forward.Type = message_type_enum.MergedForward
+			forward.Content = mergedForwardTitle(messageList, groupCache)
+			forward.Extra = mergedExtra
+			forwardList = append(forwardList, forward)
+		} else {
+			for _, message := range messageList {
+				forward := newServerMessage(owner.Uuid, owner.Nickname, owner.Avatar, receiveId, sessionId)
+				forward.Type = message.Type
+				forward.Content = message.Content
+				forward.Url = message.Url
+				forward.FileType = message.FileType
+				forward.FileName = message.FileName
+				forward.FileSize = message.FileSize
+				forward.Extra = message.Extra
+				forward.ForwardFrom = message.Uuid
+				forwardList = append(forwardList, forward)
+			}
+		}
+		duration, err := chat.GetDisappearDuration(owner.Uuid, receiveId)
+		if err != nil {
+			zlog.Error(err.Error())
+		}
+		for i := range forwardList {
+			chat.SetExpireAt(&forwardList[i], duration)
+		}
+		if res := dao.GormDB.Create(&forwardList); res.Error != nil {
+			zlog.Error(res.Error.Error())
+			rsp.Message = constants.SYSTEM_ERROR
+			rspList = append(rspList, rsp)
+			continue
+		}
+		for i := range forwardList {
+			chat.DeliverMessage(&forwardList[i])
+		}
+		rsp.Success = true
+		rsp.Message = "转发成功"
+		rspList = append(rspList, rsp)
+	}
+	return "转发完成", rspList, 0
+}
+
+// canAccessMessage 判断用户是否能看到这条消息：单聊需是收发双方之一，群聊需是群成员
+func canAccessMessage(userId string, message *model.Message, groupCache map[string]*model.GroupInfo) (bool, error) {
+	if message.ReceiveId[0] != 'G' {
+		return message.SendId == userId || message.ReceiveId == userId, nil
+	}
+	group, err := loadGroup(message.ReceiveId, groupCache)
+	if err != nil {
+		return false, err
+	}
+	return isGroupMember(group, userId), nil
+}
+
+// checkSendTarget 校验发送目标并确保发送者与目标之间的会话存在，返回会话id；不能发送时返回提示信息
+// 转发和定时消息共用
+func checkSendTarget(ownerId, receiveId string) (string, string) {
+	if receiveId == "" || (receiveId[0] != 'U' && receiveId[0] != 'G') {
+		return "", "发送目标不合法"
+	}
+	if receiveId[0] == 'U' {
+		userClient, err := clients.GetGlobalUserClient()
+		if err != nil {
+			zlog.Error("获取用户客户端失败: " + err.Error())
+			return "", constants.SYSTEM_ERROR
+		}
+		resp := userClient.GetContactStatus(ownerId, receiveId)
+		if resp == nil || resp.Code == -1 {
+			zlog.Error("查询好友关系失败: " + receiveId)
+			return "", constants.SYSTEM_ERROR
+		}
+		if resp.Status != 0 {
+			return "", "对方不是你的好友"
+		}
+	} else {
+		group, err := loadGroup(receiveId, nil)
+		if err != nil {
+			zlog.Error(err.Error())
+			return "", "群聊不存在"
+		}
+		if group.Status != group_status_enum.NORMAL {
+			return "", "该群聊不可用"
+		}
+		if !isGroupMember(group, ownerId) {
+			return "", "你不是该群成员"
+		}
+		// 转发、定时消息等服务端发出的消息不经过websocket的禁言拦截，这里同样检查
+		mutedUntil, err := chat.GroupMutedUntil(receiveId, ownerId)
+		if err != nil {
+			zlog.Error(err.Error())
+			return "", constants.SYSTEM_ERROR
+		}
+		if !mutedUntil.IsZero() {
+			return "", "你已被禁言至" + mutedUntil.Format("2006-01-02 15:04:05")
+		}
+	}
+	sessionClient, err := clients.GetGlobalSessionClient()
+	if err != nil {
+		zlog.Error("获取会话客户端失败: " + err.Error())
+		return "", constants.SYSTEM_ERROR
+	}
+	resp := sessionClient.CreateSessionIfNotExist(ownerId, receiveId)
+	if resp == nil || resp.Code != 0 {
+		zlog.Error("创建会话失败: " + receiveId)
+		return "", constants.SYSTEM_ERROR
+	}
+	// 单聊同时为接收者创建会话，和正常发消息保持一致
+	if receiveId[0] == 'U' {
+		if resp := sessionClient.CreateSessionIfNotExist(receiveId, ownerId); resp == nil || resp.Code != 0 {
+			zlog.Error("为接收者创建会话失败: " + receiveId)
+		}
+	}
+	return resp.SessionId, ""
+}
+
+// newServerMessage 构造一条由服务端生成、以sendId身份发出的新消息，转发和系统消息共用
+func newServerMessage(sendId, sendName, sendAvatar, receiveId, sessionId string) model.Message {
+	return model.Message{
+		Uuid:       fmt.Sprintf("M%s", random.GetNowAndLenRandomString(11)),
+		SessionId:  sessionId,
+		SendId:     sendId,
+		SendName:   sendName,
+		SendAvatar: sendAvatar,
+		ReceiveId:  receiveId,
+		FileSize:   "0B",
+		Status:     message_status_enum.Unsent,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// buildMergedForward 生成合并转发的聊天记录快照
+func buildMergedForward(messageList []model.Message, groupCache map[string]*model.GroupInfo) (string, error) {
+	merged := respond.MergedForwardRespond{
+		Title:    mergedForwardTitle(messageList, groupCache),
+		Messages: make([]respond.ForwardedMessageRespond, 0, len(messageList)),
+	}
+	for _, message := range messageList {
+		item := respond.ForwardedMessageRespond{
+			SendName:   message.SendName,
+			SendAvatar: message.SendAvatar,
+			Type:       message.Type,
+			Content:    message.Content,
+			Url:        message.Url,
+			FileType:   message.FileType,
+			FileName:   message.FileName,
+			FileSize:   message.FileSize,
+			CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if message.Extra != "" && json.Valid([]byte(message.Extra)) {
+			item.Extra = json.RawMessage(message.Extra)
+		}
+		merged.Messages = append(merged.Messages, item)
+	}
+	mergedByte, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(mergedByte), nil
+}
+
+// mergedForwardTitle 合并转发的标题：群聊为“群名的聊天记录”，单聊为“A和B的聊天记录”
+func mergedForwardTitle(messageList []model.Message, groupCache map[string]*model.GroupInfo) string {
+	first := messageList[0]
+	if first.ReceiveId[0] == 'G' {
+		if group, ok := groupCache[first.ReceiveId]; ok {
+			return group.Name + "的聊天记录"
+		}
+		return "群聊的聊天记录"
+	}
+	var names []string
+	for _, message := range messageList {
+		if len(names) == 0 || (len(names) == 1 && names[0] != message.SendName) {
+			names = append(names, message.SendName)
+		}
+	}
+	if len(names) == 1 {
+		return names[0] + "的聊天记录"
+	}
+	return names[0] + "和" + names[1] + "的聊天记录"
+}
+
+// loadGroup 查询群聊，groupCache不为nil时复用同一次请求中已查过的群聊
+func loadGroup(groupId string, groupCache map[string]*model.GroupInfo) (*model.GroupInfo, error) {
+	if group, ok := groupCache[groupId]; ok {
+		return group, nil
+	}
+	var group model.GroupInfo
+	if res := dao.GormDB.Where("uuid = ?", groupId).First(&group); res.Error != nil {
+		return nil, res.Error
+	}
+	if groupCache != nil {
+		groupCache[groupId] = &group
+	}
+	return &group, nil
+}
+
+// isGroupMember 判断用户是否在群成员列表中
+func isGroupMember(group *model.GroupInfo, userId string) bool {
+	var members []string
+	if err := json.Unmarshal(group.Members, &members); err != nil {
+		zlog.Error(err.Error())
+		return false
+	}
+	for _, member := range members {
+		if member == userId {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueStrings 去重并保持原有顺序
+func uniqueStrings(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	rsp := make([]string, 0, len(list))
+	for _, item := range list {
+		if seen[item] {
+			continue
+		}
+		seen[item] = true
+		rsp = append(rsp, item)
+	}
+	return rsp
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+type mentionService struct {
+}
+
+var MentionService = new(mentionService)
+
+// GetMentionList 获取跨群聊的“@我的消息”，按时间倒序
+func (m *mentionService) GetMentionList(ownerId string, page, pageSize int) (string, []respond.GetMentionListRespond, int) {
+	page, pageSize = normalizePage(page, pageSize)
+	var mentionList []model.MessageMention
+	if res := dao.GormDB.Where("user_id = ?", ownerId).Order("created_at DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&mentionList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if len(mentionList) == 0 {
+		return "获取@我的消息成功", []respond.GetMentionListRespond{}, 0
+	}
+	messageIds := make([]string, 0, len(mentionList))
+	for _, mention := range mentionList {
+		messageIds = append(messageIds, mention.MessageId)
+	}
+	var messageList []model.Message
+	if res := dao.GormDB.Where("uuid IN ?", messageIds).Find(&messageList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	messageMap := make(map[string]*model.Message, len(messageList))
+	for i := range messageList {
+		messageMap[messageList[i].Uuid] = &messageList[i]
+	}
+	rspList := make([]respond.GetMentionListRespond, 0, len(mentionList))
+	for _, mention := range mentionList {
+		message, ok := messageMap[mention.MessageId]
+		if !ok {
+			continue
+		}
+		messageRsp := respond.NewGetGroupMessageListRespond(message)
+		messageRsp.IsMentioned = true
+		rspList = append(rspList, respond.GetMentionListRespond{
+			GroupId: mention.GroupId,
+			IsAll:   mention.IsAll,
+			Message: messageRsp,
+		})
+	}
+	return "获取@我的消息成功", rspList, 0
+}
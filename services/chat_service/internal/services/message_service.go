@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,11 +15,13 @@ import (
 	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
 	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
 	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
 
 	"os"
 	"path/filepath"
 
 	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/random"
 	"github.com/puoxiu/gogochat/pkg/zlog"
 )
 
@@ -35,25 +38,14 @@ func (m *messageService) GetMessageList(userOneId, userTwoId string) (string, []
 		if errors.Is(err, redis.Nil) {
 			zlog.Info("缓存未命中，查询数据库: " + cacheKey)
 			var messageList []model.Message
-			if res := dao.GormDB.Where("(send_id = ? AND receive_id = ?) OR (send_id = ? AND receive_id = ?)", userOneId, userTwoId, userTwoId, userOneId).Order("created_at ASC").Find(&messageList); res.Error != nil {
+			if res := dao.GormDB.Where("(send_id = ? AND receive_id = ?) OR (send_id = ? AND receive_id = ?)", userOneId, userTwoId, userTwoId, userOneId).
+				Where("expire_at IS NULL OR expire_at > ?", time.Now()).Order("created_at ASC").Find(&messageList); res.Error != nil {
 				zlog.Error(res.Error.Error())
 				return constants.SYSTEM_ERROR, nil, -1
 			}
 			var rspList []respond.GetMessageListRespond
 			for _, message := range messageList {
-				rspList = append(rspList, respond.GetMessageListRespond{
-					SendId:     message.SendId,
-					SendName:   message.SendName,
-					SendAvatar: message.SendAvatar,
-					ReceiveId:  message.ReceiveId,
-					Content:    message.Content,
-					Url:        message.Url,
-					Type:       message.Type,
-					FileType:   message.FileType,
-					FileName:   message.FileName,
-					FileSize:   message.FileSize,
-					CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
-				})
+				rspList = append(rspList, respond.NewGetMessageListRespond(&message))
 			}
 			rspString, err := json.Marshal(rspList)
 			if err != nil {
@@ -84,26 +76,13 @@ func (m *messageService) GetGroupMessageList(groupId string) (string, []respond.
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			var messageList []model.Message
-			if res := dao.GormDB.Where("receive_id = ?", groupId).Order("created_at ASC").Find(&messageList); res.Error != nil {
+			if res := dao.GormDB.Where("receive_id = ? AND thread_id = '' AND (expire_at IS NULL OR expire_at > ?)", groupId, time.Now()).Order("created_at ASC").Find(&messageList); res.Error != nil {
 				zlog.Error(res.Error.Error())
 				return constants.SYSTEM_ERROR, nil, -1
 			}
 			var rspList []respond.GetGroupMessageListRespond
 			for _, message := range messageList {
-				rsp := respond.GetGroupMessageListRespond{
-					SendId:     message.SendId,
-					SendName:   message.SendName,
-					SendAvatar: message.SendAvatar,
-					ReceiveId:  message.ReceiveId,
-					Content:    message.Content,
-					Url:        message.Url,
-					Type:       message.Type,
-					FileType:   message.FileType,
-					FileName:   message.FileName,
-					FileSize:   message.FileSize,
-					CreatedAt:  message.CreatedAt.Format("2006-01-02 15:04:05"),
-				}
-				rspList = append(rspList, rsp)
+				rspList = append(rspList, respond.NewGetGroupMessageListRespond(&message))
 			}
 			rspString, err := json.Marshal(rspList)
 			if err != nil {
@@ -194,3 +173,49 @@ func (m *messageService) UploadFile(c *gin.Context) (string, int) {
 	}
 	return "上传成功", 0
 }
+
+// UploadVoice 上传语音文件，文件名由服务端生成，返回访问地址供发送语音消息使用
+func (m *messageService) UploadVoice(c *gin.Context) (string, *respond.UploadVoiceRespond, int) {
+	file, fileHeader, err := c.Request.FormFile("file")
+	if err != nil {
+		zlog.Error(err.Error())
+		return "请选择语音文件", nil, -2
+	}
+	defer file.Close()
+	zlog.Info(fmt.Sprintf("语音文件名：%s，文件大小：%d", fileHeader.Filename, fileHeader.Size))
+	if fileHeader.Size > constants.VOICE_MAX_SIZE {
+		return "语音文件过大", nil, -2
+	}
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	if !chat.IsAllowedVoiceType(ext) {
+		return "不支持的语音格式", nil, -2
+	}
+	voicePath := config.AppConfig.StaticSrcConfig.StaticVoicePath
+	if err := os.MkdirAll(voicePath, 0755); err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	fileName := fmt.Sprintf("V%s%s", random.GetNowAndLenRandomString(11), ext)
+	out, err := os.Create(filepath.Join(voicePath, fileName))
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	defer out.Close()
+	written, err := io.Copy(out, io.LimitReader(file, constants.VOICE_MAX_SIZE+1))
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if written > constants.VOICE_MAX_SIZE {
+		out.Close()
+		os.Remove(filepath.Join(voicePath, fileName))
+		return "语音文件过大", nil, -2
+	}
+	rsp := &respond.UploadVoiceRespond{
+		Url:      "/static/voices/" + fileName,
+		FileType: strings.TrimPrefix(ext, "."),
+		FileSize: fmt.Sprintf("%dB", written),
+	}
+	return "上传成功", rsp, 0
+}
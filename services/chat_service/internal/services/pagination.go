@@ -0,0 +1,17 @@
+package services
+
+import "github.com/puoxiu/gogochat/pkg/constants"
+
+// normalizePage 规范分页参数，返回页码和每页条数
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = constants.DEFAULT_PAGE_SIZE
+	}
+	if pageSize > constants.MAX_PAGE_SIZE {
+		pageSize = constants.MAX_PAGE_SIZE
+	}
+	return page, pageSize
+}
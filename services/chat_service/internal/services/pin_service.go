@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/conversation"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+	"gorm.io/gorm"
+)
+
+type pinService struct {
+}
+
+var PinService = new(pinService)
+
+// PinMessage 置顶消息：群聊需要群主或管理员，单聊双方都可以操作
+func (p *pinService) PinMessage(ownerId, messageId string) (string, int) {
+	message, msg, ret := p.loadPinnableMessage(ownerId, messageId)
+	if ret != 0 {
+		return msg, ret
+	}
+	if message.Type == message_type_enum.AudioOrVideo {
+		return "通话消息不能置顶", -2
+	}
+	if message.Type == message_type_enum.System {
+		return "系统消息不能置顶", -2
+	}
+	conversationId := conversation.Id(message.SendId, message.ReceiveId)
+	var pinCnt int64
+	if res := dao.GormDB.Model(&model.MessagePin{}).Where("conversation_id = ?", conversationId).Count(&pinCnt); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if pinCnt >= constants.MAX_PINNED_MESSAGES {
+		return fmt.Sprintf("每个会话最多置顶%d条消息", constants.MAX_PINNED_MESSAGES), -2
+	}
+	pin := model.MessagePin{
+		ConversationId: conversationId,
+		MessageId:      message.Uuid,
+		PinnedBy:       ownerId,
+		Snippet:        chat.PinSnippet(message),
+		CreatedAt:      time.Now(),
+	}
+	// 唯一索引兜底并发的重复置顶
+	res := dao.GormDB.Where("conversation_id = ? AND message_id = ?", conversationId, message.Uuid).FirstOrCreate(&pin)
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res.RowsAffected == 0 {
+		return "该消息已置顶", -2
+	}
+	chat.BroadcastPinEvent(chat.EventMessagePinned, ownerId, message, pin.Snippet)
+	return "置顶成功", 0
+}
+
+// UnpinMessage 取消置顶，权限与置顶相同
+func (p *pinService) UnpinMessage(ownerId, messageId string) (string, int) {
+	message, msg, ret := p.loadPinnableMessage(ownerId, messageId)
+	if ret != 0 {
+		return msg, ret
+	}
+	res := dao.GormDB.Where("conversation_id = ? AND message_id = ?",
+		conversation.Id(message.SendId, message.ReceiveId), message.Uuid).Delete(&model.MessagePin{})
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res.RowsAffected == 0 {
+		return "该消息未置顶", -2
+	}
+	chat.BroadcastPinEvent(chat.EventMessageUnpinned, ownerId, message, "")
+	return "取消置顶成功", 0
+}
+
+// GetPinnedMessageList 获取会话中的置顶消息，按置顶时间倒序
+func (p *pinService) GetPinnedMessageList(ownerId, targetId string) (string, []respond.GetPinnedMessageListRespond, int) {
+	if targetId == "" {
+		return "会话不存在", nil, -2
+	}
+	if targetId[0] == 'G' {
+		group, err := loadGroup(targetId, nil)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "群聊不存在", nil, -2
+			}
+			zlog.Error(err.Error())
+			return constants.SYSTEM_ERROR, nil, -1
+		}
+		if !isGroupMember(group, ownerId) {
+			return "你不是该群成员", nil, -2
+		}
+	}
+	var pinList []model.MessagePin
+	if res := dao.GormDB.Where("conversation_id = ?", conversation.Id(ownerId, targetId)).
+		Order("created_at DESC").Find(&pinList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if len(pinList) == 0 {
+		return "获取置顶消息成功", []respond.GetPinnedMessageListRespond{}, 0
+	}
+	messageIds := make([]string, 0, len(pinList))
+	for _, pin := range pinList {
+		messageIds = append(messageIds, pin.MessageId)
+	}
+	var messageList []model.Message
+	if res := dao.GormDB.Where("uuid IN ?", messageIds).Find(&messageList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	messageMap := make(map[string]*model.Message, len(messageList))
+	for i := range messageList {
+		messageMap[messageList[i].Uuid] = &messageList[i]
+	}
+	rspList := make([]respond.GetPinnedMessageListRespond, 0, len(pinList))
+	for _, pin := range pinList {
+		// 消息已被删除但置顶还没清理时直接跳过
+		message, ok := messageMap[pin.MessageId]
+		if !ok {
+			continue
+		}
+		rspList = append(rspList, respond.GetPinnedMessageListRespond{
+			PinnedBy: pin.PinnedBy,
+			PinnedAt: pin.CreatedAt.Format("2006-01-02 15:04:05"),
+			Message:  respond.NewGetMessageListRespond(message),
+		})
+	}
+	return "获取置顶消息成功", rspList, 0
+}
+
+// loadPinnableMessage 查询消息并校验当前用户是否可以修改该会话的置顶
+func (p *pinService) loadPinnableMessage(ownerId, messageId string) (*model.Message, string, int) {
+	var message model.Message
+	if res := dao.GormDB.Where("uuid = ?", messageId).First(&message); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, "消息不存在", -2
+		}
+		zlog.Error(res.Error.Error())
+		return nil, constants.SYSTEM_ERROR, -1
+	}
+	if message.ReceiveId[0] == 'G' {
+		group, err := loadGroup(message.ReceiveId, nil)
+		if err != nil {
+			zlog.Error(err.Error())
+			return nil, constants.SYSTEM_ERROR, -1
+		}
+		if !isGroupMember(group, ownerId) || !chat.IsGroupManager(group, ownerId) {
+			return nil, "只有群主或管理员可以置顶消息", -2
+		}
+	} else if message.SendId != ownerId && message.ReceiveId != ownerId {
+		return nil, "只能置顶自己所在会话的消息", -2
+	}
+	return &message, "", 0
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+	"gorm.io/gorm"
+)
+
+type pollService struct {
+}
+
+var PollService = new(pollService)
+
+// VotePoll 投票，与websocket投票走同一套校验
+func (p *pollService) VotePoll(ownerId, messageId string, options []int) (string, int) {
+	return chat.VotePoll(ownerId, messageId, options)
+}
+
+// ClosePoll 提前结束投票
+func (p *pollService) ClosePoll(ownerId, messageId string) (string, int) {
+	return chat.ClosePoll(ownerId, messageId)
+}
+
+// GetPollResult 获取投票统计，只有群成员可以查看
+func (p *pollService) GetPollResult(ownerId, messageId string) (string, *respond.PollResultRespond, int) {
+	var poll model.Poll
+	if res := dao.GormDB.Where("message_id = ?", messageId).First(&poll); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "投票不存在", nil, -2
+		}
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	group, err := loadGroup(poll.GroupId, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "群聊不存在", nil, -2
+		}
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if !isGroupMember(group, ownerId) {
+		return "你不是该群成员", nil, -2
+	}
+	rsp, err := chat.PollResult(&poll, ownerId)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "获取投票结果成功", rsp, 0
+}
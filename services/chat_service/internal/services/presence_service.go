@@ -0,0 +1,132 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/user_info/presence_status_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+)
+
+type presenceService struct {
+}
+
+var PresenceService = new(presenceService)
+
+// GetPresenceList 批量查询好友的在线状态，离线用户附带最近的上线、离线时间
+// 和订阅一样只能查询好友，不是好友的用户直接忽略
+func (p *presenceService) GetPresenceList(ownerId string, userIds []string) (string, []respond.PresenceRespond, int) {
+	userIds = uniqueStrings(userIds)
+	if len(userIds) > constants.MAX_PAGE_SIZE {
+		return fmt.Sprintf("一次最多查询%d个用户", constants.MAX_PAGE_SIZE), nil, -2
+	}
+	contactIds, msg, ret := filterContacts(ownerId, userIds)
+	if ret != 0 {
+		return msg, nil, ret
+	}
+	rspList, err := getPresenceList(contactIds)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "获取在线状态成功", rspList, 0
+}
+
+// SubscribePresence 订阅联系人的在线状态变化，只能订阅好友，返回订阅对象当前的状态
+func (p *presenceService) SubscribePresence(ownerId string, userIds []string) (string, []respond.PresenceRespond, int) {
+	userIds = uniqueStrings(userIds)
+	if len(userIds) > constants.MAX_PAGE_SIZE {
+		return fmt.Sprintf("一次最多订阅%d个用户", constants.MAX_PAGE_SIZE), nil, -2
+	}
+	contactIds, msg, ret := filterContacts(ownerId, userIds)
+	if ret != 0 {
+		return msg, nil, ret
+	}
+	if err := chat.ReplacePresenceSubscriptions(ownerId, contactIds); err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rspList, err := getPresenceList(contactIds)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "订阅成功", rspList, 0
+}
+
+// filterContacts 过滤出用户的好友，自己和不是好友的用户直接忽略
+func filterContacts(ownerId string, userIds []string) ([]string, string, int) {
+	userClient, err := clients.GetGlobalUserClient()
+	if err != nil {
+		zlog.Error("获取用户客户端失败: " + err.Error())
+		return nil, constants.SYSTEM_ERROR, -1
+	}
+	contactIds := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		if userId == ownerId {
+			continue
+		}
+		resp := userClient.GetContactStatus(ownerId, userId)
+		if resp == nil || resp.Code == -1 {
+			zlog.Error("查询好友关系失败: " + userId)
+			return nil, constants.SYSTEM_ERROR, -1
+		}
+		if resp.Status == 0 {
+			contactIds = append(contactIds, userId)
+		}
+	}
+	return contactIds, "", 0
+}
+
+// getPresenceList 在线用户取redis中的状态，其他用户按离线处理并从用户表补充最近上线、离线时间
+func getPresenceList(userIds []string) ([]respond.PresenceRespond, error) {
+	rspList := make([]respond.PresenceRespond, 0, len(userIds))
+	if len(userIds) == 0 {
+		return rspList, nil
+	}
+	presenceMap, err := chat.GetPresence(userIds)
+	if err != nil {
+		return nil, err
+	}
+	var offlineIds []string
+	for _, userId := range userIds {
+		if _, ok := presenceMap[userId]; !ok {
+			offlineIds = append(offlineIds, userId)
+		}
+	}
+	userMap := make(map[string]*model.UserInfo, len(offlineIds))
+	if len(offlineIds) > 0 {
+		var userList []model.UserInfo
+		if res := dao.GormDB.Where("uuid IN ?", offlineIds).Find(&userList); res.Error != nil {
+			return nil, res.Error
+		}
+		for i := range userList {
+			userMap[userList[i].Uuid] = &userList[i]
+		}
+	}
+	for _, userId := range userIds {
+		if presence, ok := presenceMap[userId]; ok {
+			rspList = append(rspList, *presence)
+			continue
+		}
+		rsp := respond.PresenceRespond{
+			UserId: userId,
+			Status: presence_status_enum.OFFLINE,
+		}
+		if user, ok := userMap[userId]; ok {
+			if user.LastOnlineAt.Valid {
+				rsp.LastOnlineAt = user.LastOnlineAt.Time.Format("2006-01-02 15:04:05")
+			}
+			if user.LastOfflineAt.Valid {
+				rsp.LastOfflineAt = user.LastOfflineAt.Time.Format("2006-01-02 15:04:05")
+			}
+		}
+		rspList = append(rspList, rsp)
+	}
+	return rspList, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/message/scheduled_status_enum"
+	"github.com/puoxiu/gogochat/pkg/random"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+)
+
+type scheduleService struct {
+}
+
+var ScheduleService = new(scheduleService)
+
+const scheduleTimeLayout = "2006-01-02 15:04:05"
+
+// ScheduleMessage 创建定时消息，到点后由调度器按正常消息发送
+func (s *scheduleService) ScheduleMessage(req request.ScheduleMessageRequest) (string, *respond.ScheduledMessageRespond, int) {
+	sendAt, msg := parseSendAt(req.SendAt)
+	if msg != "" {
+		return msg, nil, -2
+	}
+	var pendingCnt int64
+	if res := dao.GormDB.Model(&model.ScheduledMessage{}).
+		Where("owner_id = ? AND status = ?", req.OwnerId, scheduled_status_enum.Pending).Count(&pendingCnt); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if pendingCnt >= constants.MAX_SCHEDULED_MESSAGES {
+		return fmt.Sprintf("最多只能有%d条待发送的定时消息", constants.MAX_SCHEDULED_MESSAGES), nil, -2
+	}
+	payload, msg, ret := buildSchedulePayload(req.OwnerId, &req.Message)
+	if ret != 0 {
+		return msg, nil, ret
+	}
+	scheduled := model.ScheduledMessage{
+		Uuid:      fmt.Sprintf("S%s", random.GetNowAndLenRandomString(11)),
+		OwnerId:   req.OwnerId,
+		SessionId: req.Message.SessionId,
+		ReceiveId: req.Message.ReceiveId,
+		Type:      req.Message.Type,
+		Payload:   payload,
+		SendAt:    sendAt,
+		Status:    scheduled_status_enum.Pending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if res := dao.GormDB.Create(&scheduled); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "定时消息创建成功", newScheduledMessageRespond(&scheduled), 0
+}
+
+// UpdateScheduledMessage 修改待发送的定时消息的内容和发送时间
+func (s *scheduleService) UpdateScheduledMessage(req request.UpdateScheduledMessageRequest) (string, *respond.ScheduledMessageRespond, int) {
+	sendAt, msg := parseSendAt(req.SendAt)
+	if msg != "" {
+		return msg, nil, -2
+	}
+	payload, msg, ret := buildSchedulePayload(req.OwnerId, &req.Message)
+	if ret != 0 {
+		return msg, nil, ret
+	}
+	// 带上状态条件，调度器已经领取的消息不能再修改
+	res := dao.GormDB.Model(&model.ScheduledMessage{}).
+		Where("uuid = ? AND owner_id = ? AND status = ?", req.ScheduleId, req.OwnerId, scheduled_status_enum.Pending).
+		Updates(map[string]interface{}{
+			"session_id": req.Message.SessionId,
+			"receive_id": req.Message.ReceiveId,
+			"type":       req.Message.Type,
+			"payload":    payload,
+			"send_at":    sendAt,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if res.RowsAffected == 0 {
+		return "定时消息不存在或已发送", nil, -2
+	}
+	var scheduled model.ScheduledMessage
+	if res := dao.GormDB.Where("uuid = ?", req.ScheduleId).First(&scheduled); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "定时消息修改成功", newScheduledMessageRespond(&scheduled), 0
+}
+
+// CancelScheduledMessage 取消待发送的定时消息
+func (s *scheduleService) CancelScheduledMessage(ownerId, scheduleId string) (string, int) {
+	res := dao.GormDB.Model(&model.ScheduledMessage{}).
+		Where("uuid = ? AND owner_id = ? AND status = ?", scheduleId, ownerId, scheduled_status_enum.Pending).
+		Updates(map[string]interface{}{
+			"status":     scheduled_status_enum.Canceled,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res.RowsAffected == 0 {
+		return "定时消息不存在或已发送", -2
+	}
+	return "定时消息已取消", 0
+}
+
+// GetScheduledMessageList 获取待发送和发送失败的定时消息，按发送时间排序
+func (s *scheduleService) GetScheduledMessageList(ownerId string, page, pageSize int) (string, []respond.ScheduledMessageRespond, int) {
+	page, pageSize = normalizePage(page, pageSize)
+	var scheduledList []model.ScheduledMessage
+	if res := dao.GormDB.Where("owner_id = ? AND status IN ?", ownerId,
+		[]int{scheduled_status_enum.Pending, scheduled_status_enum.Failed}).
+		Order("send_at ASC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&scheduledList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rspList := make([]respond.ScheduledMessageRespond, 0, len(scheduledList))
+	for i := range scheduledList {
+		rspList = append(rspList, *newScheduledMessageRespond(&scheduledList[i]))
+	}
+	return "获取定时消息成功", rspList, 0
+}
+
+// parseSendAt 解析发送时间，必须是未来且不超过最远的定时范围
+func parseSendAt(sendAtString string) (time.Time, string) {
+	sendAt, err := time.ParseInLocation(scheduleTimeLayout, sendAtString, time.Local)
+	if err != nil {
+		return time.Time{}, "发送时间格式错误"
+	}
+	now := time.Now()
+	if !sendAt.After(now) {
+		return time.Time{}, "发送时间必须晚于当前时间"
+	}
+	if sendAt.After(now.AddDate(0, 0, constants.MAX_SCHEDULE_DAYS)) {
+		return time.Time{}, fmt.Sprintf("最多只能定时到%d天后", constants.MAX_SCHEDULE_DAYS)
+	}
+	return sendAt, ""
+}
+
+// buildSchedulePayload 校验定时消息并生成投递时使用的消息请求
+// 发送者以owner_id为准，会话和发送者信息由服务端填充
+func buildSchedulePayload(ownerId string, message *request.ChatMessageRequest) (string, string, int) {
+	sessionId, msg := checkSendTarget(ownerId, message.ReceiveId)
+	if msg != "" {
+		if msg == constants.SYSTEM_ERROR {
+			return "", msg, -1
+		}
+		return "", msg, -2
+	}
+	userClient, err := clients.GetGlobalUserClient()
+	if err != nil {
+		zlog.Error("获取用户客户端失败: " + err.Error())
+		return "", constants.SYSTEM_ERROR, -1
+	}
+	owner := userClient.GetUserInfo(ownerId)
+	if owner == nil || owner.Uuid == "" {
+		zlog.Error("获取发送者信息失败: " + ownerId)
+		return "", constants.SYSTEM_ERROR, -1
+	}
+	message.SessionId = sessionId
+	message.SendId = ownerId
+	message.SendName = owner.Nickname
+	message.SendAvatar = owner.Avatar
+	message.Scheduled = true
+	if err := chat.ValidateMessageRequest(message); err != nil {
+		return "", err.Error(), -2
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		zlog.Error(err.Error())
+		return "", constants.SYSTEM_ERROR, -1
+	}
+	return string(payload), "", 0
+}
+
+func newScheduledMessageRespond(scheduled *model.ScheduledMessage) *respond.ScheduledMessageRespond {
+	rsp := &respond.ScheduledMessageRespond{
+		ScheduleId: scheduled.Uuid,
+		SessionId:  scheduled.SessionId,
+		ReceiveId:  scheduled.ReceiveId,
+		Type:       scheduled.Type,
+		SendAt:     scheduled.SendAt.Format(scheduleTimeLayout),
+		Status:     scheduled.Status,
+		CreatedAt:  scheduled.CreatedAt.Format(scheduleTimeLayout),
+	}
+	var message request.ChatMessageRequest
+	if err := json.Unmarshal([]byte(scheduled.Payload), &message); err != nil {
+		zlog.Error(err.Error())
+		return rsp
+	}
+	rsp.Content = message.Content
+	rsp.Url = message.Url
+	rsp.FileName = message.FileName
+	rsp.Extra = message.Extra
+	return rsp
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/message/message_type_enum"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+)
+
+type searchService struct {
+}
+
+var SearchService = new(searchService)
+
+const searchTimeLayout = "2006-01-02 15:04:05"
+
+// ngramTokenSize 与MySQL的ngram_token_size保持一致，短于它的关键词无法命中全文索引
+const ngramTokenSize = 2
+
+// SearchMessage 搜索聊天记录，content上建有ngram全文索引，支持中文分词
+// 指定target_id时只搜索该会话，否则搜索自己参与的全部单聊和群聊
+func (s *searchService) SearchMessage(req request.SearchMessageRequest) (string, *respond.SearchMessageRespond, int) {
+	keyword := strings.TrimSpace(req.Keyword)
+	if keyword == "" {
+		return "请输入搜索关键词", nil, -2
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+
+	query := dao.GormDB.Model(&model.Message{}).Where("type <> ?", message_type_enum.AudioOrVideo)
+	if utf8.RuneCountInString(keyword) < ngramTokenSize {
+		query = query.Where("content LIKE ?", "%"+escapeLike(keyword)+"%")
+	} else {
+		// 布尔模式下用双引号做短语匹配，去掉关键词中的双引号避免破坏语法
+		query = query.Where("MATCH(content) AGAINST(? IN BOOLEAN MODE)", `"`+strings.ReplaceAll(keyword, `"`, " ")+`"`)
+	}
+
+	if req.TargetId != "" {
+		if req.TargetId[0] == 'G' {
+			group, err := loadGroup(req.TargetId, nil)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return "群聊不存在", nil, -2
+				}
+				zlog.Error(err.Error())
+				return constants.SYSTEM_ERROR, nil, -1
+			}
+			if !isGroupMember(group, req.OwnerId) {
+				return "你不是该群成员", nil, -2
+			}
+			query = query.Where("receive_id = ?", req.TargetId)
+		} else {
+			query = query.Where("(send_id = ? AND receive_id = ?) OR (send_id = ? AND receive_id = ?)",
+				req.OwnerId, req.TargetId, req.TargetId, req.OwnerId)
+		}
+	} else {
+		var groupIds []string
+		if res := dao.GormDB.Model(&model.GroupInfo{}).
+			Where("JSON_CONTAINS(members, JSON_QUOTE(?))", req.OwnerId).Pluck("uuid", &groupIds); res.Error != nil {
+			zlog.Error(res.Error.Error())
+			return constants.SYSTEM_ERROR, nil, -1
+		}
+		if len(groupIds) > 0 {
+			query = query.Where("send_id = ? OR receive_id = ? OR receive_id IN ?", req.OwnerId, req.OwnerId, groupIds)
+		} else {
+			query = query.Where("send_id = ? OR receive_id = ?", req.OwnerId, req.OwnerId)
+		}
+	}
+
+	if req.SendId != "" {
+		query = query.Where("send_id = ?", req.SendId)
+	}
+	if req.Type != nil {
+		query = query.Where("type = ?", *req.Type)
+	}
+	if req.StartTime != "" {
+		startTime, err := time.ParseInLocation(searchTimeLayout, req.StartTime, time.Local)
+		if err != nil {
+			return "开始时间格式错误", nil, -2
+		}
+		query = query.Where("created_at >= ?", startTime)
+	}
+	if req.EndTime != "" {
+		endTime, err := time.ParseInLocation(searchTimeLayout, req.EndTime, time.Local)
+		if err != nil {
+			return "结束时间格式错误", nil, -2
+		}
+		query = query.Where("created_at <= ?", endTime)
+	}
+
+	var total int64
+	if res := query.Session(&gorm.Session{}).Count(&total); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	var messageList []model.Message
+	if res := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&messageList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rsp := &respond.SearchMessageRespond{
+		Total:    total,
+		Messages: make([]respond.SearchMessageItemRespond, 0, len(messageList)),
+	}
+	for _, message := range messageList {
+		conversationId := message.ReceiveId
+		if message.ReceiveId[0] == 'U' && message.ReceiveId == req.OwnerId {
+			conversationId = message.SendId
+		}
+		rsp.Messages = append(rsp.Messages, respond.SearchMessageItemRespond{
+			ConversationId: conversationId,
+			Message:        respond.NewGetMessageListRespond(&message),
+		})
+	}
+	return "搜索成功", rsp, 0
+}
+
+// escapeLike 转义LIKE中的通配符
+func escapeLike(keyword string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(keyword)
+}
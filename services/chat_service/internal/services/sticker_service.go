@@ -0,0 +1,59 @@
+package services
+
+import (
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+)
+
+type stickerService struct {
+}
+
+var StickerService = new(stickerService)
+
+// GetStickerPackList 获取上架中的表情包及其表情，供客户端发送表情消息时选择
+func (s *stickerService) GetStickerPackList() (string, []respond.GetStickerPackListRespond, int) {
+	var packList []model.StickerPack
+	if res := dao.GormDB.Where("status = 0").Order("id ASC").Find(&packList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rspList := make([]respond.GetStickerPackListRespond, 0, len(packList))
+	if len(packList) == 0 {
+		return "获取表情包成功", rspList, 0
+	}
+	packIds := make([]string, 0, len(packList))
+	for _, pack := range packList {
+		packIds = append(packIds, pack.Uuid)
+	}
+	var itemList []model.StickerItem
+	if res := dao.GormDB.Where("pack_id IN ?", packIds).Order("sort ASC, id ASC").Find(&itemList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	itemMap := make(map[string][]respond.StickerItemRespond, len(packList))
+	for _, item := range itemList {
+		itemMap[item.PackId] = append(itemMap[item.PackId], respond.StickerItemRespond{
+			Uuid:   item.Uuid,
+			Name:   item.Name,
+			Url:    item.Url,
+			Width:  item.Width,
+			Height: item.Height,
+		})
+	}
+	for _, pack := range packList {
+		items := itemMap[pack.Uuid]
+		if items == nil {
+			items = []respond.StickerItemRespond{}
+		}
+		rspList = append(rspList, respond.GetStickerPackListRespond{
+			Uuid:  pack.Uuid,
+			Name:  pack.Name,
+			Cover: pack.Cover,
+			Items: items,
+		})
+	}
+	return "获取表情包成功", rspList, 0
+}
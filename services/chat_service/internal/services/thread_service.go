@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type threadService struct {
+}
+
+var ThreadService = new(threadService)
+
+// GetThreadList 获取群聊中的话题列表，按最近回复时间倒序，附带当前用户的未读数
+func (t *threadService) GetThreadList(groupId, ownerId string, page, pageSize int) (string, []respond.GetThreadListRespond, int) {
+	page, pageSize = normalizePage(page, pageSize)
+	var rootList []model.Message
+	if res := dao.GormDB.Where("receive_id = ? AND thread_id = '' AND reply_cnt > 0", groupId).
+		Order("last_reply_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&rootList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if len(rootList) == 0 {
+		return "获取话题列表成功", []respond.GetThreadListRespond{}, 0
+	}
+	rootIds := make([]string, 0, len(rootList))
+	for _, root := range rootList {
+		rootIds = append(rootIds, root.Uuid)
+	}
+	// 未读数：自己没读过的、别人发的话题回复
+	var unreadList []struct {
+		ThreadId  string
+		UnreadCnt int64
+	}
+	if res := dao.GormDB.Table("message AS m").
+		Select("m.thread_id AS thread_id, COUNT(*) AS unread_cnt").
+		Joins("LEFT JOIN thread_read AS r ON r.thread_id = m.thread_id AND r.user_id = ?", ownerId).
+		Where("m.thread_id IN ? AND m.send_id <> ? AND (r.last_read_at IS NULL OR m.created_at > r.last_read_at)", rootIds, ownerId).
+		Group("m.thread_id").Scan(&unreadList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	unreadMap := make(map[string]int64, len(unreadList))
+	for _, unread := range unreadList {
+		unreadMap[unread.ThreadId] = unread.UnreadCnt
+	}
+	rspList := make([]respond.GetThreadListRespond, 0, len(rootList))
+	for _, root := range rootList {
+		rsp := respond.GetThreadListRespond{
+			RootMessage: respond.NewGetGroupMessageListRespond(&root),
+			ReplyCnt:    root.ReplyCnt,
+			UnreadCnt:   unreadMap[root.Uuid],
+		}
+		if root.LastReplyAt.Valid {
+			rsp.LastReplyAt = root.LastReplyAt.Time.Format("2006-01-02 15:04:05")
+		}
+		rspList = append(rspList, rsp)
+	}
+	return "获取话题列表成功", rspList, 0
+}
+
+// GetThreadMessageList 分页获取话题内的回复，按时间正序
+func (t *threadService) GetThreadMessageList(threadId string, page, pageSize int) (string, *respond.GetThreadMessageListRespond, int) {
+	page, pageSize = normalizePage(page, pageSize)
+	var root model.Message
+	if res := dao.GormDB.Where("uuid = ? AND thread_id = ''", threadId).First(&root); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "话题不存在", nil, -2
+		}
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	var total int64
+	if res := dao.GormDB.Model(&model.Message{}).Where("thread_id = ?", threadId).Count(&total); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	var messageList []model.Message
+	if res := dao.GormDB.Where("thread_id = ?", threadId).Order("created_at ASC").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&messageList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rsp := &respond.GetThreadMessageListRespond{
+		Total:    total,
+		Messages: make([]respond.GetGroupMessageListRespond, 0, len(messageList)),
+	}
+	for _, message := range messageList {
+		rsp.Messages = append(rsp.Messages, respond.NewGetGroupMessageListRespond(&message))
+	}
+	return "获取话题消息成功", rsp, 0
+}
+
+// ReadThread 将话题标记为已读
+func (t *threadService) ReadThread(ownerId, threadId string) (string, int) {
+	threadRead := model.ThreadRead{
+		UserId:     ownerId,
+		ThreadId:   threadId,
+		LastReadAt: time.Now(),
+	}
+	if res := dao.GormDB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "thread_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_read_at"}),
+	}).Create(&threadRead); res.Error != nil {
+		zlog.Error(fmt.Sprintf("话题已读失败: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, -1
+	}
+	return "已读成功", 0
+}
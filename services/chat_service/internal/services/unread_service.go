@@ -0,0 +1,49 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/puoxiu/gogochat/common/unread"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/chat_service/internal/services/chat"
+)
+
+type unreadService struct {
+}
+
+var UnreadService = new(unreadService)
+
+// ReadSession 清零会话未读数，客户端打开会话或读到最新消息时调用
+func (u *unreadService) ReadSession(req request.ReadSessionRequest) (string, int) {
+	if req.TargetId == "" || (req.TargetId[0] != 'U' && req.TargetId[0] != 'G') {
+		return "会话不合法", -2
+	}
+	if err := chat.ReadSession(req.OwnerId, req.TargetId); err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	return "已读成功", 0
+}
+
+// GetUnreadTotal 获取未读角标，包括总数和有未读的会话
+func (u *unreadService) GetUnreadTotal(req request.GetUnreadTotalRequest) (string, *respond.UnreadTotalRespond, int) {
+	counts, err := chat.UnreadCounts(req.OwnerId)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rsp := &respond.UnreadTotalRespond{
+		Total:    unread.Total(counts),
+		Sessions: make([]respond.SessionUnreadRespond, 0, len(counts)),
+	}
+	for targetId, n := range counts {
+		rsp.Sessions = append(rsp.Sessions, respond.SessionUnreadRespond{TargetId: targetId, Unread: n})
+	}
+	sort.Slice(rsp.Sessions, func(i, j int) bool {
+		return rsp.Sessions[i].TargetId < rsp.Sessions[j].TargetId
+	})
+	return "获取未读数成功", rsp, 0
+}
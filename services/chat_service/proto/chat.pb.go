@@ -0,0 +1,518 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v4.25.3
+// source: services/chat_service/proto/chat.proto
+
+package chat
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 机器人建立事件流的请求
+type BotStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // 机器人令牌
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BotStreamRequest) Reset() {
+	*x = BotStreamRequest{}
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BotStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BotStreamRequest) ProtoMessage() {}
+
+func (x *BotStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BotStreamRequest.ProtoReflect.Descriptor instead.
+func (*BotStreamRequest) Descriptor() ([]byte, []int) {
+	return file_services_chat_service_proto_chat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BotStreamRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// 推送给机器人的事件
+type BotEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"` // 事件id，机器人可据此去重
+	Event         string                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`                     // 事件类型，目前只有message
+	Payload       string                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`                 // 事件内容json，与webhook请求体相同
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BotEvent) Reset() {
+	*x = BotEvent{}
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BotEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BotEvent) ProtoMessage() {}
+
+func (x *BotEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BotEvent.ProtoReflect.Descriptor instead.
+func (*BotEvent) Descriptor() ([]byte, []int) {
+	return file_services_chat_service_proto_chat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BotEvent) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *BotEvent) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *BotEvent) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+// 机器人发送消息请求
+type SendBotMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`                          // 机器人令牌
+	ReceiveId     string                 `protobuf:"bytes,2,opt,name=receive_id,json=receiveId,proto3" json:"receive_id,omitempty"` // 接收者，用户或群聊uuid
+	Type          int32                  `protobuf:"varint,3,opt,name=type,proto3" json:"type,omitempty"`                           // 消息类型，与websocket消息相同
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`                      // 文本内容
+	Url           string                 `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`                              // 文件等消息的地址
+	FileType      string                 `protobuf:"bytes,6,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
+	FileName      string                 `protobuf:"bytes,7,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	FileSize      string                 `protobuf:"bytes,8,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	Extra         string                 `protobuf:"bytes,9,opt,name=extra,proto3" json:"extra,omitempty"`                                   // 图片、位置等消息的结构化内容json
+	ReplyTo       string                 `protobuf:"bytes,10,opt,name=reply_to,json=replyTo,proto3" json:"reply_to,omitempty"`               // 引用回复的消息uuid
+	Mentions      []string               `protobuf:"bytes,11,rep,name=mentions,proto3" json:"mentions,omitempty"`                            // 群聊中@的成员uuid
+	ClientMsgId   string                 `protobuf:"bytes,12,opt,name=client_msg_id,json=clientMsgId,proto3" json:"client_msg_id,omitempty"` // 机器人生成的消息id，用于重发去重
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBotMessageRequest) Reset() {
+	*x = SendBotMessageRequest{}
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBotMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBotMessageRequest) ProtoMessage() {}
+
+func (x *SendBotMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBotMessageRequest.ProtoReflect.Descriptor instead.
+func (*SendBotMessageRequest) Descriptor() ([]byte, []int) {
+	return file_services_chat_service_proto_chat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SendBotMessageRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *SendBotMessageRequest) GetReceiveId() string {
+	if x != nil {
+		return x.ReceiveId
+	}
+	return ""
+}
+
+func (x *SendBotMessageRequest) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *SendBotMessageRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SendBotMessageRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *SendBotMessageRequest) GetFileType() string {
+	if x != nil {
+		return x.FileType
+	}
+	return ""
+}
+
+func (x *SendBotMessageRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *SendBotMessageRequest) GetFileSize() string {
+	if x != nil {
+		return x.FileSize
+	}
+	return ""
+}
+
+func (x *SendBotMessageRequest) GetExtra() string {
+	if x != nil {
+		return x.Extra
+	}
+	return ""
+}
+
+func (x *SendBotMessageRequest) GetReplyTo() string {
+	if x != nil {
+		return x.ReplyTo
+	}
+	return ""
+}
+
+func (x *SendBotMessageRequest) GetMentions() []string {
+	if x != nil {
+		return x.Mentions
+	}
+	return nil
+}
+
+func (x *SendBotMessageRequest) GetClientMsgId() string {
+	if x != nil {
+		return x.ClientMsgId
+	}
+	return ""
+}
+
+// 机器人发送消息响应
+type SendBotMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`      // 状态码 0-成功 -1-服务失败 -2 业务失败
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // 提示信息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBotMessageResponse) Reset() {
+	*x = SendBotMessageResponse{}
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBotMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBotMessageResponse) ProtoMessage() {}
+
+func (x *SendBotMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBotMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendBotMessageResponse) Descriptor() ([]byte, []int) {
+	return file_services_chat_service_proto_chat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SendBotMessageResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *SendBotMessageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 推送给用户在线设备的事件，用于其他服务同步用户的设置变化
+type PushUserEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 用户uuid
+	Event         string                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`                 // 事件json，原样下发给客户端
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushUserEventRequest) Reset() {
+	*x = PushUserEventRequest{}
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushUserEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushUserEventRequest) ProtoMessage() {}
+
+func (x *PushUserEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushUserEventRequest.ProtoReflect.Descriptor instead.
+func (*PushUserEventRequest) Descriptor() ([]byte, []int) {
+	return file_services_chat_service_proto_chat_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PushUserEventRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PushUserEventRequest) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+type PushUserEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`      // 状态码 0-成功 -1-服务失败 -2 业务失败
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // 提示信息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushUserEventResponse) Reset() {
+	*x = PushUserEventResponse{}
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushUserEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushUserEventResponse) ProtoMessage() {}
+
+func (x *PushUserEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_chat_service_proto_chat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushUserEventResponse.ProtoReflect.Descriptor instead.
+func (*PushUserEventResponse) Descriptor() ([]byte, []int) {
+	return file_services_chat_service_proto_chat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PushUserEventResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *PushUserEventResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_services_chat_service_proto_chat_proto protoreflect.FileDescriptor
+
+const file_services_chat_service_proto_chat_proto_rawDesc = "" +
+	"\n" +
+	"&services/chat_service/proto/chat.proto\x12\x04chat\"(\n" +
+	"\x10BotStreamRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"U\n" +
+	"\bBotEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x03R\aeventId\x12\x14\n" +
+	"\x05event\x18\x02 \x01(\tR\x05event\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\tR\apayload\"\xd4\x02\n" +
+	"\x15SendBotMessageRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"receive_id\x18\x02 \x01(\tR\treceiveId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\x05R\x04type\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12\x1b\n" +
+	"\tfile_type\x18\x06 \x01(\tR\bfileType\x12\x1b\n" +
+	"\tfile_name\x18\a \x01(\tR\bfileName\x12\x1b\n" +
+	"\tfile_size\x18\b \x01(\tR\bfileSize\x12\x14\n" +
+	"\x05extra\x18\t \x01(\tR\x05extra\x12\x19\n" +
+	"\breply_to\x18\n" +
+	" \x01(\tR\areplyTo\x12\x1a\n" +
+	"\bmentions\x18\v \x03(\tR\bmentions\x12\"\n" +
+	"\rclient_msg_id\x18\f \x01(\tR\vclientMsgId\"F\n" +
+	"\x16SendBotMessageResponse\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"E\n" +
+	"\x14PushUserEventRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05event\x18\x02 \x01(\tR\x05event\"E\n" +
+	"\x15PushUserEventResponse\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xdb\x01\n" +
+	"\vChatService\x125\n" +
+	"\tBotStream\x12\x16.chat.BotStreamRequest\x1a\x0e.chat.BotEvent0\x01\x12K\n" +
+	"\x0eSendBotMessage\x12\x1b.chat.SendBotMessageRequest\x1a\x1c.chat.SendBotMessageResponse\x12H\n" +
+	"\rPushUserEvent\x12\x1a.chat.PushUserEventRequest\x1a\x1b.chat.PushUserEventResponseB\x0eZ\f./proto/chatb\x06proto3"
+
+var (
+	file_services_chat_service_proto_chat_proto_rawDescOnce sync.Once
+	file_services_chat_service_proto_chat_proto_rawDescData []byte
+)
+
+func file_services_chat_service_proto_chat_proto_rawDescGZIP() []byte {
+	file_services_chat_service_proto_chat_proto_rawDescOnce.Do(func() {
+		file_services_chat_service_proto_chat_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_services_chat_service_proto_chat_proto_rawDesc), len(file_services_chat_service_proto_chat_proto_rawDesc)))
+	})
+	return file_services_chat_service_proto_chat_proto_rawDescData
+}
+
+var file_services_chat_service_proto_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_services_chat_service_proto_chat_proto_goTypes = []any{
+	(*BotStreamRequest)(nil),       // 0: chat.BotStreamRequest
+	(*BotEvent)(nil),               // 1: chat.BotEvent
+	(*SendBotMessageRequest)(nil),  // 2: chat.SendBotMessageRequest
+	(*SendBotMessageResponse)(nil), // 3: chat.SendBotMessageResponse
+	(*PushUserEventRequest)(nil),   // 4: chat.PushUserEventRequest
+	(*PushUserEventResponse)(nil),  // 5: chat.PushUserEventResponse
+}
+var file_services_chat_service_proto_chat_proto_depIdxs = []int32{
+	0, // 0: chat.ChatService.BotStream:input_type -> chat.BotStreamRequest
+	2, // 1: chat.ChatService.SendBotMessage:input_type -> chat.SendBotMessageRequest
+	4, // 2: chat.ChatService.PushUserEvent:input_type -> chat.PushUserEventRequest
+	1, // 3: chat.ChatService.BotStream:output_type -> chat.BotEvent
+	3, // 4: chat.ChatService.SendBotMessage:output_type -> chat.SendBotMessageResponse
+	5, // 5: chat.ChatService.PushUserEvent:output_type -> chat.PushUserEventResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_services_chat_service_proto_chat_proto_init() }
+func file_services_chat_service_proto_chat_proto_init() {
+	if File_services_chat_service_proto_chat_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_services_chat_service_proto_chat_proto_rawDesc), len(file_services_chat_service_proto_chat_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_services_chat_service_proto_chat_proto_goTypes,
+		DependencyIndexes: file_services_chat_service_proto_chat_proto_depIdxs,
+		MessageInfos:      file_services_chat_service_proto_chat_proto_msgTypes,
+	}.Build()
+	File_services_chat_service_proto_chat_proto = out.File
+	file_services_chat_service_proto_chat_proto_goTypes = nil
+	file_services_chat_service_proto_chat_proto_depIdxs = nil
+}
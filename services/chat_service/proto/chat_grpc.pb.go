@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.25.3
+// source: services/chat_service/proto/chat.proto
+
+package chat
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ChatService_BotStream_FullMethodName      = "/chat.ChatService/BotStream"
+	ChatService_SendBotMessage_FullMethodName = "/chat.ChatService/SendBotMessage"
+	ChatService_PushUserEvent_FullMethodName  = "/chat.ChatService/PushUserEvent"
+)
+
+// ChatServiceClient is the client API for ChatService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// 聊天服务
+type ChatServiceClient interface {
+	// 机器人以流的方式接收发给自己的消息
+	BotStream(ctx context.Context, in *BotStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BotEvent], error)
+	// 机器人发送消息，进入正常的消息发送流程
+	SendBotMessage(ctx context.Context, in *SendBotMessageRequest, opts ...grpc.CallOption) (*SendBotMessageResponse, error)
+	// 向用户在线的连接推送事件，用户不在线时忽略
+	PushUserEvent(ctx context.Context, in *PushUserEventRequest, opts ...grpc.CallOption) (*PushUserEventResponse, error)
+}
+
+type chatServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChatServiceClient(cc grpc.ClientConnInterface) ChatServiceClient {
+	return &chatServiceClient{cc}
+}
+
+func (c *chatServiceClient) BotStream(ctx context.Context, in *BotStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BotEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChatService_ServiceDesc.Streams[0], ChatService_BotStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BotStreamRequest, BotEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_BotStreamClient = grpc.ServerStreamingClient[BotEvent]
+
+func (c *chatServiceClient) SendBotMessage(ctx context.Context, in *SendBotMessageRequest, opts ...grpc.CallOption) (*SendBotMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendBotMessageResponse)
+	err := c.cc.Invoke(ctx, ChatService_SendBotMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) PushUserEvent(ctx context.Context, in *PushUserEventRequest, opts ...grpc.CallOption) (*PushUserEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PushUserEventResponse)
+	err := c.cc.Invoke(ctx, ChatService_PushUserEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChatServiceServer is the server API for ChatService service.
+// All implementations must embed UnimplementedChatServiceServer
+// for forward compatibility.
+//
+// 聊天服务
+type ChatServiceServer interface {
+	// 机器人以流的方式接收发给自己的消息
+	BotStream(*BotStreamRequest, grpc.ServerStreamingServer[BotEvent]) error
+	// 机器人发送消息，进入正常的消息发送流程
+	SendBotMessage(context.Context, *SendBotMessageRequest) (*SendBotMessageResponse, error)
+	// 向用户在线的连接推送事件，用户不在线时忽略
+	PushUserEvent(context.Context, *PushUserEventRequest) (*PushUserEventResponse, error)
+	mustEmbedUnimplementedChatServiceServer()
+}
+
+// UnimplementedChatServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedChatServiceServer struct{}
+
+func (UnimplementedChatServiceServer) BotStream(*BotStreamRequest, grpc.ServerStreamingServer[BotEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method BotStream not implemented")
+}
+func (UnimplementedChatServiceServer) SendBotMessage(context.Context, *SendBotMessageRequest) (*SendBotMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendBotMessage not implemented")
+}
+func (UnimplementedChatServiceServer) PushUserEvent(context.Context, *PushUserEventRequest) (*PushUserEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushUserEvent not implemented")
+}
+func (UnimplementedChatServiceServer) mustEmbedUnimplementedChatServiceServer() {}
+func (UnimplementedChatServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeChatServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChatServiceServer will
+// result in compilation errors.
+type UnsafeChatServiceServer interface {
+	mustEmbedUnimplementedChatServiceServer()
+}
+
+func RegisterChatServiceServer(s grpc.ServiceRegistrar, srv ChatServiceServer) {
+	// If the following call pancis, it indicates UnimplementedChatServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ChatService_ServiceDesc, srv)
+}
+
+func _ChatService_BotStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BotStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServiceServer).BotStream(m, &grpc.GenericServerStream[BotStreamRequest, BotEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_BotStreamServer = grpc.ServerStreamingServer[BotEvent]
+
+func _ChatService_SendBotMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendBotMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).SendBotMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_SendBotMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).SendBotMessage(ctx, req.(*SendBotMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_PushUserEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushUserEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).PushUserEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_PushUserEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).PushUserEvent(ctx, req.(*PushUserEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ChatService_ServiceDesc is the grpc.ServiceDesc for ChatService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChatService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "chat.ChatService",
+	HandlerType: (*ChatServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendBotMessage",
+			Handler:    _ChatService_SendBotMessage_Handler,
+		},
+		{
+			MethodName: "PushUserEvent",
+			Handler:    _ChatService_PushUserEvent_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BotStream",
+			Handler:       _ChatService_BotStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "services/chat_service/proto/chat.proto",
+}
@@ -22,12 +22,16 @@ func OpenSession(c *gin.Context) {
 		return
 	}
 	message, sessionId, code := services.SessionService.OpenSession(openSessionReq.SendId, openSessionReq.ReceiveId)
+	if code == 0 {
+		// 用户主动打开会话时清零未读数，grpc调用是发消息时创建会话，不清零
+		services.SessionService.ResetUnread(openSessionReq.SendId, openSessionReq.ReceiveId)
+	}
 	JsonBack(c, message, code, sessionId)
 }
 
 // GetUserSessionList 获取用户会话列表
 func GetUserSessionList(c *gin.Context) {
-	var getUserSessionListReq request.OwnlistRequest
+	var getUserSessionListReq request.SessionListRequest
 	if err := c.BindJSON(&getUserSessionListReq); err != nil {
 		zlog.Error(err.Error())
 		c.JSON(http.StatusOK, gin.H{
@@ -36,13 +40,13 @@ func GetUserSessionList(c *gin.Context) {
 		})
 		return
 	}
-	message, sessionList, code := services.SessionService.GetUserSessionList(getUserSessionListReq.OwnerId)
+	message, sessionList, code := services.SessionService.GetUserSessionList(getUserSessionListReq)
 	JsonBack(c, message, code, sessionList)
 }
 
 // GetGroupSessionList 获取群聊会话列表
 func GetGroupSessionList(c *gin.Context) {
-	var getGroupListReq request.OwnlistRequest
+	var getGroupListReq request.SessionListRequest
 	if err := c.BindJSON(&getGroupListReq); err != nil {
 		zlog.Error(err.Error())
 		c.JSON(http.StatusOK, gin.H{
@@ -51,7 +55,7 @@ func GetGroupSessionList(c *gin.Context) {
 		})
 		return
 	}
-	message, groupList, code := services.SessionService.GetGroupSessionList(getGroupListReq.OwnerId)
+	message, groupList, code := services.SessionService.GetGroupSessionList(getGroupListReq)
 	JsonBack(c, message, code, groupList)
 }
 
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/session_service/internal/services"
+)
+
+// MuteSession 设置会话免打扰
+func MuteSession(c *gin.Context) {
+	var req request.MuteSessionRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, code := services.SessionService.MuteSession(req)
+	JsonBack(c, message, code, nil)
+}
+
+// UnmuteSession 取消会话免打扰
+func UnmuteSession(c *gin.Context) {
+	var req request.UnmuteSessionRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, code := services.SessionService.UnmuteSession(req)
+	JsonBack(c, message, code, nil)
+}
+
+// SetDnd 设置全局免打扰时段
+func SetDnd(c *gin.Context) {
+	var req request.SetDndRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, code := services.SessionService.SetDnd(req)
+	JsonBack(c, message, code, nil)
+}
+
+// GetDnd 获取全局免打扰时段
+func GetDnd(c *gin.Context) {
+	var req request.OwnlistRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, code := services.SessionService.GetDnd(req.OwnerId)
+	JsonBack(c, message, code, rsp)
+}
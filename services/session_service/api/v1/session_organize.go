@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/session_service/internal/services"
+)
+
+// PinSession 置顶或取消置顶会话
+func PinSession(c *gin.Context) {
+	var req request.PinSessionRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, code := services.SessionService.PinSession(req)
+	JsonBack(c, message, code, nil)
+}
+
+// ArchiveSession 归档或取消归档会话
+func ArchiveSession(c *gin.Context) {
+	var req request.ArchiveSessionRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, code := services.SessionService.ArchiveSession(req)
+	JsonBack(c, message, code, nil)
+}
+
+// MoveSession 把会话移入或移出分组
+func MoveSession(c *gin.Context) {
+	var req request.MoveSessionRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, code := services.SessionService.MoveSession(req)
+	JsonBack(c, message, code, nil)
+}
+
+// CreateFolder 创建会话分组
+func CreateFolder(c *gin.Context) {
+	var req request.CreateFolderRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, folder, code := services.SessionService.CreateFolder(req)
+	JsonBack(c, message, code, folder)
+}
+
+// RenameFolder 重命名会话分组
+func RenameFolder(c *gin.Context) {
+	var req request.RenameFolderRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, code := services.SessionService.RenameFolder(req)
+	JsonBack(c, message, code, nil)
+}
+
+// DeleteFolder 删除会话分组
+func DeleteFolder(c *gin.Context) {
+	var req request.DeleteFolderRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, code := services.SessionService.DeleteFolder(req)
+	JsonBack(c, message, code, nil)
+}
+
+// GetFolderList 获取会话分组列表
+func GetFolderList(c *gin.Context) {
+	var req request.OwnlistRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, folderList, code := services.SessionService.GetFolderList(req.OwnerId)
+	JsonBack(c, message, code, folderList)
+}
@@ -103,6 +103,22 @@ func main() {
 		}
 	}()
 
+	// 维护所有 chat_service 实例的 rpc 客户端，用于把会话设置和未读数同步到用户在线的设备
+	// 用户的设备可能连在任意实例上，实例增减时按 etcd 中的地址列表更新
+	go func() {
+		for {
+			addr, err := etcd.GetServiceAddr("chat_service")
+			if err != nil {
+				zlog.Warn(fmt.Sprintf("获取 chat_service 地址失败: %v", err))
+			} else if len(addr) == 0 {
+				zlog.Warn("未找到 chat_service 服务，请先启动 chat_service")
+			} else if err := clients.SyncChatClients(addr); err != nil {
+				zlog.Warn(fmt.Sprintf("初始化 chat_service rpc 客户端失败: %v", err))
+			}
+			time.Sleep(time.Second * 10)
+		}
+	}()
+
 		// 测试获取全局用户服务客户端
 	time.Sleep(time.Second * 5)
 	if userClient, err := clients.GetGlobalUserClient(); err == nil {
@@ -123,4 +139,6 @@ func main() {
 			zlog.Info("user rpc客户端已关闭")
 		}
 	}
+	clients.CloseChatClients()
+	zlog.Info("chat rpc客户端已关闭")
 }
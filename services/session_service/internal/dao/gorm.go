@@ -28,6 +28,9 @@ func InitMySQL() {
 	}
 	err = GormDB.AutoMigrate(
 		&model.Session{},
+		&model.SessionMute{},
+		&model.DndSetting{},
+		&model.SessionFolder{},
 	) // 自动迁移，如果没有建表，会自动创建对应的表
 
 	if err != nil {
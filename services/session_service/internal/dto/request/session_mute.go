@@ -0,0 +1,22 @@
+package request
+
+type MuteSessionRequest struct {
+	OwnerId      string `json:"owner_id"`
+	TargetId     string `json:"target_id"`     // 会话对方的用户uuid或群聊uuid
+	Duration     int    `json:"duration"`      // 免打扰时长（分钟），0表示永久
+	AllowMention bool   `json:"allow_mention"` // 被@时仍然提醒
+}
+
+type UnmuteSessionRequest struct {
+	OwnerId  string `json:"owner_id"`
+	TargetId string `json:"target_id"`
+}
+
+type SetDndRequest struct {
+	OwnerId      string `json:"owner_id"`
+	Enabled      bool   `json:"enabled"`
+	StartTime    string `json:"start_time"` // HH:MM
+	EndTime      string `json:"end_time"`   // HH:MM，早于开始时间表示跨天
+	Timezone     string `json:"timezone"`   // 如Asia/Shanghai，为空时使用默认时区
+	AllowMention bool   `json:"allow_mention"`
+}
@@ -0,0 +1,41 @@
+package request
+
+type SessionListRequest struct {
+	OwnerId  string `json:"owner_id"`
+	Archived bool   `json:"archived"`  // true时只返回已归档的会话，否则不返回已归档的会话
+	FolderId string `json:"folder_id"` // 不为空时只返回该分组的会话
+}
+
+type PinSessionRequest struct {
+	OwnerId   string `json:"owner_id"`
+	SessionId string `json:"session_id"`
+	Pinned    bool   `json:"pinned"` // false为取消置顶
+}
+
+type ArchiveSessionRequest struct {
+	OwnerId   string `json:"owner_id"`
+	SessionId string `json:"session_id"`
+	Archived  bool   `json:"archived"` // false为取消归档
+}
+
+type CreateFolderRequest struct {
+	OwnerId string `json:"owner_id"`
+	Name    string `json:"name"`
+}
+
+type RenameFolderRequest struct {
+	OwnerId  string `json:"owner_id"`
+	FolderId string `json:"folder_id"`
+	Name     string `json:"name"`
+}
+
+type DeleteFolderRequest struct {
+	OwnerId  string `json:"owner_id"`
+	FolderId string `json:"folder_id"`
+}
+
+type MoveSessionRequest struct {
+	OwnerId   string `json:"owner_id"`
+	SessionId string `json:"session_id"`
+	FolderId  string `json:"folder_id"` // 为空表示移出分组
+}
@@ -0,0 +1,7 @@
+package respond
+
+// SessionDraftRespond 会话列表中附带的草稿
+type SessionDraftRespond struct {
+	Content   string `json:"content"`
+	UpdatedAt string `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package respond
+
+type SessionFolderRespond struct {
+	FolderId  string `json:"folder_id"`
+	Name      string `json:"name"`
+	Sort      int    `json:"sort"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SessionSyncEventRespond 会话置顶、归档、分组变化时推送给用户在线的设备
+type SessionSyncEventRespond struct {
+	Event     string `json:"event"`
+	Action    string `json:"action"`
+	SessionId string `json:"session_id,omitempty"`
+	FolderId  string `json:"folder_id,omitempty"`
+	Name      string `json:"name,omitempty"` // 创建、重命名分组时的名称
+}
@@ -0,0 +1,15 @@
+package respond
+
+type SessionMuteRespond struct {
+	MutedUntil   string `json:"muted_until,omitempty"` // 为空表示永久
+	AllowMention bool   `json:"allow_mention"`
+}
+
+type DndRespond struct {
+	Enabled      bool   `json:"enabled"`
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+	Timezone     string `json:"timezone"`
+	AllowMention bool   `json:"allow_mention"`
+	Active       bool   `json:"active"` // 当前是否处于免打扰时段
+}
@@ -0,0 +1,9 @@
+package respond
+
+// SessionPinRespond 会话列表中附带的置顶消息摘要
+type SessionPinRespond struct {
+	MessageId string `json:"message_id"`
+	Snippet   string `json:"snippet"`
+	PinnedBy  string `json:"pinned_by"`
+	PinnedAt  string `json:"pinned_at"`
+}
@@ -0,0 +1,10 @@
+package respond
+
+// UnreadEventRespond 会话未读数变化时推送给用户在线的设备，与chat_service推送的unread_updated事件结构相同
+type UnreadEventRespond struct {
+	Event    string `json:"event"`
+	TargetId string `json:"target_id"` // 会话对方的用户uuid或群聊uuid
+	Unread   int    `json:"unread"`
+	Total    int    `json:"total"`  // 所有会话的未读数之和
+	Silent   bool   `json:"silent"` // 客户端只更新角标，不弹提醒
+}
@@ -1,8 +1,17 @@
 package respond
 
 type UserSessionListRespond struct {
-	SessionId string `json:"session_id"`
-	Avatar    string `json:"avatar"`
-	UserId    string `json:"user_id"`
-	Username  string `json:"user_name"`
+	SessionId     string               `json:"session_id"`
+	Avatar        string               `json:"avatar"`
+	UserId        string               `json:"user_id"`
+	Username      string               `json:"user_name"`
+	LastMessage   string               `json:"last_message"`    // 最新消息的摘要
+	LastMessageAt string               `json:"last_message_at"` // 最新消息时间，没有消息时为空
+	Pinned        bool                 `json:"pinned"`
+	Archived      bool                 `json:"archived"`
+	FolderId      string               `json:"folder_id,omitempty"` // 所属分组，为空表示未分组
+	Pins          []SessionPinRespond  `json:"pins"`                // 不缓存，每次查询时填充
+	Draft         *SessionDraftRespond `json:"draft,omitempty"`     // 不缓存，每次查询时填充
+	Unread        int                  `json:"unread"`              // 不缓存，每次查询时填充
+	Mute          *SessionMuteRespond  `json:"mute,omitempty"`      // 会话免打扰，不缓存，每次查询时填充
 }
@@ -2,6 +2,7 @@ package grpc_server
 
 import (
 	"context"
+	"time"
 
 	"github.com/puoxiu/gogochat/services/session_service/internal/services"
 	session "github.com/puoxiu/gogochat/services/session_service/proto"
@@ -37,9 +38,26 @@ func (s *SessionGrpcServer) CreateSessionIfNotExist( ctx context.Context, req *s
 		}, nil
 	}
 
-	msg, _, code := services.SessionService.OpenSession(req.SendId, req.ReceiveId)
+	msg, sessionId, code := services.SessionService.OpenSession(req.SendId, req.ReceiveId)
 
 	return &session.CreateSessionResponse{
+		Code:      int32(code),
+		Message:   msg,
+		SessionId: sessionId,
+	}, nil
+}
+
+func (s *SessionGrpcServer) UpdateLastMessage(ctx context.Context, req *session.UpdateLastMessageRequest) (*session.UpdateLastMessageResponse, error) {
+	if req.SendId == "" || req.ReceiveId == "" {
+		return &session.UpdateLastMessageResponse{
+			Code:    -1,
+			Message: "参数错误：发送者或接收者ID不能为空",
+		}, nil
+	}
+
+	msg, code := services.SessionService.UpdateLastMessage(req.SendId, req.ReceiveId, req.LastMessage, time.UnixMilli(req.LastMessageAt))
+
+	return &session.UpdateLastMessageResponse{
 		Code:    int32(code),
 		Message: msg,
 	}, nil
@@ -21,4 +21,15 @@ func init() {
 	GE.POST("/session/getGroupSessionList", v1.GetGroupSessionList)
 	GE.POST("/session/deleteSession", v1.DeleteSession)
 	GE.POST("/session/checkOpenSessionAllowed", v1.CheckOpenSessionAllowed)
+	GE.POST("/session/muteSession", v1.MuteSession)
+	GE.POST("/session/unmuteSession", v1.UnmuteSession)
+	GE.POST("/session/setDnd", v1.SetDnd)
+	GE.POST("/session/getDnd", v1.GetDnd)
+	GE.POST("/session/pinSession", v1.PinSession)
+	GE.POST("/session/archiveSession", v1.ArchiveSession)
+	GE.POST("/session/moveSession", v1.MoveSession)
+	GE.POST("/session/createFolder", v1.CreateFolder)
+	GE.POST("/session/renameFolder", v1.RenameFolder)
+	GE.POST("/session/deleteFolder", v1.DeleteFolder)
+	GE.POST("/session/getFolderList", v1.GetFolderList)
 }
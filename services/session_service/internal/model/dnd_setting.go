@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// DndSetting 全局免打扰时段，每个用户一条
+type DndSetting struct {
+	Id           int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	UserId       string    `gorm:"column:user_id;uniqueIndex;type:char(20);not null;comment:用户uuid"`
+	Enabled      bool      `gorm:"column:enabled;not null;default:false;comment:是否开启"`
+	StartTime    string    `gorm:"column:start_time;type:char(5);not null;comment:开始时间，HH:MM"`
+	EndTime      string    `gorm:"column:end_time;type:char(5);not null;comment:结束时间，HH:MM，早于开始时间表示跨天"`
+	Timezone     string    `gorm:"column:timezone;type:varchar(64);not null;comment:用户时区"`
+	AllowMention bool      `gorm:"column:allow_mention;not null;default:false;comment:被@时是否仍然提醒"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;type:datetime;comment:更新时间"`
+}
+
+func (DndSetting) TableName() string {
+	return "dnd_setting"
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Draft 会话草稿，表由chat_service维护，这里只读
+type Draft struct {
+	Id        int64     `gorm:"column:id;primaryKey"`
+	UserId    string    `gorm:"column:user_id"`
+	TargetId  string    `gorm:"column:target_id"`
+	Content   string    `gorm:"column:content"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (Draft) TableName() string {
+	return "draft"
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// MessagePin 会话中的置顶消息，表由chat_service维护，这里只读
+type MessagePin struct {
+	Id             int64     `gorm:"column:id;primaryKey"`
+	ConversationId string    `gorm:"column:conversation_id"`
+	MessageId      string    `gorm:"column:message_id"`
+	PinnedBy       string    `gorm:"column:pinned_by"`
+	Snippet        string    `gorm:"column:snippet"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
+
+func (MessagePin) TableName() string {
+	return "message_pin"
+}
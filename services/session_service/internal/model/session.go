@@ -15,6 +15,9 @@ type Session struct {
 	Avatar        string         `gorm:"column:avatar;type:char(255);default:default_avatar.png;not null;comment:头像"`
 	LastMessage   string         `gorm:"column:last_message;type:TEXT;comment:最新的消息"`
 	LastMessageAt sql.NullTime    `gorm:"column:last_message_at;type:datetime;comment:最近接收时间"`
+	PinnedAt      sql.NullTime   `gorm:"column:pinned_at;type:datetime;comment:置顶时间，为空表示未置顶"`
+	ArchivedAt    sql.NullTime   `gorm:"column:archived_at;type:datetime;comment:归档时间，有新消息时自动取消归档"`
+	FolderId      string         `gorm:"column:folder_id;Index;type:char(20);not null;default:'';comment:所属分组uuid，为空表示未分组"`
 	CreatedAt     time.Time      `gorm:"column:created_at;Index;type:datetime;comment:创建时间"`
 	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at;Index;type:datetime;comment:删除时间"`
 }
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// SessionFolder 用户自定义的会话分组，会话通过folder_id归入分组
+type SessionFolder struct {
+	Id        int64     `gorm:"column:id;primaryKey;comment:自增id"`
+	Uuid      string    `gorm:"column:uuid;uniqueIndex;type:char(20);comment:分组uuid"`
+	OwnerId   string    `gorm:"column:owner_id;Index;type:char(20);not null;comment:所属用户uuid"`
+	Name      string    `gorm:"column:name;type:varchar(20);not null;comment:分组名称"`
+	Sort      int       `gorm:"column:sort;not null;default:0;comment:排序，越小越靠前"`
+	CreatedAt time.Time `gorm:"column:created_at;type:datetime;comment:创建时间"`
+}
+
+func (SessionFolder) TableName() string {
+	return "session_folder"
+}
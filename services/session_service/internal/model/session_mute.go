@@ -0,0 +1,20 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SessionMute 会话免打扰，消息照常投递，只是不再提醒
+type SessionMute struct {
+	Id           int64        `gorm:"column:id;primaryKey;comment:自增id"`
+	UserId       string       `gorm:"column:user_id;uniqueIndex:idx_user_target;type:char(20);not null;comment:设置人uuid"`
+	TargetId     string       `gorm:"column:target_id;uniqueIndex:idx_user_target;type:char(20);not null;comment:会话对方的用户uuid或群聊uuid"`
+	MutedUntil   sql.NullTime `gorm:"column:muted_until;type:datetime;comment:免打扰截止时间，为空表示永久"`
+	AllowMention bool         `gorm:"column:allow_mention;not null;default:false;comment:被@时是否仍然提醒"`
+	CreatedAt    time.Time    `gorm:"column:created_at;type:datetime;comment:创建时间"`
+}
+
+func (SessionMute) TableName() string {
+	return "session_mute"
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// UnreadCounter 会话未读数，表由chat_service维护，这里只读
+type UnreadCounter struct {
+	Id        int64     `gorm:"column:id;primaryKey"`
+	UserId    string    `gorm:"column:user_id"`
+	TargetId  string    `gorm:"column:target_id"`
+	Count     int       `gorm:"column:count"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (UnreadCounter) TableName() string {
+	return "unread_counter"
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/session_service/internal/model"
+)
+
+// sessionActivityOrder 会话列表按最近活跃排序，没有消息的会话按创建时间参与排序
+const sessionActivityOrder = "COALESCE(last_message_at, created_at) DESC"
+
+// sessionListOrder 置顶的会话排在最前，按置顶时间倒序，其余按最近活跃排序
+const sessionListOrder = "pinned_at IS NULL, pinned_at DESC, " + sessionActivityOrder
+
+// formatLastMessageAt 格式化会话的最新消息时间，没有消息时为空
+func formatLastMessageAt(session *model.Session) string {
+	if !session.LastMessageAt.Valid {
+		return ""
+	}
+	return session.LastMessageAt.Time.Format("2006-01-02 15:04:05")
+}
+
+// UpdateLastMessage 更新会话的最新消息 -rpc 调用
+// 单聊更新双方的会话，群聊更新所有成员的会话；消息乱序到达时不会覆盖更新的消息
+// 已归档的会话有新消息时取消归档
+func (s *sessionService) UpdateLastMessage(sendId, receiveId, lastMessage string, lastMessageAt time.Time) (string, int) {
+	query := dao.GormDB.Model(&model.Session{})
+	if receiveId[0] == 'G' {
+		query = query.Where("receive_id = ?", receiveId)
+	} else {
+		query = query.Where("(send_id = ? AND receive_id = ?) OR (send_id = ? AND receive_id = ?)", sendId, receiveId, receiveId, sendId)
+	}
+	res := query.Where("last_message_at IS NULL OR last_message_at <= ?", lastMessageAt).
+		Updates(map[string]interface{}{
+			"last_message":    lastMessage,
+			"last_message_at": lastMessageAt,
+			"archived_at":     nil,
+		})
+	if res.Error != nil {
+		zlog.Error(fmt.Sprintf("更新会话最新消息数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res.RowsAffected == 0 {
+		return "没有需要更新的会话", 0
+	}
+	if receiveId[0] != 'G' {
+		for _, ownerId := range []string{sendId, receiveId} {
+			if err := cache.GetGlobalCache().DelKeyIfExists("session_list_" + ownerId); err != nil {
+				zlog.Warn(fmt.Sprintf("删除缓存会话列表失败: %s", err.Error()))
+			}
+		}
+		return "更新成功", 0
+	}
+	var ownerIds []string
+	if res := dao.GormDB.Model(&model.Session{}).Where("receive_id = ?", receiveId).Pluck("send_id", &ownerIds); res.Error != nil {
+		zlog.Error(fmt.Sprintf("查询群聊会话数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, -1
+	}
+	for _, ownerId := range ownerIds {
+		if err := cache.GetGlobalCache().DelKeyIfExists("group_session_list_" + ownerId); err != nil {
+			zlog.Warn(fmt.Sprintf("删除缓存群聊会话列表失败: %s", err.Error()))
+		}
+	}
+	return "更新成功", 0
+}
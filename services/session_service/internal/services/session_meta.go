@@ -0,0 +1,67 @@
+package services
+
+import (
+	"github.com/puoxiu/gogochat/pkg/conversation"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/session_service/internal/model"
+)
+
+// loadSessionDrafts 批量查询用户在各个会话中的草稿，key为会话对方的uuid
+func loadSessionDrafts(ownerId string, targetIds []string) map[string]*respond.SessionDraftRespond {
+	draftMap := make(map[string]*respond.SessionDraftRespond)
+	if len(targetIds) == 0 {
+		return draftMap
+	}
+	var draftList []model.Draft
+	if res := dao.GormDB.Where("user_id = ? AND target_id IN ?", ownerId, targetIds).Find(&draftList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return draftMap
+	}
+	for _, draft := range draftList {
+		draftMap[draft.TargetId] = &respond.SessionDraftRespond{
+			Content:   draft.Content,
+			UpdatedAt: draft.UpdatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return draftMap
+}
+
+// attachUserSessionMeta 为单聊会话列表填充置顶消息、草稿、未读数和免打扰
+func attachUserSessionMeta(ownerId string, sessionList []respond.UserSessionListRespond) {
+	conversationIds := make([]string, 0, len(sessionList))
+	targetIds := make([]string, 0, len(sessionList))
+	for _, session := range sessionList {
+		conversationIds = append(conversationIds, conversation.Id(ownerId, session.UserId))
+		targetIds = append(targetIds, session.UserId)
+	}
+	pinMap := loadSessionPins(conversationIds)
+	draftMap := loadSessionDrafts(ownerId, targetIds)
+	unreadMap := loadSessionUnread(ownerId)
+	muteMap := loadSessionMutes(ownerId)
+	for i := range sessionList {
+		sessionList[i].Pins = pinMap[conversationIds[i]]
+		sessionList[i].Draft = draftMap[targetIds[i]]
+		sessionList[i].Unread = unreadMap[targetIds[i]]
+		sessionList[i].Mute = muteMap[targetIds[i]]
+	}
+}
+
+// attachGroupSessionMeta 为群聊会话列表填充置顶消息、草稿、未读数和免打扰
+func attachGroupSessionMeta(ownerId string, sessionList []respond.GroupSessionListRespond) {
+	groupIds := make([]string, 0, len(sessionList))
+	for _, session := range sessionList {
+		groupIds = append(groupIds, session.GroupId)
+	}
+	pinMap := loadSessionPins(groupIds)
+	draftMap := loadSessionDrafts(ownerId, groupIds)
+	unreadMap := loadSessionUnread(ownerId)
+	muteMap := loadSessionMutes(ownerId)
+	for i := range sessionList {
+		sessionList[i].Pins = pinMap[groupIds[i]]
+		sessionList[i].Draft = draftMap[groupIds[i]]
+		sessionList[i].Unread = unreadMap[groupIds[i]]
+		sessionList[i].Mute = muteMap[groupIds[i]]
+	}
+}
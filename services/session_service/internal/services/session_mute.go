@@ -0,0 +1,173 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/puoxiu/gogochat/common/notify"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/session_service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// loadNotifySettings 从数据库读取用户的会话免打扰和全局免打扰时段
+func loadNotifySettings(userId string) (*notify.Settings, error) {
+	var muteList []model.SessionMute
+	if res := dao.GormDB.Where("user_id = ?", userId).Find(&muteList); res.Error != nil {
+		return nil, res.Error
+	}
+	settings := &notify.Settings{Mutes: make(map[string]notify.Mute, len(muteList))}
+	for _, mute := range muteList {
+		m := notify.Mute{
+			TargetId:     mute.TargetId,
+			AllowMention: mute.AllowMention,
+		}
+		if mute.MutedUntil.Valid {
+			mutedUntil := mute.MutedUntil.Time
+			m.MutedUntil = &mutedUntil
+		}
+		settings.Mutes[mute.TargetId] = m
+	}
+	var dnd model.DndSetting
+	if res := dao.GormDB.Where("user_id = ?", userId).First(&dnd); res.Error != nil {
+		if !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, res.Error
+		}
+		return settings, nil
+	}
+	settings.Dnd = &notify.Dnd{
+		Enabled:      dnd.Enabled,
+		StartTime:    dnd.StartTime,
+		EndTime:      dnd.EndTime,
+		Timezone:     dnd.Timezone,
+		AllowMention: dnd.AllowMention,
+	}
+	return settings, nil
+}
+
+// loadSessionMutes 查询用户当前生效的会话免打扰，key为会话对方的uuid，出错时按没有免打扰处理
+func loadSessionMutes(ownerId string) map[string]*respond.SessionMuteRespond {
+	muteMap := make(map[string]*respond.SessionMuteRespond)
+	settings, err := notify.Load(ownerId, loadNotifySettings)
+	if err != nil {
+		zlog.Error(err.Error())
+		return muteMap
+	}
+	now := time.Now()
+	for targetId := range settings.Mutes {
+		mute, ok := settings.MuteOf(targetId, now)
+		if !ok {
+			continue
+		}
+		rsp := &respond.SessionMuteRespond{AllowMention: mute.AllowMention}
+		if mute.MutedUntil != nil {
+			rsp.MutedUntil = mute.MutedUntil.Format("2006-01-02 15:04:05")
+		}
+		muteMap[targetId] = rsp
+	}
+	return muteMap
+}
+
+// invalidateNotifySettings 免打扰设置变化后删除缓存
+func invalidateNotifySettings(userId string) {
+	if err := notify.Invalidate(userId); err != nil {
+		zlog.Warn(fmt.Sprintf("删除免打扰设置缓存失败: %s", err.Error()))
+	}
+}
+
+// MuteSession 设置会话免打扰，重复设置时覆盖时长和@提醒
+func (s *sessionService) MuteSession(req request.MuteSessionRequest) (string, int) {
+	if req.TargetId == "" || (req.TargetId[0] != 'U' && req.TargetId[0] != 'G') || req.TargetId == req.OwnerId {
+		return "会话不合法", -2
+	}
+	if req.Duration < 0 {
+		return "免打扰时长不合法", -2
+	}
+	mute := model.SessionMute{
+		UserId:       req.OwnerId,
+		TargetId:     req.TargetId,
+		AllowMention: req.AllowMention,
+		CreatedAt:    time.Now(),
+	}
+	if req.Duration > 0 {
+		mute.MutedUntil = sql.NullTime{Time: mute.CreatedAt.Add(time.Duration(req.Duration) * time.Minute), Valid: true}
+	}
+	if res := dao.GormDB.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"muted_until", "allow_mention", "created_at"}),
+	}).Create(&mute); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	invalidateNotifySettings(req.OwnerId)
+	return "已开启免打扰", 0
+}
+
+// UnmuteSession 取消会话免打扰
+func (s *sessionService) UnmuteSession(req request.UnmuteSessionRequest) (string, int) {
+	if res := dao.GormDB.Where("user_id = ? AND target_id = ?", req.OwnerId, req.TargetId).Delete(&model.SessionMute{}); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	invalidateNotifySettings(req.OwnerId)
+	return "已关闭免打扰", 0
+}
+
+// SetDnd 设置全局免打扰时段
+func (s *sessionService) SetDnd(req request.SetDndRequest) (string, int) {
+	if _, err := notify.ParseClock(req.StartTime); err != nil {
+		return "开始时间格式应为HH:MM", -2
+	}
+	if _, err := notify.ParseClock(req.EndTime); err != nil {
+		return "结束时间格式应为HH:MM", -2
+	}
+	if req.Timezone == "" {
+		req.Timezone = notify.DefaultTimezone
+	}
+	if _, err := notify.LoadLocation(req.Timezone); err != nil {
+		return "时区不合法", -2
+	}
+	dnd := model.DndSetting{
+		UserId:       req.OwnerId,
+		Enabled:      req.Enabled,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Timezone:     req.Timezone,
+		AllowMention: req.AllowMention,
+		UpdatedAt:    time.Now(),
+	}
+	if res := dao.GormDB.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "start_time", "end_time", "timezone", "allow_mention", "updated_at"}),
+	}).Create(&dnd); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	invalidateNotifySettings(req.OwnerId)
+	return "设置免打扰时段成功", 0
+}
+
+// GetDnd 获取全局免打扰时段，没有设置过时返回未开启
+func (s *sessionService) GetDnd(ownerId string) (string, *respond.DndRespond, int) {
+	settings, err := notify.Load(ownerId, loadNotifySettings)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if settings.Dnd == nil {
+		return "获取免打扰时段成功", &respond.DndRespond{Timezone: notify.DefaultTimezone}, 0
+	}
+	return "获取免打扰时段成功", &respond.DndRespond{
+		Enabled:      settings.Dnd.Enabled,
+		StartTime:    settings.Dnd.StartTime,
+		EndTime:      settings.Dnd.EndTime,
+		Timezone:     settings.Dnd.Timezone,
+		AllowMention: settings.Dnd.AllowMention,
+		Active:       settings.Dnd.Active(time.Now()),
+	}, 0
+}
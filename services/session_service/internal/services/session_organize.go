@@ -0,0 +1,304 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/common/clients"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/random"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/session_service/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	maxPinnedSessions = 10 // 每个用户最多置顶的会话数
+	maxSessionFolders = 20 // 每个用户最多创建的分组数
+	maxFolderNameLen  = 20 // 分组名称最大字符数
+)
+
+const EventSessionSync = "session_sync" // 会话置顶、归档、分组变化，推送给用户在线的设备
+
+const (
+	SessionSyncPin          = "pin"
+	SessionSyncUnpin        = "unpin"
+	SessionSyncArchive      = "archive"
+	SessionSyncUnarchive    = "unarchive"
+	SessionSyncMove         = "move"
+	SessionSyncFolderCreate = "folder_create"
+	SessionSyncFolderRename = "folder_rename"
+	SessionSyncFolderDelete = "folder_delete"
+)
+
+// filterUserSessionList 按归档状态和分组筛选单聊会话列表
+func filterUserSessionList(sessionList []respond.UserSessionListRespond, req request.SessionListRequest) []respond.UserSessionListRespond {
+	var rsp []respond.UserSessionListRespond
+	for _, session := range sessionList {
+		if session.Archived != req.Archived {
+			continue
+		}
+		if req.FolderId != "" && session.FolderId != req.FolderId {
+			continue
+		}
+		rsp = append(rsp, session)
+	}
+	return rsp
+}
+
+// filterGroupSessionList 按归档状态和分组筛选群聊会话列表
+func filterGroupSessionList(sessionList []respond.GroupSessionListRespond, req request.SessionListRequest) []respond.GroupSessionListRespond {
+	var rsp []respond.GroupSessionListRespond
+	for _, session := range sessionList {
+		if session.Archived != req.Archived {
+			continue
+		}
+		if req.FolderId != "" && session.FolderId != req.FolderId {
+			continue
+		}
+		rsp = append(rsp, session)
+	}
+	return rsp
+}
+
+// findOwnSession 查询用户自己的会话
+func findOwnSession(ownerId, sessionId string) (*model.Session, string, int) {
+	var session model.Session
+	if res := dao.GormDB.Where("uuid = ? AND send_id = ?", sessionId, ownerId).First(&session); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, "该会话不存在", -2
+		}
+		zlog.Error(fmt.Sprintf("查询会话数据库错误: %s", res.Error.Error()))
+		return nil, constants.SYSTEM_ERROR, -1
+	}
+	return &session, "", 0
+}
+
+// invalidateSessionListCache 会话的置顶、归档、分组变化后删除列表缓存
+func invalidateSessionListCache(ownerId string) {
+	if err := cache.GetGlobalCache().DelKeyIfExists("session_list_" + ownerId); err != nil {
+		zlog.Warn(fmt.Sprintf("删除缓存会话列表失败: %s", err.Error()))
+	}
+	if err := cache.GetGlobalCache().DelKeyIfExists("group_session_list_" + ownerId); err != nil {
+		zlog.Warn(fmt.Sprintf("删除缓存群聊会话列表失败: %s", err.Error()))
+	}
+}
+
+// pushSessionSync 通过chat_service把变化推送给用户在线的设备，失败时只记录日志，设备重新拉取列表即可同步
+func pushSessionSync(ownerId string, event respond.SessionSyncEventRespond) {
+	event.Event = EventSessionSync
+	if err := pushUserEvent(ownerId, event); err != nil {
+		zlog.Warn(fmt.Sprintf("同步会话设置失败, owner_id: %s, action: %s: %s", ownerId, event.Action, err.Error()))
+	}
+}
+
+// pushUserEvent 通过所有chat_service实例把事件推送给用户在线的设备，设备可能连在不同的实例上
+func pushUserEvent(ownerId string, event interface{}) error {
+	eventByte, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return clients.BroadcastUserEvent(ownerId, string(eventByte))
+}
+
+// PinSession 置顶或取消置顶会话
+func (s *sessionService) PinSession(req request.PinSessionRequest) (string, int) {
+	session, msg, ret := findOwnSession(req.OwnerId, req.SessionId)
+	if ret != 0 {
+		return msg, ret
+	}
+	if session.PinnedAt.Valid == req.Pinned {
+		return "操作成功", 0
+	}
+	pinnedAt := sql.NullTime{}
+	action := SessionSyncUnpin
+	if req.Pinned {
+		var pinnedCount int64
+		if res := dao.GormDB.Model(&model.Session{}).Where("send_id = ? AND pinned_at IS NOT NULL", req.OwnerId).Count(&pinnedCount); res.Error != nil {
+			zlog.Error(fmt.Sprintf("查询置顶会话数据库错误: %s", res.Error.Error()))
+			return constants.SYSTEM_ERROR, -1
+		}
+		if pinnedCount >= maxPinnedSessions {
+			return fmt.Sprintf("最多置顶%d个会话", maxPinnedSessions), -2
+		}
+		pinnedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		action = SessionSyncPin
+	}
+	if res := dao.GormDB.Model(session).Update("pinned_at", pinnedAt); res.Error != nil {
+		zlog.Error(fmt.Sprintf("置顶会话数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, -1
+	}
+	invalidateSessionListCache(req.OwnerId)
+	pushSessionSync(req.OwnerId, respond.SessionSyncEventRespond{Action: action, SessionId: session.Uuid})
+	return "操作成功", 0
+}
+
+// ArchiveSession 归档或取消归档会话，归档的会话收到新消息时自动取消归档
+func (s *sessionService) ArchiveSession(req request.ArchiveSessionRequest) (string, int) {
+	session, msg, ret := findOwnSession(req.OwnerId, req.SessionId)
+	if ret != 0 {
+		return msg, ret
+	}
+	if session.ArchivedAt.Valid == req.Archived {
+		return "操作成功", 0
+	}
+	archivedAt := sql.NullTime{}
+	action := SessionSyncUnarchive
+	if req.Archived {
+		archivedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		action = SessionSyncArchive
+	}
+	if res := dao.GormDB.Model(session).Update("archived_at", archivedAt); res.Error != nil {
+		zlog.Error(fmt.Sprintf("归档会话数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, -1
+	}
+	invalidateSessionListCache(req.OwnerId)
+	pushSessionSync(req.OwnerId, respond.SessionSyncEventRespond{Action: action, SessionId: session.Uuid})
+	return "操作成功", 0
+}
+
+// MoveSession 把会话移入分组，folder_id为空时移出分组
+func (s *sessionService) MoveSession(req request.MoveSessionRequest) (string, int) {
+	session, msg, ret := findOwnSession(req.OwnerId, req.SessionId)
+	if ret != 0 {
+		return msg, ret
+	}
+	if req.FolderId != "" {
+		var folder model.SessionFolder
+		if res := dao.GormDB.Where("uuid = ? AND owner_id = ?", req.FolderId, req.OwnerId).First(&folder); res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				return "分组不存在", -2
+			}
+			zlog.Error(fmt.Sprintf("查询分组数据库错误: %s", res.Error.Error()))
+			return constants.SYSTEM_ERROR, -1
+		}
+	}
+	if session.FolderId == req.FolderId {
+		return "操作成功", 0
+	}
+	if res := dao.GormDB.Model(session).Update("folder_id", req.FolderId); res.Error != nil {
+		zlog.Error(fmt.Sprintf("移动会话数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, -1
+	}
+	invalidateSessionListCache(req.OwnerId)
+	pushSessionSync(req.OwnerId, respond.SessionSyncEventRespond{Action: SessionSyncMove, SessionId: session.Uuid, FolderId: req.FolderId})
+	return "操作成功", 0
+}
+
+// checkFolderName 校验分组名称
+func checkFolderName(name string) (string, bool) {
+	if name == "" {
+		return "分组名称不能为空", false
+	}
+	if utf8.RuneCountInString(name) > maxFolderNameLen {
+		return fmt.Sprintf("分组名称不能超过%d个字", maxFolderNameLen), false
+	}
+	return "", true
+}
+
+// CreateFolder 创建会话分组，新分组排在最后
+func (s *sessionService) CreateFolder(req request.CreateFolderRequest) (string, *respond.SessionFolderRespond, int) {
+	if msg, ok := checkFolderName(req.Name); !ok {
+		return msg, nil, -2
+	}
+	var folderCount int64
+	if res := dao.GormDB.Model(&model.SessionFolder{}).Where("owner_id = ?", req.OwnerId).Count(&folderCount); res.Error != nil {
+		zlog.Error(fmt.Sprintf("查询分组数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if folderCount >= maxSessionFolders {
+		return fmt.Sprintf("最多创建%d个分组", maxSessionFolders), nil, -2
+	}
+	folder := model.SessionFolder{
+		Uuid:      fmt.Sprintf("D%s", random.GetNowAndLenRandomString(11)),
+		OwnerId:   req.OwnerId,
+		Name:      req.Name,
+		Sort:      int(folderCount),
+		CreatedAt: time.Now(),
+	}
+	if res := dao.GormDB.Create(&folder); res.Error != nil {
+		zlog.Error(fmt.Sprintf("创建分组数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	pushSessionSync(req.OwnerId, respond.SessionSyncEventRespond{Action: SessionSyncFolderCreate, FolderId: folder.Uuid, Name: folder.Name})
+	return "创建分组成功", &respond.SessionFolderRespond{
+		FolderId:  folder.Uuid,
+		Name:      folder.Name,
+		Sort:      folder.Sort,
+		CreatedAt: folder.CreatedAt.Format("2006-01-02 15:04:05"),
+	}, 0
+}
+
+// RenameFolder 重命名会话分组
+func (s *sessionService) RenameFolder(req request.RenameFolderRequest) (string, int) {
+	if msg, ok := checkFolderName(req.Name); !ok {
+		return msg, -2
+	}
+	var folder model.SessionFolder
+	if res := dao.GormDB.Where("uuid = ? AND owner_id = ?", req.FolderId, req.OwnerId).First(&folder); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "分组不存在", -2
+		}
+		zlog.Error(fmt.Sprintf("查询分组数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res := dao.GormDB.Model(&folder).Update("name", req.Name); res.Error != nil {
+		zlog.Error(fmt.Sprintf("重命名分组数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, -1
+	}
+	pushSessionSync(req.OwnerId, respond.SessionSyncEventRespond{Action: SessionSyncFolderRename, FolderId: req.FolderId, Name: req.Name})
+	return "重命名分组成功", 0
+}
+
+// DeleteFolder 删除会话分组，分组中的会话回到未分组
+func (s *sessionService) DeleteFolder(req request.DeleteFolderRequest) (string, int) {
+	var deleted int64
+	err := dao.GormDB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("uuid = ? AND owner_id = ?", req.FolderId, req.OwnerId).Delete(&model.SessionFolder{})
+		if res.Error != nil {
+			return res.Error
+		}
+		deleted = res.RowsAffected
+		if deleted == 0 {
+			return nil
+		}
+		return tx.Model(&model.Session{}).Where("send_id = ? AND folder_id = ?", req.OwnerId, req.FolderId).Update("folder_id", "").Error
+	})
+	if err != nil {
+		zlog.Error(fmt.Sprintf("删除分组数据库错误: %s", err.Error()))
+		return constants.SYSTEM_ERROR, -1
+	}
+	if deleted == 0 {
+		return "分组不存在", -2
+	}
+	invalidateSessionListCache(req.OwnerId)
+	pushSessionSync(req.OwnerId, respond.SessionSyncEventRespond{Action: SessionSyncFolderDelete, FolderId: req.FolderId})
+	return "删除分组成功", 0
+}
+
+// GetFolderList 获取用户的会话分组
+func (s *sessionService) GetFolderList(ownerId string) (string, []respond.SessionFolderRespond, int) {
+	var folderList []model.SessionFolder
+	if res := dao.GormDB.Where("owner_id = ?", ownerId).Order("sort ASC, created_at ASC").Find(&folderList); res.Error != nil {
+		zlog.Error(fmt.Sprintf("查询分组数据库错误: %s", res.Error.Error()))
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rsp := make([]respond.SessionFolderRespond, 0, len(folderList))
+	for _, folder := range folderList {
+		rsp = append(rsp, respond.SessionFolderRespond{
+			FolderId:  folder.Uuid,
+			Name:      folder.Name,
+			Sort:      folder.Sort,
+			CreatedAt: folder.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return "获取分组成功", rsp, 0
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/session_service/internal/model"
+)
+
+// loadSessionPins 批量查询会话的置顶消息，按置顶时间倒序，key为会话标识
+// 置顶变化频繁且影响所有参与者，不随会话列表缓存，查询失败时只记录日志
+func loadSessionPins(conversationIds []string) map[string][]respond.SessionPinRespond {
+	pinMap := make(map[string][]respond.SessionPinRespond)
+	if len(conversationIds) == 0 {
+		return pinMap
+	}
+	var pinList []model.MessagePin
+	if res := dao.GormDB.Where("conversation_id IN ?", conversationIds).Order("created_at DESC").Find(&pinList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return pinMap
+	}
+	for _, pin := range pinList {
+		pinMap[pin.ConversationId] = append(pinMap[pin.ConversationId], respond.SessionPinRespond{
+			MessageId: pin.MessageId,
+			Snippet:   pin.Snippet,
+			PinnedBy:  pin.PinnedBy,
+			PinnedAt:  pin.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return pinMap
+}
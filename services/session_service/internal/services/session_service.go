@@ -189,13 +189,14 @@ func (s *sessionService) OpenSession(sendId, receiveId string) (string, string,
 	return "打开会话成功", session.Uuid, 0
 }
 
-// GetUserSessionList 获取用户会话列表 -✅
-func (s *sessionService) GetUserSessionList(ownerId string) (string, []respond.UserSessionListRespond, int) {
+// GetUserSessionList 获取用户会话列表，缓存完整列表，按归档状态和分组筛选后返回 -✅
+func (s *sessionService) GetUserSessionList(req request.SessionListRequest) (string, []respond.UserSessionListRespond, int) {
+	ownerId := req.OwnerId
 	rspString, err := cache.GetGlobalCache().GetKeyNilIsErr("session_list_" + ownerId)
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			var sessionList []model.Session
-			if res := dao.GormDB.Order("created_at DESC").Where("send_id = ?", ownerId).Find(&sessionList); res.Error != nil {
+			if res := dao.GormDB.Order(sessionListOrder).Where("send_id = ?", ownerId).Find(&sessionList); res.Error != nil {
 				if errors.Is(res.Error, gorm.ErrRecordNotFound) {
 					zlog.Info(fmt.Sprintf("用户 %s 未创建会话", ownerId))
 					return "未创建用户会话", nil, -2
@@ -208,10 +209,15 @@ func (s *sessionService) GetUserSessionList(ownerId string) (string, []respond.U
 			for i := 0; i < len(sessionList); i++ {
 				if sessionList[i].ReceiveId[0] == 'U' {
 					sessionListRsp = append(sessionListRsp, respond.UserSessionListRespond{
-						SessionId: sessionList[i].Uuid,
-						Avatar:    sessionList[i].Avatar,
-						UserId:    sessionList[i].ReceiveId,
-						Username:  sessionList[i].ReceiveName,
+						SessionId:     sessionList[i].Uuid,
+						Avatar:        sessionList[i].Avatar,
+						UserId:        sessionList[i].ReceiveId,
+						Username:      sessionList[i].ReceiveName,
+						LastMessage:   sessionList[i].LastMessage,
+						LastMessageAt: formatLastMessageAt(&sessionList[i]),
+						Pinned:        sessionList[i].PinnedAt.Valid,
+						Archived:      sessionList[i].ArchivedAt.Valid,
+						FolderId:      sessionList[i].FolderId,
 					})
 				}
 			}
@@ -223,6 +229,8 @@ func (s *sessionService) GetUserSessionList(ownerId string) (string, []respond.U
 			if err := cache.GetGlobalCache().SetKeyEx("session_list_"+ownerId, string(rspString), time.Minute*constants.REDIS_TIMEOUT); err != nil {
 				zlog.Warn(fmt.Sprintf("缓存会话列表错误: %s", err.Error()))
 			}
+			sessionListRsp = filterUserSessionList(sessionListRsp, req)
+			attachUserSessionMeta(ownerId, sessionListRsp)
 			return "获取成功", sessionListRsp, 0
 		} else {
 			zlog.Error(fmt.Sprintf("查询会话数据库错误: %s", err.Error()))
@@ -234,16 +242,19 @@ func (s *sessionService) GetUserSessionList(ownerId string) (string, []respond.U
 		zlog.Error(fmt.Sprintf("会话反序列化错误: %s", err.Error()))
 		return constants.SYSTEM_ERROR, nil, -1
 	}
+	rsp = filterUserSessionList(rsp, req)
+	attachUserSessionMeta(ownerId, rsp)
 	return "获取成功", rsp, 0
 }
 
-// GetGroupSessionList 获取群聊会话列表 -✅
-func (s *sessionService) GetGroupSessionList(ownerId string) (string, []respond.GroupSessionListRespond, int) {
+// GetGroupSessionList 获取群聊会话列表，缓存完整列表，按归档状态和分组筛选后返回 -✅
+func (s *sessionService) GetGroupSessionList(req request.SessionListRequest) (string, []respond.GroupSessionListRespond, int) {
+	ownerId := req.OwnerId
 	rspString, err := cache.GetGlobalCache().GetKeyNilIsErr("group_session_list_" + ownerId)
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			var sessionList []model.Session
-			if res := dao.GormDB.Order("created_at DESC").Where("send_id = ?", ownerId).Find(&sessionList); res.Error != nil {
+			if res := dao.GormDB.Order(sessionListOrder).Where("send_id = ?", ownerId).Find(&sessionList); res.Error != nil {
 				if errors.Is(res.Error, gorm.ErrRecordNotFound) {
 					zlog.Info(fmt.Sprintf("用户 %s 未创建群聊会话", ownerId))
 					return "未创建群聊会话", nil, -2
@@ -256,10 +267,15 @@ func (s *sessionService) GetGroupSessionList(ownerId string) (string, []respond.
 			for i := 0; i < len(sessionList); i++ {
 				if sessionList[i].ReceiveId[0] == 'G' {
 					sessionListRsp = append(sessionListRsp, respond.GroupSessionListRespond{
-						SessionId: sessionList[i].Uuid,
-						Avatar:    sessionList[i].Avatar,
-						GroupId:   sessionList[i].ReceiveId,
-						GroupName: sessionList[i].ReceiveName,
+						SessionId:     sessionList[i].Uuid,
+						Avatar:        sessionList[i].Avatar,
+						GroupId:       sessionList[i].ReceiveId,
+						GroupName:     sessionList[i].ReceiveName,
+						LastMessage:   sessionList[i].LastMessage,
+						LastMessageAt: formatLastMessageAt(&sessionList[i]),
+						Pinned:        sessionList[i].PinnedAt.Valid,
+						Archived:      sessionList[i].ArchivedAt.Valid,
+						FolderId:      sessionList[i].FolderId,
 					})
 				}
 			}
@@ -270,6 +286,8 @@ func (s *sessionService) GetGroupSessionList(ownerId string) (string, []respond.
 			if err := cache.GetGlobalCache().SetKeyEx("group_session_list_"+ownerId, string(rspString), time.Minute*constants.REDIS_TIMEOUT); err != nil {
 				zlog.Warn(fmt.Sprintf("缓存群聊会话列表错误: %s", err.Error()))
 			}
+			sessionListRsp = filterGroupSessionList(sessionListRsp, req)
+			attachGroupSessionMeta(ownerId, sessionListRsp)
 			return "获取成功", sessionListRsp, 1
 		} else {
 			zlog.Error(fmt.Sprintf("查询会话数据库错误: %s", err.Error()))
@@ -281,6 +299,8 @@ func (s *sessionService) GetGroupSessionList(ownerId string) (string, []respond.
 		zlog.Error(fmt.Sprintf("会话反序列化错误: %s", err.Error()))
 		return constants.SYSTEM_ERROR, nil, -1
 	}
+	rsp = filterGroupSessionList(rsp, req)
+	attachGroupSessionMeta(ownerId, rsp)
 	return "获取成功", rsp, 1
 }
 
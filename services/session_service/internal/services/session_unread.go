@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/puoxiu/gogochat/common/unread"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/session_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/session_service/internal/model"
+)
+
+const EventUnreadUpdated = "unread_updated" // 会话未读数变化，与chat_service推送的事件相同
+
+// loadUnreadCounters redis中没有用户的未读数时从数据库加载
+func loadUnreadCounters(userId string) (map[string]int, error) {
+	var counterList []model.UnreadCounter
+	if res := dao.GormDB.Where("user_id = ? AND count > 0", userId).Find(&counterList); res.Error != nil {
+		return nil, res.Error
+	}
+	counts := make(map[string]int, len(counterList))
+	for _, counter := range counterList {
+		counts[counter.TargetId] = counter.Count
+	}
+	return counts, nil
+}
+
+// loadSessionUnread 查询用户所有会话的未读数，出错时按没有未读处理
+func loadSessionUnread(ownerId string) map[string]int {
+	counts, err := unread.Counts(ownerId, loadUnreadCounters)
+	if err != nil {
+		zlog.Error(err.Error())
+		return map[string]int{}
+	}
+	return counts
+}
+
+// ResetUnread 用户打开会话时清零未读数，并同步给用户其他在线的设备
+func (s *sessionService) ResetUnread(ownerId, targetId string) {
+	if err := unread.Reset(ownerId, targetId, loadUnreadCounters); err != nil {
+		zlog.Error(err.Error())
+		return
+	}
+	event := respond.UnreadEventRespond{
+		Event:    EventUnreadUpdated,
+		TargetId: targetId,
+		Unread:   0,
+		Total:    unread.Total(loadSessionUnread(ownerId)),
+		Silent:   true,
+	}
+	if err := pushUserEvent(ownerId, event); err != nil {
+		zlog.Warn(fmt.Sprintf("同步未读数失败, owner_id: %s, target_id: %s: %s", ownerId, targetId, err.Error()))
+	}
+}
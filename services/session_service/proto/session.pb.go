@@ -183,8 +183,9 @@ func (x *CreateSessionRequest) GetReceiveId() string {
 // 创建会话响应
 type CreateSessionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`      // 状态码 0-成功 -1-服务失败 -2 业务失败
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // 提示信息
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`                           // 状态码 0-成功 -1-服务失败 -2 业务失败
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                      // 提示信息
+	SessionId     string                 `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 会话ID
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -233,6 +234,135 @@ func (x *CreateSessionResponse) GetMessage() string {
 	return ""
 }
 
+func (x *CreateSessionResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// 更新会话最新消息请求
+type UpdateLastMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SendId        string                 `protobuf:"bytes,1,opt,name=send_id,json=sendId,proto3" json:"send_id,omitempty"`                         // 发送者ID
+	ReceiveId     string                 `protobuf:"bytes,2,opt,name=receive_id,json=receiveId,proto3" json:"receive_id,omitempty"`                // 接收者ID，用户或群聊
+	LastMessage   string                 `protobuf:"bytes,3,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`          // 最新消息的摘要
+	LastMessageAt int64                  `protobuf:"varint,4,opt,name=last_message_at,json=lastMessageAt,proto3" json:"last_message_at,omitempty"` // 消息时间，unix毫秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLastMessageRequest) Reset() {
+	*x = UpdateLastMessageRequest{}
+	mi := &file_services_session_service_proto_session_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLastMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLastMessageRequest) ProtoMessage() {}
+
+func (x *UpdateLastMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_session_service_proto_session_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLastMessageRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLastMessageRequest) Descriptor() ([]byte, []int) {
+	return file_services_session_service_proto_session_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateLastMessageRequest) GetSendId() string {
+	if x != nil {
+		return x.SendId
+	}
+	return ""
+}
+
+func (x *UpdateLastMessageRequest) GetReceiveId() string {
+	if x != nil {
+		return x.ReceiveId
+	}
+	return ""
+}
+
+func (x *UpdateLastMessageRequest) GetLastMessage() string {
+	if x != nil {
+		return x.LastMessage
+	}
+	return ""
+}
+
+func (x *UpdateLastMessageRequest) GetLastMessageAt() int64 {
+	if x != nil {
+		return x.LastMessageAt
+	}
+	return 0
+}
+
+// 更新会话最新消息响应
+type UpdateLastMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`      // 状态码 0-成功 -1-服务失败 -2 业务失败
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // 提示信息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLastMessageResponse) Reset() {
+	*x = UpdateLastMessageResponse{}
+	mi := &file_services_session_service_proto_session_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLastMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLastMessageResponse) ProtoMessage() {}
+
+func (x *UpdateLastMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_session_service_proto_session_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLastMessageResponse.ProtoReflect.Descriptor instead.
+func (*UpdateLastMessageResponse) Descriptor() ([]byte, []int) {
+	return file_services_session_service_proto_session_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateLastMessageResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *UpdateLastMessageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_services_session_service_proto_session_proto protoreflect.FileDescriptor
 
 const file_services_session_service_proto_session_proto_rawDesc = "" +
@@ -248,13 +378,25 @@ const file_services_session_service_proto_session_proto_rawDesc = "" +
 	"\x14CreateSessionRequest\x12\x17\n" +
 	"\asend_id\x18\x01 \x01(\tR\x06sendId\x12\x1d\n" +
 	"\n" +
-	"receive_id\x18\x02 \x01(\tR\treceiveId\"E\n" +
+	"receive_id\x18\x02 \x01(\tR\treceiveId\"d\n" +
 	"\x15CreateSessionResponse\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\xd2\x01\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x03 \x01(\tR\tsessionId\"\x9d\x01\n" +
+	"\x18UpdateLastMessageRequest\x12\x17\n" +
+	"\asend_id\x18\x01 \x01(\tR\x06sendId\x12\x1d\n" +
+	"\n" +
+	"receive_id\x18\x02 \x01(\tR\treceiveId\x12!\n" +
+	"\flast_message\x18\x03 \x01(\tR\vlastMessage\x12&\n" +
+	"\x0flast_message_at\x18\x04 \x01(\x03R\rlastMessageAt\"I\n" +
+	"\x19UpdateLastMessageResponse\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xae\x02\n" +
 	"\x0eSessionService\x12f\n" +
 	"\x15DeleteSessionsByUsers\x12%.session.DeleteSessionsByUsersRequest\x1a&.session.DeleteSessionsByUsersResponse\x12X\n" +
-	"\x17CreateSessionIfNotExist\x12\x1d.session.CreateSessionRequest\x1a\x1e.session.CreateSessionResponseB\x11Z\x0f./proto/sessionb\x06proto3"
+	"\x17CreateSessionIfNotExist\x12\x1d.session.CreateSessionRequest\x1a\x1e.session.CreateSessionResponse\x12Z\n" +
+	"\x11UpdateLastMessage\x12!.session.UpdateLastMessageRequest\x1a\".session.UpdateLastMessageResponseB\x11Z\x0f./proto/sessionb\x06proto3"
 
 var (
 	file_services_session_service_proto_session_proto_rawDescOnce sync.Once
@@ -268,20 +410,24 @@ func file_services_session_service_proto_session_proto_rawDescGZIP() []byte {
 	return file_services_session_service_proto_session_proto_rawDescData
 }
 
-var file_services_session_service_proto_session_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_services_session_service_proto_session_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_services_session_service_proto_session_proto_goTypes = []any{
 	(*DeleteSessionsByUsersRequest)(nil),  // 0: session.DeleteSessionsByUsersRequest
 	(*DeleteSessionsByUsersResponse)(nil), // 1: session.DeleteSessionsByUsersResponse
 	(*CreateSessionRequest)(nil),          // 2: session.CreateSessionRequest
 	(*CreateSessionResponse)(nil),         // 3: session.CreateSessionResponse
+	(*UpdateLastMessageRequest)(nil),      // 4: session.UpdateLastMessageRequest
+	(*UpdateLastMessageResponse)(nil),     // 5: session.UpdateLastMessageResponse
 }
 var file_services_session_service_proto_session_proto_depIdxs = []int32{
 	0, // 0: session.SessionService.DeleteSessionsByUsers:input_type -> session.DeleteSessionsByUsersRequest
 	2, // 1: session.SessionService.CreateSessionIfNotExist:input_type -> session.CreateSessionRequest
-	1, // 2: session.SessionService.DeleteSessionsByUsers:output_type -> session.DeleteSessionsByUsersResponse
-	3, // 3: session.SessionService.CreateSessionIfNotExist:output_type -> session.CreateSessionResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
+	4, // 2: session.SessionService.UpdateLastMessage:input_type -> session.UpdateLastMessageRequest
+	1, // 3: session.SessionService.DeleteSessionsByUsers:output_type -> session.DeleteSessionsByUsersResponse
+	3, // 4: session.SessionService.CreateSessionIfNotExist:output_type -> session.CreateSessionResponse
+	5, // 5: session.SessionService.UpdateLastMessage:output_type -> session.UpdateLastMessageResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -298,7 +444,7 @@ func file_services_session_service_proto_session_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_services_session_service_proto_session_proto_rawDesc), len(file_services_session_service_proto_session_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
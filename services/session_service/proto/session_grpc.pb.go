@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	SessionService_DeleteSessionsByUsers_FullMethodName   = "/session.SessionService/DeleteSessionsByUsers"
 	SessionService_CreateSessionIfNotExist_FullMethodName = "/session.SessionService/CreateSessionIfNotExist"
+	SessionService_UpdateLastMessage_FullMethodName       = "/session.SessionService/UpdateLastMessage"
 )
 
 // SessionServiceClient is the client API for SessionService service.
@@ -33,6 +34,8 @@ type SessionServiceClient interface {
 	DeleteSessionsByUsers(ctx context.Context, in *DeleteSessionsByUsersRequest, opts ...grpc.CallOption) (*DeleteSessionsByUsersResponse, error)
 	// 创建会话
 	CreateSessionIfNotExist(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error)
+	// 更新会话的最新消息，单聊更新双方的会话，群聊更新所有成员的会话
+	UpdateLastMessage(ctx context.Context, in *UpdateLastMessageRequest, opts ...grpc.CallOption) (*UpdateLastMessageResponse, error)
 }
 
 type sessionServiceClient struct {
@@ -63,6 +66,16 @@ func (c *sessionServiceClient) CreateSessionIfNotExist(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *sessionServiceClient) UpdateLastMessage(ctx context.Context, in *UpdateLastMessageRequest, opts ...grpc.CallOption) (*UpdateLastMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateLastMessageResponse)
+	err := c.cc.Invoke(ctx, SessionService_UpdateLastMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SessionServiceServer is the server API for SessionService service.
 // All implementations must embed UnimplementedSessionServiceServer
 // for forward compatibility.
@@ -73,6 +86,8 @@ type SessionServiceServer interface {
 	DeleteSessionsByUsers(context.Context, *DeleteSessionsByUsersRequest) (*DeleteSessionsByUsersResponse, error)
 	// 创建会话
 	CreateSessionIfNotExist(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error)
+	// 更新会话的最新消息，单聊更新双方的会话，群聊更新所有成员的会话
+	UpdateLastMessage(context.Context, *UpdateLastMessageRequest) (*UpdateLastMessageResponse, error)
 	mustEmbedUnimplementedSessionServiceServer()
 }
 
@@ -89,6 +104,9 @@ func (UnimplementedSessionServiceServer) DeleteSessionsByUsers(context.Context,
 func (UnimplementedSessionServiceServer) CreateSessionIfNotExist(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateSessionIfNotExist not implemented")
 }
+func (UnimplementedSessionServiceServer) UpdateLastMessage(context.Context, *UpdateLastMessageRequest) (*UpdateLastMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLastMessage not implemented")
+}
 func (UnimplementedSessionServiceServer) mustEmbedUnimplementedSessionServiceServer() {}
 func (UnimplementedSessionServiceServer) testEmbeddedByValue()                        {}
 
@@ -146,6 +164,24 @@ func _SessionService_CreateSessionIfNotExist_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SessionService_UpdateLastMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLastMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).UpdateLastMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_UpdateLastMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).UpdateLastMessage(ctx, req.(*UpdateLastMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SessionService_ServiceDesc is the grpc.ServiceDesc for SessionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -161,6 +197,10 @@ var SessionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateSessionIfNotExist",
 			Handler:    _SessionService_CreateSessionIfNotExist_Handler,
 		},
+		{
+			MethodName: "UpdateLastMessage",
+			Handler:    _SessionService_UpdateLastMessage_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/session_service/proto/session.proto",
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/user_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/user_service/internal/services"
+)
+
+// CreateBot 创建机器人
+func CreateBot(c *gin.Context) {
+	var req request.CreateBotRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.BotService.CreateBot(req)
+	JsonBack(c, message, ret, rsp)
+}
+
+// UpdateBot 修改机器人
+func UpdateBot(c *gin.Context) {
+	var req request.UpdateBotRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.BotService.UpdateBot(req)
+	JsonBack(c, message, ret, nil)
+}
+
+// ResetBotToken 重置机器人令牌
+func ResetBotToken(c *gin.Context) {
+	var req request.BotIdRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rsp, ret := services.BotService.ResetBotToken(req.OwnerId, req.BotId)
+	JsonBack(c, message, ret, rsp)
+}
+
+// DeleteBot 删除机器人
+func DeleteBot(c *gin.Context) {
+	var req request.BotIdRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.BotService.DeleteBot(req.OwnerId, req.BotId)
+	JsonBack(c, message, ret, nil)
+}
+
+// GetMyBotList 获取自己创建的机器人
+func GetMyBotList(c *gin.Context) {
+	var req request.GetMyBotListRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, rspList, ret := services.BotService.GetMyBotList(req.OwnerId)
+	JsonBack(c, message, ret, rspList)
+}
@@ -162,3 +162,48 @@ func RemoveGroupMembers(c *gin.Context) {
 	message, ret := services.GroupInfoService.RemoveGroupMembers(req)
 	JsonBack(c, message, ret, nil)
 }
+
+// SetGroupAdmin 设置或取消群管理员
+func SetGroupAdmin(c *gin.Context) {
+	var req request.SetGroupAdminRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.GroupInfoService.SetGroupAdmin(req)
+	JsonBack(c, message, ret, nil)
+}
+
+// UpdateGroupNotice 修改群公告
+func UpdateGroupNotice(c *gin.Context) {
+	var req request.UpdateGroupNoticeRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.GroupInfoService.UpdateGroupNotice(req)
+	JsonBack(c, message, ret, nil)
+}
+
+// MuteGroupMember 禁言或解除禁言群成员
+func MuteGroupMember(c *gin.Context) {
+	var req request.MuteGroupMemberRequest
+	if err := c.BindJSON(&req); err != nil {
+		zlog.Error(err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"code":    500,
+			"message": constants.SYSTEM_ERROR,
+		})
+		return
+	}
+	message, ret := services.GroupInfoService.MuteGroupMember(req)
+	JsonBack(c, message, ret, nil)
+}
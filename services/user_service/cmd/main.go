@@ -17,6 +17,7 @@ import (
 	"github.com/puoxiu/gogochat/services/user_service/internal/config"
 	"github.com/puoxiu/gogochat/services/user_service/internal/grpc_server"
 	"github.com/puoxiu/gogochat/services/user_service/internal/http_server"
+	"github.com/puoxiu/gogochat/services/user_service/internal/services"
 	user "github.com/puoxiu/gogochat/services/user_service/proto"
 	"google.golang.org/grpc"
 )
@@ -28,6 +29,10 @@ func main() {
 
 	// 初始化 MySQL 数据库
 	dao.InitMySQL()
+	// 创建AI助手用户，所有用户默认都可以和它聊天
+	if err := services.BotService.InitAssistant(); err != nil {
+		zlog.Fatal(fmt.Sprintf("初始化AI助手失败: %v", err))
+	}
 
 	// 初始化缓存
 	redisCache := cache.NewRedisCache(
@@ -32,6 +32,8 @@ func InitMySQL() {
 		&model.GroupInfo{}, 
 		&model.UserContact{},
 		&model.ContactApply{}, 
+		&model.Bot{},
+		&model.GroupMute{},
 	) // 自动迁移，如果没有建表，会自动创建对应的表
 
 	if err != nil {
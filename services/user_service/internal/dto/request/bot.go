@@ -0,0 +1,27 @@
+package request
+
+type CreateBotRequest struct {
+	OwnerId      string `json:"owner_id"`
+	Nickname     string `json:"nickname"`
+	Avatar       string `json:"avatar"`
+	DeliveryMode int8   `json:"delivery_mode"` // 0.webhook，1.gRPC流
+	WebhookUrl   string `json:"webhook_url"`
+}
+
+type UpdateBotRequest struct {
+	OwnerId      string `json:"owner_id"`
+	BotId        string `json:"bot_id"`
+	Nickname     string `json:"nickname"`
+	Avatar       string `json:"avatar"`
+	DeliveryMode int8   `json:"delivery_mode"`
+	WebhookUrl   string `json:"webhook_url"`
+}
+
+type BotIdRequest struct {
+	OwnerId string `json:"owner_id"`
+	BotId   string `json:"bot_id"`
+}
+
+type GetMyBotListRequest struct {
+	OwnerId string `json:"owner_id"`
+}
@@ -0,0 +1,8 @@
+package request
+
+type MuteGroupMemberRequest struct {
+	OwnerId string `json:"owner_id"`
+	GroupId string `json:"group_id"`
+	UserId  string `json:"user_id"`
+	Minutes int    `json:"minutes"` // 禁言时长，单位分钟，0表示解除禁言
+}
@@ -0,0 +1,7 @@
+package request
+
+type UpdateGroupNoticeRequest struct {
+	OwnerId string `json:"owner_id"`
+	GroupId string `json:"group_id"`
+	Notice  string `json:"notice"`
+}
@@ -0,0 +1,18 @@
+package respond
+
+type BotRespond struct {
+	BotId        string `json:"bot_id"`
+	Nickname     string `json:"nickname"`
+	Avatar       string `json:"avatar"`
+	DeliveryMode int8   `json:"delivery_mode"`
+	WebhookUrl   string `json:"webhook_url"`
+	Status       int8   `json:"status"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// BotCredentialRespond 创建或重置令牌时返回，令牌和签名密钥只在这里出现一次
+type BotCredentialRespond struct {
+	BotRespond
+	Token         string `json:"token"`
+	WebhookSecret string `json:"webhook_secret"`
+}
@@ -11,5 +11,6 @@ type GetUserInfoRespond struct {
 	Signature string `json:"signature"`
 	CreatedAt string `json:"created_at"`
 	IsAdmin   int8   `json:"is_admin"`
+	IsBot     int8   `json:"is_bot"`
 	Status    int8   `json:"status"`
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package grpc_server
 import (
 	"context"
 
+	"github.com/puoxiu/gogochat/services/user_service/internal/dto/request"
 	"github.com/puoxiu/gogochat/services/user_service/internal/services"
 	user "github.com/puoxiu/gogochat/services/user_service/proto"
 )
@@ -76,3 +77,40 @@ func (s *UserGrpcServer) GetContactStatus(ctx context.Context, req *user.GetCont
 		Status:    int32(status),
 	}, nil
 }
+
+func (s *UserGrpcServer) RemoveGroupMembers(ctx context.Context, req *user.RemoveGroupMembersRequest) (*user.GroupOperationResponse, error) {
+	msg, code := services.GroupInfoService.RemoveGroupMembers(request.RemoveGroupMembersRequest{
+		GroupId:  req.GroupId,
+		OwnerId:  req.OwnerId,
+		UuidList: req.UuidList,
+	})
+	return &user.GroupOperationResponse{
+		Code:    int32(code),
+		Message: msg,
+	}, nil
+}
+
+func (s *UserGrpcServer) UpdateGroupNotice(ctx context.Context, req *user.UpdateGroupNoticeRequest) (*user.GroupOperationResponse, error) {
+	msg, code := services.GroupInfoService.UpdateGroupNotice(request.UpdateGroupNoticeRequest{
+		OwnerId: req.OwnerId,
+		GroupId: req.GroupId,
+		Notice:  req.Notice,
+	})
+	return &user.GroupOperationResponse{
+		Code:    int32(code),
+		Message: msg,
+	}, nil
+}
+
+func (s *UserGrpcServer) MuteGroupMember(ctx context.Context, req *user.MuteGroupMemberRequest) (*user.GroupOperationResponse, error) {
+	msg, code := services.GroupInfoService.MuteGroupMember(request.MuteGroupMemberRequest{
+		OwnerId: req.OwnerId,
+		GroupId: req.GroupId,
+		UserId:  req.UserId,
+		Minutes: int(req.Minutes),
+	})
+	return &user.GroupOperationResponse{
+		Code:    int32(code),
+		Message: msg,
+	}, nil
+}
@@ -51,4 +51,13 @@ func init() {
 	GE.POST("/group/updateGroupInfo", v1.UpdateGroupInfo)
 	GE.POST("/group/getGroupMemberList", v1.GetGroupMemberList)
 	GE.POST("/group/removeGroupMembers", v1.RemoveGroupMembers)
+	GE.POST("/group/setGroupAdmin", v1.SetGroupAdmin)
+	GE.POST("/group/updateGroupNotice", v1.UpdateGroupNotice)
+	GE.POST("/group/muteGroupMember", v1.MuteGroupMember)
+
+	GE.POST("/bot/createBot", v1.CreateBot)
+	GE.POST("/bot/updateBot", v1.UpdateBot)
+	GE.POST("/bot/resetBotToken", v1.ResetBotToken)
+	GE.POST("/bot/deleteBot", v1.DeleteBot)
+	GE.POST("/bot/getMyBotList", v1.GetMyBotList)
 }
@@ -14,6 +14,7 @@ type GroupInfo struct {
 	Members   json.RawMessage `gorm:"column:members;type:json;comment:群组成员"`
 	MemberCnt int             `gorm:"column:member_cnt;default:1;comment:群人数"` // 默认群主1人
 	OwnerId   string          `gorm:"column:owner_id;type:char(20);not null;comment:群主uuid"`
+	Admins    json.RawMessage `gorm:"column:admins;type:json;comment:群管理员uuid列表"`
 	AddMode   int8            `gorm:"column:add_mode;default:0;comment:加群方式，0.直接，1.审核"`
 	Avatar    string          `gorm:"column:avatar;type:char(255);default:https://cube.elemecdn.com/0/88/03b0d39583f48206768a7534e55bcpng.png;not null;comment:头像"`
 	Status    int8            `gorm:"column:status;default:0;comment:状态，0.正常，1.禁用，2.解散"`
@@ -22,6 +22,7 @@ type UserInfo struct {
 	LastOnlineAt  sql.NullTime      `gorm:"column:last_online_at;type:datetime;comment:上次登录时间"`
 	LastOfflineAt sql.NullTime      `gorm:"column:last_offline_at;type:datetime;comment:最近离线时间"`
 	IsAdmin       int8           `gorm:"column:is_admin;not null;comment:是否是管理员，0.不是，1.是"`
+	IsBot         int8           `gorm:"column:is_bot;not null;default:0;comment:是否是机器人，0.不是，1.是"`
 	Status        int8           `gorm:"column:status;index;not null;comment:状态，0.正常，1.禁用"`
 }
 
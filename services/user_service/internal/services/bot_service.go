@@ -0,0 +1,310 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puoxiu/gogochat/common/cache"
+	"github.com/puoxiu/gogochat/pkg/bottoken"
+	"github.com/puoxiu/gogochat/pkg/constants"
+	"github.com/puoxiu/gogochat/pkg/enum/bot/bot_delivery_enum"
+	"github.com/puoxiu/gogochat/pkg/enum/user_info/user_status_enum"
+	"github.com/puoxiu/gogochat/pkg/random"
+	"github.com/puoxiu/gogochat/pkg/zlog"
+	"github.com/puoxiu/gogochat/services/user_service/internal/dao"
+	"github.com/puoxiu/gogochat/services/user_service/internal/dto/request"
+	"github.com/puoxiu/gogochat/services/user_service/internal/dto/respond"
+	"github.com/puoxiu/gogochat/services/user_service/internal/model"
+	"gorm.io/gorm"
+)
+
+type botService struct {
+}
+
+var BotService = new(botService)
+
+const (
+	maxBotsPerUser    = 10 // 每个用户最多创建的机器人数
+	botNicknameMaxLen = 20 // 机器人昵称最大字符数，与user_info.nickname一致
+	defaultBotAvatar  = "https://cube.elemecdn.com/0/88/03b0d39583f48206768a7534e55bcpng.png"
+	assistantNickname = "AI助手"
+)
+
+// CreateBot 创建机器人：新建一个is_bot为1的用户，并生成令牌和webhook签名密钥
+func (b *botService) CreateBot(req request.CreateBotRequest) (string, *respond.BotCredentialRespond, int) {
+	nickname := strings.TrimSpace(req.Nickname)
+	if nickname == "" || utf8.RuneCountInString(nickname) > botNicknameMaxLen {
+		return fmt.Sprintf("机器人名称不能为空且不能超过%d个字", botNicknameMaxLen), nil, -2
+	}
+	if msg := b.checkDelivery(req.DeliveryMode, req.WebhookUrl); msg != "" {
+		return msg, nil, -2
+	}
+	var owner model.UserInfo
+	if res := dao.GormDB.Where("uuid = ?", req.OwnerId).First(&owner); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "用户不存在", nil, -2
+		}
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if owner.IsBot == 1 || owner.Status != user_status_enum.NORMAL {
+		return "该用户不能创建机器人", nil, -2
+	}
+	var botCnt int64
+	if res := dao.GormDB.Model(&model.Bot{}).Where("owner_id = ?", req.OwnerId).Count(&botCnt); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if botCnt >= maxBotsPerUser {
+		return fmt.Sprintf("每个用户最多创建%d个机器人", maxBotsPerUser), nil, -2
+	}
+	token, tokenHash, err := bottoken.Generate()
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	secret, err := bottoken.RandomHex(32)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	// 机器人不能登录，密码只是占位，同样使用随机值
+	password, err := bottoken.RandomHex(9)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	avatar := strings.TrimSpace(req.Avatar)
+	if avatar == "" {
+		avatar = defaultBotAvatar
+	}
+	now := time.Now()
+	botUser := model.UserInfo{
+		Uuid:      "U" + random.GetNowAndLenRandomString(11),
+		Nickname:  nickname,
+		Avatar:    avatar,
+		Password:  password,
+		CreatedAt: now,
+		IsBot:     1,
+		Status:    user_status_enum.NORMAL,
+	}
+	bot := model.Bot{
+		OwnerId:       req.OwnerId,
+		TokenHash:     tokenHash,
+		DeliveryMode:  req.DeliveryMode,
+		WebhookUrl:    strings.TrimSpace(req.WebhookUrl),
+		WebhookSecret: secret,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := dao.GormDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&botUser).Error; err != nil {
+			return err
+		}
+		bot.Uuid = botUser.Uuid
+		return tx.Create(&bot).Error
+	}); err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "创建机器人成功", &respond.BotCredentialRespond{
+		BotRespond:    b.botRespond(&bot, &botUser),
+		Token:         token,
+		WebhookSecret: secret,
+	}, 0
+}
+
+// UpdateBot 修改机器人的名称、头像和投递方式
+func (b *botService) UpdateBot(req request.UpdateBotRequest) (string, int) {
+	bot, msg, ret := b.loadOwnBot(req.OwnerId, req.BotId)
+	if ret != 0 {
+		return msg, ret
+	}
+	if msg := b.checkDelivery(req.DeliveryMode, req.WebhookUrl); msg != "" {
+		return msg, -2
+	}
+	userUpdates := map[string]interface{}{}
+	if nickname := strings.TrimSpace(req.Nickname); nickname != "" {
+		if utf8.RuneCountInString(nickname) > botNicknameMaxLen {
+			return fmt.Sprintf("机器人名称不能超过%d个字", botNicknameMaxLen), -2
+		}
+		userUpdates["nickname"] = nickname
+	}
+	if avatar := strings.TrimSpace(req.Avatar); avatar != "" {
+		userUpdates["avatar"] = avatar
+	}
+	if err := dao.GormDB.Transaction(func(tx *gorm.DB) error {
+		if len(userUpdates) > 0 {
+			if err := tx.Model(&model.UserInfo{}).Where("uuid = ?", bot.Uuid).Updates(userUpdates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.Bot{}).Where("id = ?", bot.Id).Updates(map[string]interface{}{
+			"delivery_mode": req.DeliveryMode,
+			"webhook_url":   strings.TrimSpace(req.WebhookUrl),
+			"updated_at":    time.Now(),
+		}).Error
+	}); err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if err := cache.GetGlobalCache().DelKeyIfExists("user_info_" + bot.Uuid); err != nil {
+		zlog.Error(err.Error())
+	}
+	return "修改机器人成功", 0
+}
+
+// ResetBotToken 重置机器人令牌和签名密钥，旧令牌立即失效
+func (b *botService) ResetBotToken(ownerId, botId string) (string, *respond.BotCredentialRespond, int) {
+	bot, msg, ret := b.loadOwnBot(ownerId, botId)
+	if ret != 0 {
+		return msg, nil, ret
+	}
+	token, tokenHash, err := bottoken.Generate()
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	secret, err := bottoken.RandomHex(32)
+	if err != nil {
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	if res := dao.GormDB.Model(&model.Bot{}).Where("id = ?", bot.Id).Updates(map[string]interface{}{
+		"token_hash":     tokenHash,
+		"webhook_secret": secret,
+		"updated_at":     time.Now(),
+	}); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	var botUser model.UserInfo
+	if res := dao.GormDB.Where("uuid = ?", bot.Uuid).First(&botUser); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	return "重置令牌成功", &respond.BotCredentialRespond{
+		BotRespond:    b.botRespond(bot, &botUser),
+		Token:         token,
+		WebhookSecret: secret,
+	}, 0
+}
+
+// DeleteBot 删除机器人，机器人用户的联系人和会话按删除用户的流程清理
+func (b *botService) DeleteBot(ownerId, botId string) (string, int) {
+	bot, msg, ret := b.loadOwnBot(ownerId, botId)
+	if ret != 0 {
+		return msg, ret
+	}
+	if res := dao.GormDB.Delete(&model.Bot{}, "id = ?", bot.Id); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
+	if msg, ret := UserInfoService.DeleteUser(bot.Uuid); ret != 0 {
+		return msg, ret
+	}
+	return "删除机器人成功", 0
+}
+
+// GetMyBotList 获取自己创建的机器人
+func (b *botService) GetMyBotList(ownerId string) (string, []respond.BotRespond, int) {
+	var botList []model.Bot
+	if res := dao.GormDB.Where("owner_id = ?", ownerId).Order("created_at DESC").Find(&botList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	rspList := make([]respond.BotRespond, 0, len(botList))
+	if len(botList) == 0 {
+		return "获取机器人列表成功", rspList, 0
+	}
+	botIds := make([]string, 0, len(botList))
+	for _, bot := range botList {
+		botIds = append(botIds, bot.Uuid)
+	}
+	var userList []model.UserInfo
+	if res := dao.GormDB.Where("uuid IN ?", botIds).Find(&userList); res.Error != nil {
+		zlog.Error(res.Error.Error())
+		return constants.SYSTEM_ERROR, nil, -1
+	}
+	userMap := make(map[string]*model.UserInfo, len(userList))
+	for i := range userList {
+		userMap[userList[i].Uuid] = &userList[i]
+	}
+	for i := range botList {
+		botUser, ok := userMap[botList[i].Uuid]
+		if !ok {
+			continue
+		}
+		rspList = append(rspList, b.botRespond(&botList[i], botUser))
+	}
+	return "获取机器人列表成功", rspList, 0
+}
+
+// InitAssistant 服务启动时创建AI助手用户，AI助手以机器人身份存在，不能登录，回复由聊天服务生成
+func (b *botService) InitAssistant() error {
+	password, err := bottoken.RandomHex(9)
+	if err != nil {
+		return err
+	}
+	assistant := model.UserInfo{
+		Uuid:      constants.AI_ASSISTANT_ID,
+		Nickname:  assistantNickname,
+		Avatar:    defaultBotAvatar,
+		Password:  password,
+		CreatedAt: time.Now(),
+		IsBot:     1,
+		Status:    user_status_enum.NORMAL,
+	}
+	return dao.GormDB.Where("uuid = ?", constants.AI_ASSISTANT_ID).FirstOrCreate(&assistant).Error
+}
+
+// loadOwnBot 查询机器人并校验是否属于操作人
+func (b *botService) loadOwnBot(ownerId, botId string) (*model.Bot, string, int) {
+	var bot model.Bot
+	if res := dao.GormDB.Where("uuid = ?", botId).First(&bot); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, "机器人不存在", -2
+		}
+		zlog.Error(res.Error.Error())
+		return nil, constants.SYSTEM_ERROR, -1
+	}
+	if bot.OwnerId != ownerId {
+		return nil, "只能管理自己创建的机器人", -2
+	}
+	return &bot, "", 0
+}
+
+// checkDelivery 校验投递方式，webhook只允许https，是否为公网地址在投递时校验
+func (b *botService) checkDelivery(deliveryMode int8, webhookUrl string) string {
+	switch deliveryMode {
+	case bot_delivery_enum.Webhook:
+		u, err := url.Parse(strings.TrimSpace(webhookUrl))
+		if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+			return "webhook地址必须是https链接"
+		}
+		if len(webhookUrl) > 255 {
+			return "webhook地址过长"
+		}
+		return ""
+	case bot_delivery_enum.Stream:
+		return ""
+	}
+	return "不支持的投递方式"
+}
+
+// botRespond 由机器人配置和机器人用户构造响应
+func (b *botService) botRespond(bot *model.Bot, botUser *model.UserInfo) respond.BotRespond {
+	return respond.BotRespond{
+		BotId:        bot.Uuid,
+		Nickname:     botUser.Nickname,
+		Avatar:       botUser.Avatar,
+		DeliveryMode: bot.DeliveryMode,
+		WebhookUrl:   bot.WebhookUrl,
+		Status:       botUser.Status,
+		CreatedAt:    bot.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -19,12 +19,21 @@ import (
 	"github.com/puoxiu/gogochat/services/user_service/internal/dto/respond"
 	"github.com/puoxiu/gogochat/services/user_service/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type groupInfoService struct {
 }
+
+const (
+	groupNoticeMaxLen   = 500          // 群公告最大字符数，与group_info.notice一致
+	groupMuteMaxMinutes = 30 * 24 * 60 // 禁言最长时长，单位分钟
+)
+
 var GroupInfoService = new(groupInfoService)
 
 // CreateGroup 创建群聊
@@ -161,6 +170,13 @@ func (g *groupInfoService) GetGroupInfo(groupId string) (string, *respond.GetGro
 					return constants.SYSTEM_ERROR, nil, -1
 				}
 			}
+			var admins []string
+			if len(group.Admins) > 0 {
+				if err := json.Unmarshal(group.Admins, &admins); err != nil {
+					zlog.Error(fmt.Sprintf("解析群管理员列表失败: %v", err))
+					return constants.SYSTEM_ERROR, nil, -1
+				}
+			}
 			rsp := &respond.GetGroupInfoRespond{
 				Uuid:      group.Uuid,
 				Name:      group.Name,
@@ -168,6 +184,7 @@ func (g *groupInfoService) GetGroupInfo(groupId string) (string, *respond.GetGro
 				Avatar:    group.Avatar,
 				MemberCnt: group.MemberCnt,
 				OwnerId:   group.OwnerId,
+				Admins:    admins,
 				AddMode:   group.AddMode,
 				Status:    group.Status,
 				IsDeleted: group.DeletedAt.Valid,
@@ -257,6 +274,11 @@ func (g *groupInfoService) LeaveGroup(userId string, groupId string) (string, in
 	}
 	group.Members = membersJson
 	group.MemberCnt = len(newMembers)
+	if err := removeGroupAdmins(&group, map[string]bool{userId: true}); err != nil {
+		tx.Rollback()
+		zlog.Error(err.Error())
+		return constants.SYSTEM_ERROR, -1
+	}
 	if res := tx.Save(&group); res.Error != nil {
 		tx.Rollback()
 		zlog.Error(res.Error.Error())
@@ -764,9 +786,10 @@ func (g *groupInfoService) RemoveGroupMembers(req request.RemoveGroupMembersRequ
         zlog.Error(fmt.Sprintf("查询群聊失败: %v", res.Error))
         return constants.SYSTEM_ERROR, -1
     }
-    if group.OwnerId != req.OwnerId {
+    isOwner := group.OwnerId == req.OwnerId
+    if !isOwner && !isGroupManager(&group, req.OwnerId) {
         tx.Rollback()
-        return "无权限移除群成员(仅群主可操作)", -2
+        return "无权限移除群成员(仅群主和管理员可操作)", -2
     }
     if group.DeletedAt.Valid {
         tx.Rollback()
@@ -786,10 +809,15 @@ func (g *groupInfoService) RemoveGroupMembers(req request.RemoveGroupMembersRequ
     }
     toRemoveSet := make(map[string]bool, len(req.UuidList))
     for _, uuid := range req.UuidList {
-        if uuid == req.OwnerId {
+        if uuid == group.OwnerId {
             tx.Rollback()
             return fmt.Sprintf("不能移除群主(成员ID:%s)", uuid), -6
         }
+        // 管理员只能移除普通成员
+        if !isOwner && isGroupManager(&group, uuid) {
+            tx.Rollback()
+            return fmt.Sprintf("管理员不能移除其他管理员(成员ID:%s)", uuid), -2
+        }
         if !currentMemberMap[uuid] {
             tx.Rollback()
             return fmt.Sprintf("成员(ID:%s)不在群内，无需移除", uuid), -7
@@ -859,6 +887,11 @@ func (g *groupInfoService) RemoveGroupMembers(req request.RemoveGroupMembersRequ
     }
     group.Members = newMembersJson
     group.MemberCnt = len(newMembers) 
+    if err := removeGroupAdmins(&group, toRemoveSet); err != nil {
+        tx.Rollback()
+        zlog.Error(fmt.Sprintf("更新群管理员列表失败: %v", err))
+        return constants.SYSTEM_ERROR, -1
+    }
     group.UpdatedAt = time.Now()
     if res := tx.Save(&group); res.Error != nil {
         tx.Rollback()
@@ -874,7 +907,7 @@ func (g *groupInfoService) RemoveGroupMembers(req request.RemoveGroupMembersRequ
     cacheKeys := []string{
         "group_info_" + req.GroupId,          // 群聊基础信息缓存(必须清理)
         "group_memberlist_" + req.GroupId,    // 群成员列表缓存(必须清理)
-        "contact_mygroup_list_" + req.OwnerId, // 群主的“我的群聊”列表(若展示成员数，需清理)
+        "contact_mygroup_list_" + group.OwnerId, // 群主的“我的群聊”列表(若展示成员数，需清理)
     }
     // 补充:清理被移除成员的“已加入群聊”列表缓存
     for _, uuid := range toRemoveList {
@@ -889,3 +922,233 @@ func (g *groupInfoService) RemoveGroupMembers(req request.RemoveGroupMembersRequ
 
     return fmt.Sprintf("成功移除%d名群成员", len(toRemoveList)), 0
 }
+
+// SetGroupAdmin 设置或取消群管理员(仅群主可操作)
+func (g *groupInfoService) SetGroupAdmin(req request.SetGroupAdminRequest) (string, int) {
+	tx := dao.GormDB.Begin()
+	if tx.Error != nil {
+		zlog.Error(fmt.Sprintf("开启事务失败: %v", tx.Error))
+		return constants.SYSTEM_ERROR, -1
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			zlog.Error(fmt.Sprintf("事务panic回滚: %v", r))
+		}
+	}()
+
+	var group model.GroupInfo
+	if res := tx.Set("gorm:query_option", "FOR UPDATE").First(&group, "uuid = ?", req.GroupId); res.Error != nil {
+		tx.Rollback()
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "群聊不存在或已解散", -2
+		}
+		zlog.Error(fmt.Sprintf("查询群聊失败: %v", res.Error))
+		return constants.SYSTEM_ERROR, -1
+	}
+	if group.OwnerId != req.OwnerId {
+		tx.Rollback()
+		return "无权限设置管理员(仅群主可操作)", -2
+	}
+	if req.UserId == group.OwnerId {
+		tx.Rollback()
+		return "群主无需设置为管理员", -2
+	}
+
+	var members []string
+	if err := json.Unmarshal(group.Members, &members); err != nil {
+		tx.Rollback()
+		zlog.Error(fmt.Sprintf("解析群成员列表失败: %v", err))
+		return constants.SYSTEM_ERROR, -1
+	}
+	isMember := false
+	for _, member := range members {
+		if member == req.UserId {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		tx.Rollback()
+		return "该用户不在群聊中", -2
+	}
+
+	var admins []string
+	if len(group.Admins) > 0 {
+		if err := json.Unmarshal(group.Admins, &admins); err != nil {
+			tx.Rollback()
+			zlog.Error(fmt.Sprintf("解析群管理员列表失败: %v", err))
+			return constants.SYSTEM_ERROR, -1
+		}
+	}
+	newAdmins := make([]string, 0, len(admins)+1)
+	for _, admin := range admins {
+		if admin != req.UserId {
+			newAdmins = append(newAdmins, admin)
+		}
+	}
+	if req.IsAdmin {
+		newAdmins = append(newAdmins, req.UserId)
+	}
+	adminsJson, err := json.Marshal(newAdmins)
+	if err != nil {
+		tx.Rollback()
+		zlog.Error(fmt.Sprintf("序列化群管理员列表失败: %v", err))
+		return constants.SYSTEM_ERROR, -1
+	}
+	if res := tx.Model(&group).Updates(map[string]interface{}{
+		"admins":     adminsJson,
+		"updated_at": time.Now(),
+	}); res.Error != nil {
+		tx.Rollback()
+		zlog.Error(fmt.Sprintf("更新群管理员列表失败: %v", res.Error))
+		return constants.SYSTEM_ERROR, -1
+	}
+	if err := tx.Commit().Error; err != nil {
+		zlog.Error(fmt.Sprintf("事务提交失败: %v", err))
+		return constants.SYSTEM_ERROR, -1
+	}
+
+	if err := cache.GetGlobalCache().DelKeyIfExists("group_info_" + req.GroupId); err != nil {
+		zlog.Warn(fmt.Sprintf("清理缓存失败: key=%s, err=%v", "group_info_"+req.GroupId, err))
+	}
+	if req.IsAdmin {
+		return "设置管理员成功", 0
+	}
+	return "取消管理员成功", 0
+}
+
+// removeGroupAdmins 成员离开群聊时，同步从管理员列表中移除
+func removeGroupAdmins(group *model.GroupInfo, removeSet map[string]bool) error {
+	if len(group.Admins) == 0 {
+		return nil
+	}
+	var admins []string
+	if err := json.Unmarshal(group.Admins, &admins); err != nil {
+		return err
+	}
+	newAdmins := make([]string, 0, len(admins))
+	for _, admin := range admins {
+		if !removeSet[admin] {
+			newAdmins = append(newAdmins, admin)
+		}
+	}
+	adminsJson, err := json.Marshal(newAdmins)
+	if err != nil {
+		return err
+	}
+	group.Admins = adminsJson
+	return nil
+}
+
+// UpdateGroupNotice 修改群公告(群主和管理员可操作)
+func (g *groupInfoService) UpdateGroupNotice(req request.UpdateGroupNoticeRequest) (string, int) {
+	notice := strings.TrimSpace(req.Notice)
+	if utf8.RuneCountInString(notice) > groupNoticeMaxLen {
+		return fmt.Sprintf("群公告不能超过%d个字", groupNoticeMaxLen), -2
+	}
+	var group model.GroupInfo
+	if res := dao.GormDB.First(&group, "uuid = ?", req.GroupId); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "群聊不存在或已解散", -2
+		}
+		zlog.Error(fmt.Sprintf("查询群聊失败: %v", res.Error))
+		return constants.SYSTEM_ERROR, -1
+	}
+	if !isGroupManager(&group, req.OwnerId) {
+		return "无权限修改群公告(仅群主和管理员可操作)", -2
+	}
+	if res := dao.GormDB.Model(&group).Updates(map[string]interface{}{
+		"notice":     notice,
+		"updated_at": time.Now(),
+	}); res.Error != nil {
+		zlog.Error(fmt.Sprintf("更新群公告失败: %v", res.Error))
+		return constants.SYSTEM_ERROR, -1
+	}
+	if err := cache.GetGlobalCache().DelKeyIfExists("group_info_" + req.GroupId); err != nil {
+		zlog.Warn(fmt.Sprintf("清理缓存失败: key=%s, err=%v", "group_info_"+req.GroupId, err))
+	}
+	return "群公告修改成功", 0
+}
+
+// MuteGroupMember 禁言或解除禁言群成员(群主和管理员可操作，管理员只能禁言普通成员)
+func (g *groupInfoService) MuteGroupMember(req request.MuteGroupMemberRequest) (string, int) {
+	if req.Minutes < 0 || req.Minutes > groupMuteMaxMinutes {
+		return fmt.Sprintf("禁言时长不能超过%d天", groupMuteMaxMinutes/(24*60)), -2
+	}
+	var group model.GroupInfo
+	if res := dao.GormDB.First(&group, "uuid = ?", req.GroupId); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return "群聊不存在或已解散", -2
+		}
+		zlog.Error(fmt.Sprintf("查询群聊失败: %v", res.Error))
+		return constants.SYSTEM_ERROR, -1
+	}
+	if !isGroupManager(&group, req.OwnerId) {
+		return "无权限禁言群成员(仅群主和管理员可操作)", -2
+	}
+	if req.UserId == group.OwnerId {
+		return "不能禁言群主", -2
+	}
+	if group.OwnerId != req.OwnerId && isGroupManager(&group, req.UserId) {
+		return "管理员不能禁言其他管理员", -2
+	}
+	var members []string
+	if err := json.Unmarshal(group.Members, &members); err != nil {
+		zlog.Error(fmt.Sprintf("解析群成员列表失败: %v", err))
+		return constants.SYSTEM_ERROR, -1
+	}
+	isMember := false
+	for _, member := range members {
+		if member == req.UserId {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		return "该用户不在群聊中", -2
+	}
+	if req.Minutes == 0 {
+		if res := dao.GormDB.Where("group_id = ? AND user_id = ?", req.GroupId, req.UserId).Delete(&model.GroupMute{}); res.Error != nil {
+			zlog.Error(fmt.Sprintf("解除禁言失败: %v", res.Error))
+			return constants.SYSTEM_ERROR, -1
+		}
+		return "已解除禁言", 0
+	}
+	now := time.Now()
+	mute := model.GroupMute{
+		GroupId:    req.GroupId,
+		UserId:     req.UserId,
+		OperatorId: req.OwnerId,
+		MutedUntil: now.Add(time.Duration(req.Minutes) * time.Minute),
+		CreatedAt:  now,
+	}
+	if res := dao.GormDB.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"operator_id", "muted_until", "created_at"}),
+	}).Create(&mute); res.Error != nil {
+		zlog.Error(fmt.Sprintf("禁言群成员失败: %v", res.Error))
+		return constants.SYSTEM_ERROR, -1
+	}
+	return "禁言成功", 0
+}
+
+// isGroupManager 判断用户是否为群主或群管理员
+func isGroupManager(group *model.GroupInfo, userId string) bool {
+	if group.OwnerId == userId {
+		return true
+	}
+	if len(group.Admins) == 0 {
+		return false
+	}
+	var admins []string
+	if err := json.Unmarshal(group.Admins, &admins); err != nil {
+		zlog.Error(fmt.Sprintf("解析群管理员列表失败: %v", err))
+		return false
+	}
+	for _, admin := range admins {
+		if admin == userId {
+			return true
+		}
+	}
+	return false
+}
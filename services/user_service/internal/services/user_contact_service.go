@@ -50,6 +50,17 @@ func (u *userContactService) GetUserList(uuid string) (string, []respond.MyUserL
                 return constants.SYSTEM_ERROR, nil, -1
 			}
 			var userListRsp []respond.MyUserListRespond
+			// AI助手固定排在联系人列表最前面
+			var assistant model.UserInfo
+			if res := dao.GormDB.Where("uuid = ?", constants.AI_ASSISTANT_ID).First(&assistant); res.Error != nil {
+				zlog.Warn(fmt.Sprintf("查询AI助手失败: %v", res.Error))
+			} else {
+				userListRsp = append(userListRsp, respond.MyUserListRespond{
+					UserId:   assistant.Uuid,
+					UserName: assistant.Nickname,
+					Avatar:   assistant.Avatar,
+				})
+			}
 			for _, contact := range contactList {
 				if contact.ContactType != contact_type_enum.User {
 					continue
@@ -290,6 +301,9 @@ func (u *userContactService) DeleteContact(ownerId, contactId string) (string, i
 
 // ApplyContact 申请添加联系人(包括群聊) -✅
 func (u *userContactService) ApplyContact(req request.ApplyContactRequest) (string, int) {
+	if req.ContactId == constants.AI_ASSISTANT_ID {
+		return "AI助手无需添加好友", -2
+	}
 	if req.ContactId[0] == 'U' {
 		var contactApply model.ContactApply
 		if res := dao.GormDB.Where("user_id = ? AND contact_id = ?", req.OwnerId, req.ContactId).First(&contactApply); res.Error != nil {
@@ -324,6 +338,15 @@ func (u *userContactService) ApplyContact(req request.ApplyContactRequest) (stri
 			zlog.Error(fmt.Sprintf("保存联系人申请记录失败：%v", res.Error))
 			return constants.SYSTEM_ERROR, -1
 		}
+		// 机器人不会处理好友申请，直接通过
+		var contact model.UserInfo
+		if res := dao.GormDB.Where("uuid = ?", req.ContactId).First(&contact); res.Error != nil {
+			zlog.Error(fmt.Sprintf("查询用户信息失败：%v", res.Error))
+			return constants.SYSTEM_ERROR, -1
+		}
+		if contact.IsBot == 1 {
+			return u.PassContactApply(req.ContactId, req.OwnerId)
+		}
 		return "申请成功", 0
 	} else if req.ContactId[0] == 'G' {
 		var group model.GroupInfo
@@ -663,6 +686,10 @@ func (u *userContactService) GetUserContact(userId string, contactId string) (st
 
 // GetContactStatus 查询联系人状态 -✅
 func (u *userContactService) GetContactStatus(userId string, contactId string) (string, int8, int8) {
+	// AI助手默认是所有用户的好友，没有好友关系记录
+	if userId == constants.AI_ASSISTANT_ID || contactId == constants.AI_ASSISTANT_ID {
+		return "查询好友关系成功", 0, contact_status_enum.NORMAL
+	}
 	var contact model.UserContact
 	if res := dao.GormDB.Where("user_id = ? AND contact_id = ?", userId, contactId).First(&contact); res.Error != nil {
 		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
@@ -66,6 +66,10 @@ func (u *userInfoService) Login(loginReq request.LoginRequest) (string, *respond
 		zlog.Error(res.Error.Error())
 		return constants.SYSTEM_ERROR, nil, -1
 	}
+	// 机器人账号没有手机号和密码，只能通过令牌调用机器人接口
+	if user.IsBot == 1 {
+		return "用户不存在，请注册", nil, -2
+	}
 	if user.Password != password {
 		zlog.Warn(fmt.Sprintf("密码不正确: telephone=%s", loginReq.Telephone))
 		return "密码不正确，请重试", nil, -2
@@ -101,6 +105,9 @@ func (u *userInfoService) SmsLogin(req request.SmsLoginRequest) (string, *respon
 		zlog.Error(res.Error.Error())
 		return constants.SYSTEM_ERROR, nil, -1
 	} 
+	if user.IsBot == 1 {
+		return "用户不存在，请注册", nil, -2
+	}
 
 	key := "auth_code_" + req.Telephone
 	code, err := cache.GetGlobalCache().GetKey(key)
@@ -451,6 +458,7 @@ func (u *userInfoService) GetUserInfo(uuid string) (string, *respond.GetUserInfo
 				Signature: user.Signature,
 				CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
 				IsAdmin:   user.IsAdmin,
+				IsBot:     user.IsBot,
 				Status:    user.Status,
 			}
 			rspString, err := json.Marshal(rsp)
@@ -504,6 +504,250 @@ func (x *GetContactStatusResponse) GetStatus() int32 {
 	return 0
 }
 
+// 移除群成员请求
+type RemoveGroupMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerId       string                 `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"` // 操作人UUID，群主或管理员
+	GroupId       string                 `protobuf:"bytes,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	UuidList      []string               `protobuf:"bytes,3,rep,name=uuid_list,json=uuidList,proto3" json:"uuid_list,omitempty"` // 要移除的成员UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveGroupMembersRequest) Reset() {
+	*x = RemoveGroupMembersRequest{}
+	mi := &file_services_user_service_proto_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveGroupMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveGroupMembersRequest) ProtoMessage() {}
+
+func (x *RemoveGroupMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_user_service_proto_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveGroupMembersRequest.ProtoReflect.Descriptor instead.
+func (*RemoveGroupMembersRequest) Descriptor() ([]byte, []int) {
+	return file_services_user_service_proto_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RemoveGroupMembersRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *RemoveGroupMembersRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *RemoveGroupMembersRequest) GetUuidList() []string {
+	if x != nil {
+		return x.UuidList
+	}
+	return nil
+}
+
+// 修改群公告请求
+type UpdateGroupNoticeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerId       string                 `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"` // 操作人UUID，群主或管理员
+	GroupId       string                 `protobuf:"bytes,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Notice        string                 `protobuf:"bytes,3,opt,name=notice,proto3" json:"notice,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateGroupNoticeRequest) Reset() {
+	*x = UpdateGroupNoticeRequest{}
+	mi := &file_services_user_service_proto_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateGroupNoticeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateGroupNoticeRequest) ProtoMessage() {}
+
+func (x *UpdateGroupNoticeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_user_service_proto_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateGroupNoticeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateGroupNoticeRequest) Descriptor() ([]byte, []int) {
+	return file_services_user_service_proto_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateGroupNoticeRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *UpdateGroupNoticeRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *UpdateGroupNoticeRequest) GetNotice() string {
+	if x != nil {
+		return x.Notice
+	}
+	return ""
+}
+
+// 禁言群成员请求
+type MuteGroupMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerId       string                 `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"` // 操作人UUID，群主或管理员
+	GroupId       string                 `protobuf:"bytes,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 被禁言的成员UUID
+	Minutes       int32                  `protobuf:"varint,4,opt,name=minutes,proto3" json:"minutes,omitempty"`            // 禁言时长，单位分钟，0表示解除禁言
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MuteGroupMemberRequest) Reset() {
+	*x = MuteGroupMemberRequest{}
+	mi := &file_services_user_service_proto_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MuteGroupMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MuteGroupMemberRequest) ProtoMessage() {}
+
+func (x *MuteGroupMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_user_service_proto_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MuteGroupMemberRequest.ProtoReflect.Descriptor instead.
+func (*MuteGroupMemberRequest) Descriptor() ([]byte, []int) {
+	return file_services_user_service_proto_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MuteGroupMemberRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *MuteGroupMemberRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *MuteGroupMemberRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MuteGroupMemberRequest) GetMinutes() int32 {
+	if x != nil {
+		return x.Minutes
+	}
+	return 0
+}
+
+// 群管理操作响应
+type GroupOperationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`      // 状态码 0-成功 -1-服务失败 -2 业务失败
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // 提示信息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupOperationResponse) Reset() {
+	*x = GroupOperationResponse{}
+	mi := &file_services_user_service_proto_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupOperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupOperationResponse) ProtoMessage() {}
+
+func (x *GroupOperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_user_service_proto_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupOperationResponse.ProtoReflect.Descriptor instead.
+func (*GroupOperationResponse) Descriptor() ([]byte, []int) {
+	return file_services_user_service_proto_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GroupOperationResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *GroupOperationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_services_user_service_proto_user_proto protoreflect.FileDescriptor
 
 const file_services_user_service_proto_user_proto_rawDesc = "" +
@@ -548,11 +792,30 @@ const file_services_user_service_proto_user_proto_rawDesc = "" +
 	"\x18GetContactStatusResponse\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
-	"\x06status\x18\x03 \x01(\x05R\x06status2\xf1\x01\n" +
+	"\x06status\x18\x03 \x01(\x05R\x06status\"n\n" +
+	"\x19RemoveGroupMembersRequest\x12\x19\n" +
+	"\bowner_id\x18\x01 \x01(\tR\aownerId\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\tR\agroupId\x12\x1b\n" +
+	"\tuuid_list\x18\x03 \x03(\tR\buuidList\"h\n" +
+	"\x18UpdateGroupNoticeRequest\x12\x19\n" +
+	"\bowner_id\x18\x01 \x01(\tR\aownerId\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\tR\agroupId\x12\x16\n" +
+	"\x06notice\x18\x03 \x01(\tR\x06notice\"\x81\x01\n" +
+	"\x16MuteGroupMemberRequest\x12\x19\n" +
+	"\bowner_id\x18\x01 \x01(\tR\aownerId\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\tR\agroupId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x18\n" +
+	"\aminutes\x18\x04 \x01(\x05R\aminutes\"F\n" +
+	"\x16GroupOperationResponse\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xe8\x03\n" +
 	"\vUserService\x12B\n" +
 	"\vGetUserInfo\x12\x18.user.GetUserInfoRequest\x1a\x19.user.GetUserInfoResponse\x12K\n" +
 	"\x0eGetUserContact\x12\x1b.user.GetUserContactRequest\x1a\x1c.user.GetUserContactResponse\x12Q\n" +
-	"\x10GetContactStatus\x12\x1d.user.GetContactStatusRequest\x1a\x1e.user.GetContactStatusResponseB\x0eZ\f./proto/userb\x06proto3"
+	"\x10GetContactStatus\x12\x1d.user.GetContactStatusRequest\x1a\x1e.user.GetContactStatusResponse\x12S\n" +
+	"\x12RemoveGroupMembers\x12\x1f.user.RemoveGroupMembersRequest\x1a\x1c.user.GroupOperationResponse\x12Q\n" +
+	"\x11UpdateGroupNotice\x12\x1e.user.UpdateGroupNoticeRequest\x1a\x1c.user.GroupOperationResponse\x12M\n" +
+	"\x0fMuteGroupMember\x12\x1c.user.MuteGroupMemberRequest\x1a\x1c.user.GroupOperationResponseB\x0eZ\f./proto/userb\x06proto3"
 
 var (
 	file_services_user_service_proto_user_proto_rawDescOnce sync.Once
@@ -566,29 +829,39 @@ func file_services_user_service_proto_user_proto_rawDescGZIP() []byte {
 	return file_services_user_service_proto_user_proto_rawDescData
 }
 
-var file_services_user_service_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_services_user_service_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_services_user_service_proto_user_proto_goTypes = []any{
-	(*GetUserInfoRequest)(nil),       // 0: user.GetUserInfoRequest
-	(*GetUserInfoResponse)(nil),      // 1: user.GetUserInfoResponse
-	(*GetUserContactRequest)(nil),    // 2: user.GetUserContactRequest
-	(*FriendContact)(nil),            // 3: user.FriendContact
-	(*GetUserContactResponse)(nil),   // 4: user.GetUserContactResponse
-	(*GetContactStatusRequest)(nil),  // 5: user.GetContactStatusRequest
-	(*GetContactStatusResponse)(nil), // 6: user.GetContactStatusResponse
+	(*GetUserInfoRequest)(nil),        // 0: user.GetUserInfoRequest
+	(*GetUserInfoResponse)(nil),       // 1: user.GetUserInfoResponse
+	(*GetUserContactRequest)(nil),     // 2: user.GetUserContactRequest
+	(*FriendContact)(nil),             // 3: user.FriendContact
+	(*GetUserContactResponse)(nil),    // 4: user.GetUserContactResponse
+	(*GetContactStatusRequest)(nil),   // 5: user.GetContactStatusRequest
+	(*GetContactStatusResponse)(nil),  // 6: user.GetContactStatusResponse
+	(*RemoveGroupMembersRequest)(nil), // 7: user.RemoveGroupMembersRequest
+	(*UpdateGroupNoticeRequest)(nil),  // 8: user.UpdateGroupNoticeRequest
+	(*MuteGroupMemberRequest)(nil),    // 9: user.MuteGroupMemberRequest
+	(*GroupOperationResponse)(nil),    // 10: user.GroupOperationResponse
 }
 var file_services_user_service_proto_user_proto_depIdxs = []int32{
-	3, // 0: user.GetUserContactResponse.contact:type_name -> user.FriendContact
-	0, // 1: user.UserService.GetUserInfo:input_type -> user.GetUserInfoRequest
-	2, // 2: user.UserService.GetUserContact:input_type -> user.GetUserContactRequest
-	5, // 3: user.UserService.GetContactStatus:input_type -> user.GetContactStatusRequest
-	1, // 4: user.UserService.GetUserInfo:output_type -> user.GetUserInfoResponse
-	4, // 5: user.UserService.GetUserContact:output_type -> user.GetUserContactResponse
-	6, // 6: user.UserService.GetContactStatus:output_type -> user.GetContactStatusResponse
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3,  // 0: user.GetUserContactResponse.contact:type_name -> user.FriendContact
+	0,  // 1: user.UserService.GetUserInfo:input_type -> user.GetUserInfoRequest
+	2,  // 2: user.UserService.GetUserContact:input_type -> user.GetUserContactRequest
+	5,  // 3: user.UserService.GetContactStatus:input_type -> user.GetContactStatusRequest
+	7,  // 4: user.UserService.RemoveGroupMembers:input_type -> user.RemoveGroupMembersRequest
+	8,  // 5: user.UserService.UpdateGroupNotice:input_type -> user.UpdateGroupNoticeRequest
+	9,  // 6: user.UserService.MuteGroupMember:input_type -> user.MuteGroupMemberRequest
+	1,  // 7: user.UserService.GetUserInfo:output_type -> user.GetUserInfoResponse
+	4,  // 8: user.UserService.GetUserContact:output_type -> user.GetUserContactResponse
+	6,  // 9: user.UserService.GetContactStatus:output_type -> user.GetContactStatusResponse
+	10, // 10: user.UserService.RemoveGroupMembers:output_type -> user.GroupOperationResponse
+	10, // 11: user.UserService.UpdateGroupNotice:output_type -> user.GroupOperationResponse
+	10, // 12: user.UserService.MuteGroupMember:output_type -> user.GroupOperationResponse
+	7,  // [7:13] is the sub-list for method output_type
+	1,  // [1:7] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_services_user_service_proto_user_proto_init() }
@@ -602,7 +875,7 @@ func file_services_user_service_proto_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_services_user_service_proto_user_proto_rawDesc), len(file_services_user_service_proto_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
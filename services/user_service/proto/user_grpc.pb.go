@@ -19,9 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_GetUserInfo_FullMethodName      = "/user.UserService/GetUserInfo"
-	UserService_GetUserContact_FullMethodName   = "/user.UserService/GetUserContact"
-	UserService_GetContactStatus_FullMethodName = "/user.UserService/GetContactStatus"
+	UserService_GetUserInfo_FullMethodName        = "/user.UserService/GetUserInfo"
+	UserService_GetUserContact_FullMethodName     = "/user.UserService/GetUserContact"
+	UserService_GetContactStatus_FullMethodName   = "/user.UserService/GetContactStatus"
+	UserService_RemoveGroupMembers_FullMethodName = "/user.UserService/RemoveGroupMembers"
+	UserService_UpdateGroupNotice_FullMethodName  = "/user.UserService/UpdateGroupNotice"
+	UserService_MuteGroupMember_FullMethodName    = "/user.UserService/MuteGroupMember"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -34,6 +37,9 @@ type UserServiceClient interface {
 	GetUserInfo(ctx context.Context, in *GetUserInfoRequest, opts ...grpc.CallOption) (*GetUserInfoResponse, error)
 	GetUserContact(ctx context.Context, in *GetUserContactRequest, opts ...grpc.CallOption) (*GetUserContactResponse, error)
 	GetContactStatus(ctx context.Context, in *GetContactStatusRequest, opts ...grpc.CallOption) (*GetContactStatusResponse, error)
+	RemoveGroupMembers(ctx context.Context, in *RemoveGroupMembersRequest, opts ...grpc.CallOption) (*GroupOperationResponse, error)
+	UpdateGroupNotice(ctx context.Context, in *UpdateGroupNoticeRequest, opts ...grpc.CallOption) (*GroupOperationResponse, error)
+	MuteGroupMember(ctx context.Context, in *MuteGroupMemberRequest, opts ...grpc.CallOption) (*GroupOperationResponse, error)
 }
 
 type userServiceClient struct {
@@ -74,6 +80,36 @@ func (c *userServiceClient) GetContactStatus(ctx context.Context, in *GetContact
 	return out, nil
 }
 
+func (c *userServiceClient) RemoveGroupMembers(ctx context.Context, in *RemoveGroupMembersRequest, opts ...grpc.CallOption) (*GroupOperationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupOperationResponse)
+	err := c.cc.Invoke(ctx, UserService_RemoveGroupMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateGroupNotice(ctx context.Context, in *UpdateGroupNoticeRequest, opts ...grpc.CallOption) (*GroupOperationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupOperationResponse)
+	err := c.cc.Invoke(ctx, UserService_UpdateGroupNotice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) MuteGroupMember(ctx context.Context, in *MuteGroupMemberRequest, opts ...grpc.CallOption) (*GroupOperationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupOperationResponse)
+	err := c.cc.Invoke(ctx, UserService_MuteGroupMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -84,6 +120,9 @@ type UserServiceServer interface {
 	GetUserInfo(context.Context, *GetUserInfoRequest) (*GetUserInfoResponse, error)
 	GetUserContact(context.Context, *GetUserContactRequest) (*GetUserContactResponse, error)
 	GetContactStatus(context.Context, *GetContactStatusRequest) (*GetContactStatusResponse, error)
+	RemoveGroupMembers(context.Context, *RemoveGroupMembersRequest) (*GroupOperationResponse, error)
+	UpdateGroupNotice(context.Context, *UpdateGroupNoticeRequest) (*GroupOperationResponse, error)
+	MuteGroupMember(context.Context, *MuteGroupMemberRequest) (*GroupOperationResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -103,6 +142,15 @@ func (UnimplementedUserServiceServer) GetUserContact(context.Context, *GetUserCo
 func (UnimplementedUserServiceServer) GetContactStatus(context.Context, *GetContactStatusRequest) (*GetContactStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetContactStatus not implemented")
 }
+func (UnimplementedUserServiceServer) RemoveGroupMembers(context.Context, *RemoveGroupMembersRequest) (*GroupOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveGroupMembers not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateGroupNotice(context.Context, *UpdateGroupNoticeRequest) (*GroupOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGroupNotice not implemented")
+}
+func (UnimplementedUserServiceServer) MuteGroupMember(context.Context, *MuteGroupMemberRequest) (*GroupOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MuteGroupMember not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -178,6 +226,60 @@ func _UserService_GetContactStatus_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_RemoveGroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveGroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RemoveGroupMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RemoveGroupMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RemoveGroupMembers(ctx, req.(*RemoveGroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateGroupNotice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateGroupNoticeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateGroupNotice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UpdateGroupNotice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateGroupNotice(ctx, req.(*UpdateGroupNoticeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_MuteGroupMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MuteGroupMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).MuteGroupMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_MuteGroupMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).MuteGroupMember(ctx, req.(*MuteGroupMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -197,6 +299,18 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetContactStatus",
 			Handler:    _UserService_GetContactStatus_Handler,
 		},
+		{
+			MethodName: "RemoveGroupMembers",
+			Handler:    _UserService_RemoveGroupMembers_Handler,
+		},
+		{
+			MethodName: "UpdateGroupNotice",
+			Handler:    _UserService_UpdateGroupNotice_Handler,
+		},
+		{
+			MethodName: "MuteGroupMember",
+			Handler:    _UserService_MuteGroupMember_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/user_service/proto/user.proto",